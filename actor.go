@@ -0,0 +1,68 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotSerialized = errors.New("Instance is not serialized")
+)
+
+// Reply is the outcome of a call queued by Ask
+type Reply struct {
+	Results []interface{}
+	Err     error
+}
+
+// Send queues the call of a method of a serialized instance, see Serialize, and returns
+// without waiting for it. The queued calls run in order, their results are discarded.
+//
+//	f.Serialize(cart)
+//	f.Send("cart.Add", "apple", 2)
+func (f *FuncUtil) Send(methodName string, params ...interface{}) error {
+	return f.enqueue(methodName, params, nil)
+}
+
+// Ask queues the call like Send and returns the channel receiving its reply
+//
+//	reply, _ := f.Ask("cart.Total")
+//	r := <-reply
+func (f *FuncUtil) Ask(methodName string, params ...interface{}) (<-chan Reply, error) {
+	reply := make(chan Reply, 1)
+	if err := f.enqueue(methodName, params, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// enqueue posts the call to the worker of the method, the reply is optional
+func (f *FuncUtil) enqueue(methodName string, params []interface{}, reply chan<- Reply) error {
+	plan, err := f.Bind(methodName)
+	if err != nil {
+		return err
+	}
+	w := plan.ci.worker
+	if w == nil {
+		return ErrNotSerialized
+	}
+	// the plan runs on the worker instead of waiting for it
+	ctx := context.WithValue(context.Background(), workerKey{}, w)
+	job := func() {
+		r := Reply{}
+		defer func() {
+			if p := recover(); p != nil {
+				r.Err = fmt.Errorf("%s: panic: %v", methodName, p)
+			}
+			if reply != nil {
+				reply <- r
+			}
+		}()
+		r.Results, r.Err = plan.InvokeContext(ctx, params...)
+	}
+	if !w.post(job) {
+		return ErrNotSerialized
+	}
+	return nil
+}
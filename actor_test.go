@@ -0,0 +1,61 @@
+package funcutil
+
+import (
+	"testing"
+)
+
+type cart struct {
+	items []string
+}
+
+func (c *cart) Add(item string, n int) {
+	for i := 0; i < n; i++ {
+		c.items = append(c.items, item)
+	}
+}
+
+func (c *cart) Items() []string {
+	return c.items
+}
+
+func (c *cart) Drop() {
+	panic("dropped")
+}
+
+func TestSendAsk(t *testing.T) {
+	f := New()
+	c := &cart{}
+	f.Register(c)
+	if err := f.Send("cart.Add", "apple", 1); err != ErrNotSerialized {
+		t.Errorf("should failed due to not serialized got %v", err)
+	}
+	f.Serialize(c)
+	for _, item := range []string{"apple", "pear", "plum"} {
+		if err := f.Send("cart.Add", item, 2); err != nil {
+			t.Error(err)
+		}
+	}
+	reply, err := f.Ask("cart.Items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := <-reply
+	if r.Err != nil || len(r.Results[0].([]string)) != 6 || r.Results[0].([]string)[2] != "pear" {
+		t.Errorf("Unexpected reply %v", r)
+	}
+	reply, _ = f.Ask("cart.Add", "fig")
+	if r := <-reply; r.Err == nil {
+		t.Error("should failed due to missing argument")
+	}
+	reply, _ = f.Ask("cart.Drop")
+	if r := <-reply; r.Err == nil || r.Err.Error() != "cart.Drop: panic: dropped" {
+		t.Errorf("should failed due to panic got %v", r.Err)
+	}
+	if _, err := f.Ask("cart.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	f.Unserialize(c)
+	if err := f.Send("cart.Add", "apple", 1); err != ErrNotSerialized {
+		t.Errorf("should failed due to not serialized got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package funcutil
+
+import (
+	"strings"
+)
+
+// AliasNamespace lets the callers use alias as the namespace ns, e.g. the short and
+// stable prefixes of the external callers for the internal namespaces. An empty ns
+// removes the alias.
+//
+//	f.AliasNamespace("v1", "com.example.device")
+//	f.Call("v1.Light.On") // calls com.example.device.Light.On
+func (f *FuncUtil) AliasNamespace(alias, ns string) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if ns == "" {
+		delete(f.aliases, alias)
+		return nil
+	}
+	f.aliases[alias] = ns
+	return nil
+}
+
+// unalias rewrites the longest aliased namespace of the method name
+func (f *FuncUtil) unalias(methodName string) (string, bool) {
+	longest := ""
+	for alias := range f.aliases {
+		if len(alias) > len(longest) && strings.HasPrefix(methodName, alias+".") {
+			longest = alias
+		}
+	}
+	if longest == "" {
+		return methodName, false
+	}
+	return f.aliases[longest] + methodName[len(longest):], true
+}
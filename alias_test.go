@@ -0,0 +1,43 @@
+package funcutil
+
+import (
+	"testing"
+)
+
+func TestAliasNamespace(t *testing.T) {
+	f := New("com.example.device")
+	f.Register(&service{})
+	f.AliasNamespace("v1", "com.example.device")
+	f.AliasNamespace("v1.svc", "com.example.device.service")
+	if _, err := f.Call("v1.service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Call("v1.svc.Running"); err != nil || rets[0] != false {
+		t.Errorf("Should be false got %v %v", rets, err)
+	}
+	if mi, exists := f.Info("v1.service.Stop"); !exists || mi.Name != "com.example.device.service.Stop" {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+	if !f.Has("v1.svc.Stop") || f.Has("v2.service.Stop") {
+		t.Error("only the aliases should be resolved")
+	}
+	f.AliasNamespace("v1", "")
+	if _, err := f.Call("v1.service.Stop", true); err != ErrMethodNotFound {
+		t.Error("alias should be removed")
+	}
+	if c := f.Clone(); !c.Has("v1.svc.Stop") {
+		t.Error("clone should keep the aliases")
+	}
+}
+
+func TestAliasNamespaceCycle(t *testing.T) {
+	f := New("a")
+	f.Register(&service{})
+	f.AliasNamespace("a", "a.b")
+	if _, err := f.Call("a.service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("a.x.Stop", true); err != ErrMethodNotFound {
+		t.Errorf("method should not exists got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package funcutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Redacted replaces the redacted values within the audit log
+const Redacted = "[REDACTED]"
+
+// AuditRedactor returns the params as they are written to the audit log
+type AuditRedactor func(methodName string, params []interface{}) []interface{}
+
+// AuditRecord is an entry of the audit log, written as a JSON line
+type AuditRecord struct {
+	Time     time.Time     `json:"time"`
+	Caller   string        `json:"caller,omitempty"`
+	Method   string        `json:"method"`
+	Params   []interface{} `json:"params"`
+	Duration time.Duration `json:"duration"`
+	// Outcome is either "ok" or "error"
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+type auditLogger struct {
+	sync.Mutex
+	w      io.Writer
+	redact AuditRedactor
+}
+
+// WithAuditLogger writes a record of every invocation to w, nil disables the audit log.
+// The caller is the identity of the context, see ContextWithCaller, the duration is in
+// nanoseconds and the failed calls include the error returned by the call or the method.
+// The struct fields tagged `audit:"redact"` are redacted unless the redactor is set
+// by SetAuditRedactor.
+//
+//	{"time":"2019-05-03T10:00:00Z","caller":"alice","method":"users.Login","params":[{"Name":"alice","Password":"[REDACTED]"}],"duration":5120,"outcome":"ok"}
+func (f *FuncUtil) WithAuditLogger(w io.Writer) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if w == nil {
+		f.opts.audit = nil
+		return nil
+	}
+	f.opts.audit = &auditLogger{w: w, redact: redactParams}
+	return nil
+}
+
+// SetAuditRedactor replaces the redaction of the params of the audit log
+func (f *FuncUtil) SetAuditRedactor(redact AuditRedactor) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if f.opts.audit != nil {
+		f.opts.audit.Lock()
+		f.opts.audit.redact = redact
+		f.opts.audit.Unlock()
+	}
+	return nil
+}
+
+// record writes the record of the call started at start, err is read when the call returns
+func (a *auditLogger) record(ctx context.Context, name string, params []interface{}, start time.Time, results []interface{}, err *error) {
+	r := AuditRecord{
+		Time:     start.UTC(),
+		Method:   name,
+		Duration: time.Since(start),
+		Outcome:  "ok",
+	}
+	r.Caller, _ = CallerFromContext(ctx)
+	callErr := *err
+	if callErr == nil {
+		callErr = resultError(results)
+	}
+	if callErr != nil {
+		r.Outcome = "error"
+		r.Error = callErr.Error()
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	r.Params = params
+	if a.redact != nil {
+		r.Params = a.redact(name, params)
+	}
+	line, encErr := json.Marshal(r)
+	if encErr != nil {
+		// keep the record of the values JSON can't encode
+		types := make([]interface{}, len(r.Params))
+		for i, p := range r.Params {
+			types[i] = fmt.Sprintf("%T", p)
+		}
+		r.Params = types
+		line, _ = json.Marshal(r)
+	}
+	a.w.Write(append(line, '\n'))
+}
+
+// redactParams redacts the struct fields tagged `audit:"redact"`, including the ones
+// of the nested structs, slices and maps
+func redactParams(name string, params []interface{}) []interface{} {
+	out := make([]interface{}, len(params))
+	for i, p := range params {
+		out[i] = redactValue(p)
+	}
+	return out
+}
+
+func redactValue(p interface{}) interface{} {
+	v := reflect.ValueOf(p)
+	if !v.IsValid() || !hasRedacted(v.Type(), map[reflect.Type]bool{}) {
+		return p
+	}
+	return redacted(v, 0)
+}
+
+// maxRedactDepth stops the redaction of the cyclic values
+const maxRedactDepth = 32
+
+// redacted returns the value with the tagged fields redacted, the structs become maps
+func redacted(v reflect.Value, depth int) interface{} {
+	if depth > maxRedactDepth {
+		return Redacted
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redacted(v.Elem(), depth+1)
+	case reflect.Struct:
+		t := v.Type()
+		fields := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			if sf.Tag.Get("audit") == "redact" {
+				fields[sf.Name] = Redacted
+				continue
+			}
+			fields[sf.Name] = redacted(v.Field(i), depth+1)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if !hasRedacted(v.Type().Elem(), map[reflect.Type]bool{}) {
+			return v.Interface()
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = redacted(v.Index(i), depth+1)
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if !hasRedacted(v.Type().Elem(), map[reflect.Type]bool{}) {
+			return v.Interface()
+		}
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[fmt.Sprint(iter.Key().Interface())] = redacted(iter.Value(), depth+1)
+		}
+		return entries
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// hasRedacted checks whether the values of type t may hold fields tagged `audit:"redact"`
+func hasRedacted(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasRedacted(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			if sf.Tag.Get("audit") == "redact" || hasRedacted(sf.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loggedArgs returns the converted args as they are written to the audit log and the
+// recorded calls, the context and the injected values are left out
+func (o options) loggedArgs(ci callInfo, args []reflect.Value) []interface{} {
+	logged := make([]interface{}, 0, len(args))
+	for i, arg := range args {
+		t := ci.argTypes[i]
+		if _, injected := o.provider(t); injected || t == contextType {
+			continue
+		}
+		logged = append(logged, arg.Interface())
+	}
+	return logged
+}
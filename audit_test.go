@@ -0,0 +1,95 @@
+package funcutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type Credentials struct {
+	Name     string
+	Password string `audit:"redact"`
+}
+
+type accounts struct {
+}
+
+func (a *accounts) Login(c Credentials) error {
+	if c.Password != "secret" {
+		return errors.New("invalid password")
+	}
+	return nil
+}
+
+func (a *accounts) Import(all map[string][]Credentials) int {
+	return len(all)
+}
+
+func TestWithAuditLogger(t *testing.T) {
+	f := New()
+	f.Register(&accounts{})
+	buf := &bytes.Buffer{}
+	f.WithAuditLogger(buf)
+
+	ctx := ContextWithCaller(context.Background(), "alice")
+	f.CallContext(ctx, "accounts.Login", Credentials{"alice", "secret"})
+	f.Call("accounts.Login", Credentials{"bob", "guess"})
+	f.Call("accounts.NotExists")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Should be 2 got %d", len(lines))
+	}
+	records := make([]AuditRecord, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if records[0].Caller != "alice" || records[0].Method != "accounts.Login" || records[0].Outcome != "ok" {
+		t.Errorf("Unexpected record %s", lines[0])
+	}
+	if strings.Contains(lines[0], "secret") || !strings.Contains(lines[0], Redacted) {
+		t.Errorf("Password should be redacted %s", lines[0])
+	}
+	if records[1].Caller != "" || records[1].Outcome != "error" || records[1].Error != "invalid password" {
+		t.Errorf("Unexpected record %s", lines[1])
+	}
+
+	f.SetAuditRedactor(nil)
+	f.Call("accounts.Login", Credentials{"bob", "guess"})
+	if !strings.Contains(buf.String(), "guess") {
+		t.Error("params should not be redacted")
+	}
+	f.WithAuditLogger(nil)
+	buf.Reset()
+	f.Call("accounts.Login", Credentials{"bob", "guess"})
+	if buf.Len() != 0 {
+		t.Errorf("Should be empty got %s", buf.String())
+	}
+}
+
+func TestAuditRedactConvertedArgs(t *testing.T) {
+	f := New()
+	f.Register(&accounts{})
+	buf := &bytes.Buffer{}
+	f.WithAuditLogger(buf)
+
+	f.CallJSON("accounts.Login", []byte(`[{"Name":"alice","Password":"s3cret"}]`))
+	if strings.Contains(buf.String(), "s3cret") || !strings.Contains(buf.String(), Redacted) {
+		t.Errorf("Password should be redacted %s", buf.String())
+	}
+	buf.Reset()
+	f.Call("accounts.Login", map[string]interface{}{"Name": "alice", "Password": "s3cret"})
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("Password should be redacted %s", buf.String())
+	}
+	buf.Reset()
+	f.Call("accounts.Import", map[string][]Credentials{"staff": {{"bob", "hunter2"}}})
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), "bob") {
+		t.Errorf("Nested password should be redacted %s", buf.String())
+	}
+}
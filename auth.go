@@ -0,0 +1,119 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnauthenticated = errors.New("Unauthenticated")
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	Name  string
+	Roles []string
+}
+
+// Authenticator returns the identity of the credential received by a transport, e.g.
+// the bearer token of an HTTP request
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (Identity, error)
+}
+
+// AuthenticatorFunc is a function implementing Authenticator
+type AuthenticatorFunc func(ctx context.Context, credential string) (Identity, error)
+
+// Authenticate calls fn
+func (fn AuthenticatorFunc) Authenticate(ctx context.Context, credential string) (Identity, error) {
+	return fn(ctx, credential)
+}
+
+// Authorizer returns an error when the identity isn't allowed to call the method, the
+// identity is empty for the unauthenticated calls
+type Authorizer func(ctx context.Context, id Identity, methodName string) error
+
+type identityKey struct{}
+
+// ContextWithIdentity returns a copy of ctx carrying the identity, its name is the
+// caller of the audit log, see ContextWithCaller
+func ContextWithIdentity(ctx context.Context, id Identity) context.Context {
+	return ContextWithCaller(context.WithValue(ctx, identityKey{}, id), id.Name)
+}
+
+// IdentityFromContext returns the identity carried by ctx
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// SetAuthenticator sets the authenticator used by the transports, see Authenticate
+func (f *FuncUtil) SetAuthenticator(a Authenticator) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.authenticator = a
+	return nil
+}
+
+// SetAuthorizer sets the authorizer checking every call made with a context, see
+// CallContext, against the identity it carries
+func (f *FuncUtil) SetAuthorizer(a Authorizer) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.authorizer = a
+	return nil
+}
+
+// Authenticate returns a copy of ctx carrying the identity of the credential, the
+// transports call it before dispatching the requests with CallContext. The error
+// wraps ErrUnauthenticated. Without authenticator ctx is returned as is.
+//
+//	ctx, err := f.Authenticate(r.Context(), r.Header.Get("Authorization"))
+func (f *FuncUtil) Authenticate(ctx context.Context, credential string) (context.Context, error) {
+	f.RLock()
+	a := f.opts.authenticator
+	f.RUnlock()
+	if a == nil {
+		return ctx, nil
+	}
+	id, err := a.Authenticate(ctx, credential)
+	if err != nil {
+		if !errors.Is(err, ErrUnauthenticated) {
+			err = &authError{err}
+		}
+		return ctx, err
+	}
+	return ContextWithIdentity(ctx, id), nil
+}
+
+// authError wraps the errors of the authenticators into ErrUnauthenticated
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string {
+	return ErrUnauthenticated.Error() + ": " + e.err.Error()
+}
+
+func (e *authError) Is(target error) bool {
+	return target == ErrUnauthenticated
+}
+
+func (e *authError) Unwrap() error {
+	return e.err
+}
+
+// authorize checks the call against the identity of ctx
+func (o options) authorize(ctx context.Context, name string) error {
+	if o.authorizer == nil {
+		return nil
+	}
+	id, _ := IdentityFromContext(ctx)
+	return o.authorizer(ctx, id, name)
+}
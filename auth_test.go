@@ -0,0 +1,74 @@
+package funcutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var tokens = AuthenticatorFunc(func(ctx context.Context, credential string) (Identity, error) {
+	switch credential {
+	case "Bearer admin":
+		return Identity{Name: "alice", Roles: []string{"admin"}}, nil
+	case "Bearer user":
+		return Identity{Name: "bob"}, nil
+	}
+	return Identity{}, errors.New("invalid token")
+})
+
+func TestAuthenticate(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	ctx, err := f.Authenticate(context.Background(), "anything")
+	if _, ok := IdentityFromContext(ctx); err != nil || ok {
+		t.Error("should not authenticate without authenticator")
+	}
+	f.SetAuthenticator(tokens)
+	ctx, err = f.Authenticate(context.Background(), "Bearer admin")
+	if id, ok := IdentityFromContext(ctx); err != nil || !ok || id.Name != "alice" {
+		t.Errorf("Should be alice got %v %v", id, err)
+	}
+	if caller, _ := CallerFromContext(ctx); caller != "alice" {
+		t.Errorf("Should be alice got %s", caller)
+	}
+	if _, err := f.Authenticate(context.Background(), "Bearer x"); !errors.Is(err, ErrUnauthenticated) || err.Error() != "Unauthenticated: invalid token" {
+		t.Errorf("should failed due to invalid token got %v", err)
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.SetAuthenticator(tokens)
+	f.SetAuthorizer(func(ctx context.Context, id Identity, methodName string) error {
+		if methodName == "service.Stop" && id.Name != "alice" {
+			return errors.New("Forbidden")
+		}
+		return nil
+	})
+	buf := &bytes.Buffer{}
+	f.WithAuditLogger(buf)
+	admin, _ := f.Authenticate(context.Background(), "Bearer admin")
+	user, _ := f.Authenticate(context.Background(), "Bearer user")
+	if _, err := f.CallContext(admin, "service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.CallContext(user, "service.Stop", true); err == nil || err.Error() != "Forbidden" {
+		t.Errorf("should failed due to forbidden got %v", err)
+	}
+	if _, err := f.Call("service.Stop", true); err == nil {
+		t.Error("should failed due to anonymous caller")
+	}
+	plan, _ := f.Compile("service.Stop")
+	if _, err := plan.InvokeContext(user, true); err == nil {
+		t.Error("should failed due to forbidden plan")
+	}
+	if _, err := f.CallContext(user, "service.Running"); err != nil {
+		t.Error(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 5 || !strings.Contains(lines[1], `"bob"`) || !strings.Contains(lines[1], "Forbidden") {
+		t.Errorf("Unexpected audit %s", buf)
+	}
+}
@@ -0,0 +1,80 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrNotFunc = errors.New("Type must be kind of func")
+)
+
+// BindFunc returns a function of type F calling the registered method, see Bind.
+// F takes the method parameters and returns its results, optionally followed by an
+// error receiving the call errors. Without it the call errors panic.
+//
+//	stop, err := funcutil.BindFunc[func(bool) error](f, "service.Stop")
+//	...
+//	err = stop(true)
+func BindFunc[F any](f *FuncUtil, methodName string) (F, error) {
+	var fn F
+	v, err := f.BindFuncType(reflect.TypeOf((*F)(nil)).Elem(), methodName)
+	if err != nil {
+		return fn, err
+	}
+	return v.Interface().(F), nil
+}
+
+// BindFuncType is the reflect based equivalent of BindFunc, it returns a function
+// of type t calling the registered method
+func (f *FuncUtil) BindFuncType(t reflect.Type, methodName string) (reflect.Value, error) {
+	if t.Kind() != reflect.Func {
+		return reflect.Value{}, ErrNotFunc
+	}
+	plan, err := f.Bind(methodName)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	rets := plan.ci.retTypes
+	// the results of t may be followed by an error receiving the call errors
+	callErr := t.NumOut() == len(rets)+1 && t.Out(len(rets)) == errorType
+	if t.NumOut() != len(rets) && !callErr {
+		return reflect.Value{}, ErrResultsMismatch
+	}
+	for i, rt := range rets {
+		if !rt.AssignableTo(t.Out(i)) {
+			return reflect.Value{}, fmt.Errorf("result %d: %v is not assignable to %v", i, rt, t.Out(i))
+		}
+	}
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		params := make([]interface{}, 0, len(args))
+		for i, arg := range args {
+			if t.IsVariadic() && i == len(args)-1 {
+				for j := 0; j < arg.Len(); j++ {
+					params = append(params, arg.Index(j).Interface())
+				}
+				break
+			}
+			params = append(params, arg.Interface())
+		}
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+		results, err := plan.Invoke(params...)
+		if err != nil {
+			if !callErr {
+				panic(fmt.Sprintf("funcutil: %s: %v", methodName, err))
+			}
+			out[len(rets)] = reflect.ValueOf(&err).Elem()
+			return out
+		}
+		for i, r := range results[:len(rets)] {
+			if r != nil {
+				out[i] = reflect.ValueOf(r).Convert(t.Out(i))
+			}
+		}
+		return out
+	}), nil
+}
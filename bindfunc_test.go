@@ -0,0 +1,64 @@
+package funcutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBindFunc(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &service{})
+	add, err := BindFunc[func(int64, int64) int64](f, "calculator.Add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := add(2, 3); sum != 5 {
+		t.Errorf("Should be 5 got %d", sum)
+	}
+	sum, err := BindFunc[func([]float64, float64) (float64, error)](f, "calculator.Sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := sum([]float64{1, 2}, 2); err != nil || v != 6 {
+		t.Errorf("Should be 6 got %v %v", v, err)
+	}
+	if _, err := sum(nil, 1); err == nil || err.Error() != "no values" {
+		t.Errorf("should failed due to no values got %v", err)
+	}
+	stop, err := BindFunc[func(...interface{}) error](f, "service.Stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(true); err != nil {
+		t.Error(err)
+	}
+	if err := stop(); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to missing argument got %v", err)
+	}
+	describe, _ := BindFunc[func(interface{}) interface{}](f, "calculator.Describe")
+	if d := describe(1); d != "int" {
+		t.Errorf("Should be int got %v", d)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("should panic due to missing argument")
+			}
+		}()
+		add, _ := BindFunc[func(int64) int64](f, "calculator.Add")
+		add(1)
+	}()
+	if _, err := BindFunc[func() string](f, "calculator.Add"); err == nil {
+		t.Error("should failed due to result type")
+	}
+	if _, err := BindFunc[func()](f, "calculator.Add"); err != ErrResultsMismatch {
+		t.Errorf("should failed due to results count got %v", err)
+	}
+	if _, err := f.BindFuncType(reflect.TypeOf(0), "calculator.Add"); err != ErrNotFunc {
+		t.Errorf("should failed due to not func got %v", err)
+	}
+	if _, err := BindFunc[func()](f, "calculator.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,122 @@
+package funcutil
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCircuitOpen = errors.New("Circuit open")
+	// errPanicked is the failure of the panicking calls
+	errPanicked = errors.New("Call panicked")
+)
+
+// BreakerPolicy configures the circuit breaker of a method
+type BreakerPolicy struct {
+	// Threshold is the number of consecutive failures opening the circuit
+	Threshold int
+	// OpenFor is how long the calls fail with ErrCircuitOpen before probing the method
+	OpenFor time.Duration
+	// Probes is the number of calls let through while half-open, they must all
+	// succeed to close the circuit, defaults to 1
+	Probes int
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breaker struct {
+	sync.Mutex
+	policy   BreakerPolicy
+	state    breakerState
+	failures int
+	openedAt time.Time
+	// probes started and succeeded while half-open
+	probes    int
+	successes int
+}
+
+// SetBreaker puts a circuit breaker around the method, after Threshold consecutive
+// failed calls (returning a non nil error) the calls fail with ErrCircuitOpen until
+// OpenFor elapses, then the probe calls decide whether the circuit is closed or
+// opened again. A zero threshold removes the breaker.
+//
+//	f.SetBreaker("payments.Charge", funcutil.BreakerPolicy{Threshold: 5, OpenFor: 30 * time.Second})
+func (f *FuncUtil) SetBreaker(methodName string, policy BreakerPolicy) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.breaker = nil
+	if policy.Threshold > 0 {
+		if policy.Probes <= 0 {
+			policy.Probes = 1
+		}
+		ci.breaker = &breaker{policy: policy}
+	}
+	return f.store(ci)
+}
+
+// clone returns a closed breaker with the same policy
+func (b *breaker) clone() *breaker {
+	if b == nil {
+		return nil
+	}
+	return &breaker{policy: b.policy}
+}
+
+// allow tells whether the call may proceed
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.Lock()
+	defer b.Unlock()
+	if b.state == breakerOpen {
+		if time.Since(b.openedAt) < b.policy.OpenFor {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probes, b.successes = 0, 0
+	}
+	if b.state == breakerHalfOpen {
+		if b.probes >= b.policy.Probes {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// done records the outcome of an allowed call
+func (b *breaker) done(err error) {
+	if b == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	if err != nil {
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.policy.Threshold {
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+		return
+	}
+	b.failures = 0
+	if b.state == breakerHalfOpen {
+		b.successes++
+		if b.successes >= b.policy.Probes {
+			b.state = breakerClosed
+		}
+	}
+}
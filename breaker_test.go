@@ -0,0 +1,62 @@
+package funcutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetBreaker(t *testing.T) {
+	f := New()
+	s := &flaky{failures: 3}
+	f.Register(s)
+	if err := f.SetBreaker("flaky.Fetch", BreakerPolicy{Threshold: 2, OpenFor: 20 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if rets, err := f.Call("flaky.Fetch", 1); err != nil || rets[1] != errTransient {
+			t.Errorf("Should be transient got %v %v", rets, err)
+		}
+	}
+	if _, err := f.Call("flaky.Fetch", 1); err != ErrCircuitOpen {
+		t.Errorf("Should be circuit open got %v", err)
+	}
+	if s.calls != 2 {
+		t.Errorf("Should be 2 calls got %d", s.calls)
+	}
+
+	// the failing probe opens the circuit again
+	time.Sleep(25 * time.Millisecond)
+	if rets, _ := f.Call("flaky.Fetch", 1); rets[1] != errTransient {
+		t.Errorf("Should be transient got %v", rets)
+	}
+	if _, err := f.Call("flaky.Fetch", 1); err != ErrCircuitOpen {
+		t.Errorf("Should be circuit open got %v", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if rets, err := f.Call("flaky.Fetch", 1); err != nil || rets[0] != 1 {
+			t.Errorf("Should be 1 got %v %v", rets, err)
+		}
+	}
+	if err := f.SetBreaker("flaky.NotExists", BreakerPolicy{Threshold: 1}); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown method")
+	}
+}
+
+func TestBreakerPanic(t *testing.T) {
+	f := New()
+	f.Register(&fragile{})
+	f.SetBreaker("fragile.Index", BreakerPolicy{Threshold: 1, OpenFor: time.Minute})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("should panic due to out of range")
+			}
+		}()
+		f.Call("fragile.Index", []int{}, 1)
+	}()
+	if _, err := f.Call("fragile.Index", []int{1, 2}, 1); err != ErrCircuitOpen {
+		t.Errorf("Should be circuit open got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package funcutil
+
+import (
+	"context"
+	"path"
+	"sort"
+)
+
+// CallAll invokes every registered method whose name matches the pattern and
+// accepts the given params, e.g. "*.Shutdown" or "service.*".
+// The pattern syntax is the one of path.Match. Methods are called in name order
+// and their results are returned keyed by method name.
+func (f *FuncUtil) CallAll(pattern string, params ...interface{}) (map[string][]interface{}, error) {
+	// validate the pattern once
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return f.broadcast(func(name string, ci callInfo) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}, params)
+}
+
+// broadcast invokes the selected methods accepting the params in name order
+func (f *FuncUtil) broadcast(selected func(name string, ci callInfo) bool, params []interface{}) (map[string][]interface{}, error) {
+	t := f.published()
+	opts := t.opts
+	calls := map[string]callInfo{}
+	names := []string{}
+	for name, ci := range t.calls {
+		if !selected(name, ci) {
+			continue
+		}
+		// skip the methods that can't take the params
+		if opts.parametersMatch(ci, ci.withDefaults(ci.withContext(context.Background(), params))) != nil {
+			continue
+		}
+		calls[name] = ci
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := map[string][]interface{}{}
+	for _, name := range names {
+		ci := calls[name]
+		var rets []interface{}
+		if n := opts.resultCount(ci); n > 0 {
+			rets = make([]interface{}, n)
+		}
+		if err := opts.invoke(context.Background(), ci, params, rets); err != nil {
+			return results, err
+		}
+		results[name] = rets
+	}
+	return results, nil
+}
@@ -0,0 +1,30 @@
+package funcutil
+
+import "testing"
+
+func TestCallAll(t *testing.T) {
+	f := New()
+	s := &service{}
+	f.Register(s, &Monitor{})
+	results, err := f.CallAll("service.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// service.Stop needs an argument
+	if len(results) != 4 {
+		t.Errorf("Should call 4 methods got %d", len(results))
+	}
+	if _, exists := results["service.Stop"]; exists {
+		t.Error("service.Stop should not be called")
+	}
+	results, _ = f.CallAll("*.Display")
+	if results["Monitor.Display"][0] != "Display()" {
+		t.Error("Should be Display()")
+	}
+	if _, err := f.CallAll("*.Stop", true); err != nil || s.running {
+		t.Error("service should be stopped")
+	}
+	if _, err := f.CallAll("[", true); err == nil {
+		t.Error("should failed due to bad pattern")
+	}
+}
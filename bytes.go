@@ -0,0 +1,79 @@
+package funcutil
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// BytesEncoding tells how the strings supplied for []byte parameters are decoded
+type BytesEncoding int
+
+const (
+	// BytesRaw takes the bytes of the string as they are
+	BytesRaw BytesEncoding = 0
+	// BytesBase64 decodes the standard or URL base64, padded or not
+	BytesBase64 BytesEncoding = 1 << iota
+	// BytesHex decodes the hexadecimal strings
+	BytesHex
+)
+
+var errBytesEncoding = errors.New("string is not encoded as expected by the []byte parameter")
+
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+// SetBytesEncoding sets how the strings supplied for []byte parameters (and elements)
+// are decoded, matching how the transports encode binary data. When combined, e.g.
+// BytesBase64|BytesHex, hex is tried first as the hex strings are valid base64 too.
+// The strings which can't be decoded fail the call. BytesRaw is the default.
+func (f *FuncUtil) SetBytesEncoding(e BytesEncoding) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.bytesEncoding = e
+	return nil
+}
+
+// decodeBytes decodes the strings for the []byte types, reports false when it doesn't apply
+func (o options) decodeBytes(p interface{}, t reflect.Type) (reflect.Value, bool, error) {
+	if o.bytesEncoding == BytesRaw || t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8 {
+		return reflect.Value{}, false, nil
+	}
+	var s string
+	switch v := p.(type) {
+	case string:
+		s = v
+	case json.RawMessage:
+		if len(v) == 0 || v[0] != '"' {
+			return reflect.Value{}, false, nil
+		}
+		if err := json.Unmarshal(v, &s); err != nil {
+			return reflect.Value{}, true, err
+		}
+	default:
+		return reflect.Value{}, false, nil
+	}
+	// the hex strings are valid base64 too
+	if o.bytesEncoding&BytesHex != 0 {
+		if b, err := hex.DecodeString(s); err == nil {
+			return reflect.ValueOf(b).Convert(t), true, nil
+		}
+	}
+	if o.bytesEncoding&BytesBase64 != 0 {
+		for _, enc := range base64Encodings {
+			if b, err := enc.DecodeString(s); err == nil {
+				return reflect.ValueOf(b).Convert(t), true, nil
+			}
+		}
+	}
+	return reflect.Value{}, true, errBytesEncoding
+}
@@ -0,0 +1,52 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type hasher struct {
+}
+
+func (h *hasher) Len(data []byte) int {
+	return len(data)
+}
+
+func TestSetBytesEncoding(t *testing.T) {
+	f := New()
+	f.Register(&hasher{})
+	if rets, _ := f.Call("hasher.Len", "aGVsbG8="); rets[0] != 8 {
+		t.Errorf("Should be 8 got %v", rets[0])
+	}
+	f.SetBytesEncoding(BytesBase64 | BytesHex)
+	tests := []struct {
+		param  interface{}
+		expect int
+	}{
+		{"aGVsbG8=", 5},
+		{"aGVsbG8", 5},
+		{"aGVsbG8_", 6},
+		{"0a1b2c", 3}, // hex wins
+		{"0a1b2c3", 5},
+		{json.RawMessage(`"aGVsbG8="`), 5},
+		{[]byte("aGVsbG8="), 8},
+	}
+	for _, test := range tests {
+		rets, err := f.Call("hasher.Len", test.param)
+		if err != nil {
+			t.Errorf("%v: %v", test.param, err)
+			continue
+		}
+		if rets[0] != test.expect {
+			t.Errorf("%v: Should be %d got %v", test.param, test.expect, rets[0])
+		}
+	}
+	f.SetBytesEncoding(BytesBase64)
+	if rets, _ := f.Call("hasher.Len", "0a1b2c"); rets[0] != 4 {
+		t.Errorf("Should be 4 got %v", rets[0])
+	}
+	f.SetBytesEncoding(BytesHex)
+	if _, err := f.Call("hasher.Len", "not hex"); err == nil {
+		t.Error("should failed due to invalid hex")
+	}
+}
@@ -0,0 +1,13 @@
+package funcutil
+
+import "context"
+
+// Caller invokes the methods by name. It is implemented by FuncUtil and by the
+// recording registry of funcutil/mock, so the code calling the methods can be
+// tested without the real services.
+type Caller interface {
+	Call(methodName string, params ...interface{}) ([]interface{}, error)
+	CallContext(ctx context.Context, methodName string, params ...interface{}) ([]interface{}, error)
+}
+
+var _ Caller = (*FuncUtil)(nil)
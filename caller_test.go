@@ -0,0 +1,16 @@
+package funcutil
+
+import "testing"
+
+func stopService(c Caller) error {
+	_, err := c.Call("service.Stop", true)
+	return err
+}
+
+func TestCaller(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	if err := stopService(f); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,80 @@
+package funcutil
+
+// Snapshot is a saved state of the registered methods, see FuncUtil.Snapshot
+type Snapshot struct {
+	calls map[string]callInfo
+}
+
+func copyCalls(calls map[string]callInfo) map[string]callInfo {
+	c := make(map[string]callInfo, len(calls))
+	for name, ci := range calls {
+		c[name] = ci
+	}
+	return c
+}
+
+// cloneCalls copies the methods with their own state, e.g. their circuit breakers.
+// The workers of the serialized receivers are shared like the receivers, so their
+// calls stay serialized across the registries.
+func cloneCalls(calls map[string]callInfo) map[string]callInfo {
+	c := make(map[string]callInfo, len(calls))
+	for name, ci := range calls {
+		ci.breaker = ci.breaker.clone()
+		ci.limit = ci.limit.clone()
+		ci.pacer = ci.pacer.clone()
+		ci.memo = ci.memo.clone()
+		c[name] = ci
+	}
+	return c
+}
+
+// Clone returns an independent registry with the same methods and options.
+// The receivers are shared, but registering into either registry doesn't affect
+// the other. The circuit breakers, concurrency limits, paced calls and cached results
+// start afresh. Event subscriptions are not cloned.
+func (f *FuncUtil) Clone() *FuncUtil {
+	f.Lock()
+	defer f.Unlock()
+	lazy := map[string]*lazyStruct{}
+	for name, l := range f.lazy {
+		lazy[name] = &lazyStruct{ctor: l.ctor}
+	}
+	defaultVersions := map[string]string{}
+	for name, version := range f.defaultVersions {
+		defaultVersions[name] = version
+	}
+	aliases := map[string]string{}
+	for alias, ns := range f.aliases {
+		aliases[alias] = ns
+	}
+	return &FuncUtil{
+		calls:           cloneCalls(f.calls),
+		lazy:            lazy,
+		ns:              f.ns,
+		opts:            f.opts.clone(),
+		versionPolicy:   f.versionPolicy,
+		defaultVersions: defaultVersions,
+		aliases:         aliases,
+		promoted:        f.promoted,
+		typeNameFormat:  f.typeNameFormat,
+	}
+}
+
+// Snapshot saves the registered methods to be restored later
+func (f *FuncUtil) Snapshot() *Snapshot {
+	f.Lock()
+	defer f.Unlock()
+	return &Snapshot{calls: copyCalls(f.calls)}
+}
+
+// Restore rolls the registered methods back to the snapshot,
+// the snapshot can be restored again
+func (f *FuncUtil) Restore(s *Snapshot) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.calls = copyCalls(s.calls)
+	return nil
+}
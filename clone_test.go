@@ -0,0 +1,114 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	f := New("ns")
+	s := &service{}
+	f.Register(s)
+	f.SetValidator(nil)
+	c := f.Clone()
+	c.Register(&Monitor{})
+	if len(f.Dump()) != 5 || len(c.Dump()) != 6 {
+		t.Errorf("Should be 5 and 6 methods got %d and %d", len(f.Dump()), len(c.Dump()))
+	}
+	c.Call("ns.service.Run")
+	if !s.running {
+		t.Error("receivers should be shared")
+	}
+	if c.opts.validator != nil {
+		t.Error("options should be cloned")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	snap := f.Snapshot()
+	f.Register(&Monitor{})
+	f.SetDefaults("service.Stop", true)
+	f.Restore(snap)
+	if len(f.Dump()) != 5 {
+		t.Errorf("Should be 5 methods got %d", len(f.Dump()))
+	}
+	if _, err := f.Call("service.Stop"); !errors.Is(err, ErrParametersMismatch) {
+		t.Error("defaults should be rolled back")
+	}
+	f.Register(&Monitor{})
+	f.Restore(snap)
+	if _, err := f.Call("Monitor.Display"); err != ErrMethodNotFound {
+		t.Error("snapshot should be restorable again")
+	}
+}
+
+func TestCloneBreaker(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	f.SetBreaker("calculator.Sum", BreakerPolicy{Threshold: 1, OpenFor: time.Minute})
+	f.Call("calculator.Sum", []float64{}, 1)
+	if _, err := f.Call("calculator.Sum", []float64{1}, 1); err != ErrCircuitOpen {
+		t.Fatalf("should failed due to open circuit got %v", err)
+	}
+	c := f.Clone()
+	if _, err := c.Call("calculator.Sum", []float64{1}, 1); err != nil {
+		t.Errorf("Clone circuit should be closed got %v", err)
+	}
+}
+
+func TestCloneConcurrency(t *testing.T) {
+	f := New()
+	g := &gate{entered: make(chan struct{}, 2), open: make(chan struct{})}
+	f.Register(g)
+	f.SetConcurrency("gate.Pass", 1, LimitFailFast)
+	c := f.Clone()
+	done := make(chan error, 2)
+	go func() {
+		_, err := f.Call("gate.Pass")
+		done <- err
+	}()
+	<-g.entered
+	go func() {
+		_, err := c.Call("gate.Pass")
+		done <- err
+	}()
+	<-g.entered
+	close(g.open)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("Clone should have its own limit got %v", err)
+		}
+	}
+}
+
+func TestClonePacer(t *testing.T) {
+	f := New()
+	h := &heartbeat{}
+	f.Register(h)
+	f.Throttle("heartbeat.Beat", time.Minute)
+	f.Call("heartbeat.Beat")
+	c := f.Clone()
+	if _, err := c.Call("heartbeat.Beat"); err != nil {
+		t.Errorf("Clone should not be throttled got %v", err)
+	}
+	if _, err := f.Call("heartbeat.Beat"); err != ErrThrottled {
+		t.Errorf("should failed due to throttle got %v", err)
+	}
+}
+
+func TestCloneWorker(t *testing.T) {
+	f := New()
+	m := &Monitor{}
+	f.Register(m)
+	f.Serialize(m)
+	defer f.Unserialize(m)
+	c := f.Clone()
+	fci, _, _ := f.resolve("Monitor.Display")
+	cci, _, _ := c.resolve("Monitor.Display")
+	if fci.worker == nil || fci.worker != cci.worker {
+		t.Error("the worker of the shared receiver should be shared")
+	}
+}
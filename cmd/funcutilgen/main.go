@@ -0,0 +1,241 @@
+// Command funcutilgen generates reflection free dispatchers for funcutil.
+//
+// It scans the Go source in a package directory for the given struct types and
+// emits a FuncutilDispatch method for each of them. funcutil.FuncUtil.Call uses the
+// generated dispatcher for the calls that don't need argument conversion and
+// falls back to reflection for everything else, so the semantics stay the same.
+//
+//		//go:generate funcutilgen -type=service,Monitor
+//
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type method struct {
+	name    string
+	params  []string
+	results int
+}
+
+type generator struct {
+	fset    *token.FileSet
+	pkg     string
+	methods map[string][]method
+	imports map[string]string
+}
+
+func (g *generator) typeString(file *ast.File, expr ast.Expr) string {
+	// keep the imports referenced by the type
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			for _, imp := range file.Imports {
+				path, _ := strconv.Unquote(imp.Path.Value)
+				name := filepath.Base(path)
+				if imp.Name != nil {
+					name = imp.Name.Name
+				}
+				if name == id.Name {
+					g.imports[path] = name
+				}
+			}
+		}
+		return false
+	})
+	b := &bytes.Buffer{}
+	printer.Fprint(b, g.fset, expr)
+	return b.String()
+}
+
+func receiverName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) != 1 {
+		return ""
+	}
+	t := fd.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func fieldCount(fl *ast.FieldList) int {
+	if fl == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range fl.List {
+		if len(f.Names) == 0 {
+			n++
+		}
+		n += len(f.Names)
+	}
+	return n
+}
+
+func (g *generator) collect(file *ast.File, types map[string]bool) {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || !fd.Name.IsExported() || fd.Name.Name == "FuncutilDispatch" {
+			continue
+		}
+		recv := receiverName(fd)
+		if !types[recv] {
+			continue
+		}
+		m := method{
+			name:    fd.Name.Name,
+			results: fieldCount(fd.Type.Results),
+		}
+		variadic := false
+		for _, p := range fd.Type.Params.List {
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				variadic = true
+			}
+			n := len(p.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				m.params = append(m.params, g.typeString(file, p.Type))
+			}
+		}
+		// variadic methods are left to reflection
+		if variadic {
+			continue
+		}
+		g.methods[recv] = append(g.methods[recv], m)
+	}
+}
+
+func (g *generator) generate(types []string) ([]byte, error) {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "// Code generated by funcutilgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n\n", g.pkg)
+	if len(g.imports) > 0 {
+		paths := []string{}
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(b, "import (\n")
+		for _, path := range paths {
+			if name := g.imports[path]; name != filepath.Base(path) {
+				fmt.Fprintf(b, "%s ", name)
+			}
+			fmt.Fprintf(b, "%q\n", path)
+		}
+		fmt.Fprintf(b, ")\n\n")
+	}
+	for _, t := range types {
+		fmt.Fprintf(b, "// FuncutilDispatch calls the methods of %s without reflection\n", t)
+		fmt.Fprintf(b, "func (r *%s) FuncutilDispatch(method string, params []interface{}) ([]interface{}, bool) {\n", t)
+		fmt.Fprintf(b, "switch method {\n")
+		for _, m := range g.methods[t] {
+			fmt.Fprintf(b, "case %q:\n", m.name)
+			fmt.Fprintf(b, "if len(params) != %d {\nreturn nil, false\n}\n", len(m.params))
+			args := []string{}
+			for i, p := range m.params {
+				fmt.Fprintf(b, "p%d, ok := params[%d].(%s)\nif !ok {\nreturn nil, false\n}\n", i, i, p)
+				args = append(args, fmt.Sprintf("p%d", i))
+			}
+			call := fmt.Sprintf("r.%s(%s)", m.name, strings.Join(args, ", "))
+			if m.results == 0 {
+				fmt.Fprintf(b, "%s\nreturn nil, true\n", call)
+				continue
+			}
+			rets := []string{}
+			for i := 0; i < m.results; i++ {
+				rets = append(rets, fmt.Sprintf("r%d", i))
+			}
+			fmt.Fprintf(b, "%s := %s\n", strings.Join(rets, ", "), call)
+			fmt.Fprintf(b, "return []interface{}{%s}, true\n", strings.Join(rets, ", "))
+		}
+		fmt.Fprintf(b, "}\nreturn nil, false\n}\n\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// Generate parses the package in dir and returns the dispatcher source for types
+func Generate(dir string, types []string) ([]byte, error) {
+	g := &generator{
+		fset:    token.NewFileSet(),
+		methods: map[string][]method{},
+		imports: map[string]string{},
+	}
+	pkgs, err := parser.ParseDir(g.fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, t := range types {
+		wanted[t] = true
+	}
+	for name, pkg := range pkgs {
+		g.pkg = name
+		for _, file := range pkg.Files {
+			g.collect(file, wanted)
+		}
+	}
+	if g.pkg == "" {
+		return nil, fmt.Errorf("no Go package found in %s", dir)
+	}
+	for _, t := range types {
+		if _, exists := g.methods[t]; !exists {
+			return nil, fmt.Errorf("no exported methods found for %s", t)
+		}
+		sort.Slice(g.methods[t], func(i, j int) bool {
+			return g.methods[t][i].name < g.methods[t][j].name
+		})
+	}
+	return g.generate(types)
+}
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names")
+	output := flag.String("output", "", "output file name; default <dir>/<type>_funcutil.go")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	src, err := Generate(dir, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_funcutil.go")
+	}
+	if err := ioutil.WriteFile(name, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
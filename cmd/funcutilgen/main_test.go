@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate("testdata", []string{"service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(src)
+	for _, expect := range []string{
+		"func (r *service) FuncutilDispatch(",
+		`case "Run":`,
+		`case "Stop":`,
+		"p1, ok := params[1].(time.Duration)",
+		"r0, r1 := r.Info()",
+		`"time"`,
+	} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("Should contain %s", expect)
+		}
+	}
+	for _, unexpect := range []string{`case "Log":`, `case "internal":`, `"fmt"`} {
+		if strings.Contains(out, unexpect) {
+			t.Errorf("Should not contain %s", unexpect)
+		}
+	}
+	if _, err := Generate("testdata", []string{"missing"}); err == nil {
+		t.Error("should failed due to missing type")
+	}
+}
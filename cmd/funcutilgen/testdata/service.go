@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+type service struct {
+	running bool
+	timeout time.Duration
+}
+
+func (s *service) Run() {
+	s.running = true
+}
+
+func (s *service) Stop(wait bool, timeout time.Duration) {
+	s.running = false
+	s.timeout = timeout
+}
+
+func (s service) Info() (string, error) {
+	return fmt.Sprintf("Running: %v", s.running), nil
+}
+
+func (s *service) Log(args ...string) {
+}
+
+func (s *service) internal() {
+}
@@ -0,0 +1,166 @@
+package funcutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Codec encodes the calls carried by the transports
+type Codec interface {
+	// ContentType is the MIME type of the encoding
+	ContentType() string
+	// Marshal encodes a value, e.g. the results of a call
+	Marshal(v interface{}) ([]byte, error)
+	// UnmarshalParams decodes an array of params, they are converted into
+	// the parameter types by Call
+	UnmarshalParams(data []byte) ([]interface{}, error)
+}
+
+type jsonCodec struct {
+}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// UnmarshalParams splits a JSON array into raw params decoded later into the parameter types
+func (jsonCodec) UnmarshalParams(data []byte) ([]interface{}, error) {
+	var raws []json.RawMessage
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, fmt.Errorf("arguments: %v", err)
+		}
+	}
+	params := make([]interface{}, len(raws))
+	for i, raw := range raws {
+		params[i] = raw
+	}
+	return params, nil
+}
+
+// JSONCodec encodes the calls as JSON arrays
+var JSONCodec Codec = jsonCodec{}
+
+var (
+	codecsMu sync.Mutex
+	codecs   = map[string]Codec{}
+)
+
+// RegisterCodec makes the codec available to LookupCodec by its content type
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[c.ContentType()] = c
+}
+
+// LookupCodec returns the codec registered for the content type
+func LookupCodec(contentType string) (Codec, bool) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	c, exists := codecs[contentType]
+	return c, exists
+}
+
+type textCodec struct {
+}
+
+func (textCodec) ContentType() string {
+	return "text/plain"
+}
+
+// Marshal writes the elements of the slices one per line and the other values as is
+func (textCodec) Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return []byte(fmt.Sprint(v)), nil
+	}
+	b := &bytes.Buffer{}
+	for i := 0; i < rv.Len(); i++ {
+		fmt.Fprintln(b, rv.Index(i).Interface())
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalParams takes every line as a string param
+func (textCodec) UnmarshalParams(data []byte) ([]interface{}, error) {
+	text := strings.TrimRight(string(data), "\r\n")
+	if text == "" {
+		return nil, nil
+	}
+	params := []interface{}{}
+	for _, line := range strings.Split(text, "\n") {
+		params = append(params, strings.TrimSuffix(line, "\r"))
+	}
+	return params, nil
+}
+
+// TextCodec encodes the params and results as lines of text
+var TextCodec Codec = textCodec{}
+
+func init() {
+	RegisterCodec(JSONCodec)
+	RegisterCodec(TextCodec)
+}
+
+// ResultValues returns the results with the errors replaced by their message (or nil),
+// so they can be encoded by any codec
+func ResultValues(rets []interface{}) []interface{} {
+	out := make([]interface{}, len(rets))
+	for i, ret := range rets {
+		if err, ok := ret.(error); ok {
+			out[i] = err.Error()
+			continue
+		}
+		out[i] = ret
+	}
+	return out
+}
+
+// CallCodec invokes the method with the params decoded by the codec and returns the
+// results encoded by the codec, see ResultValues. A failing call or a non nil error
+// result is returned as error.
+func (f *FuncUtil) CallCodec(c Codec, methodName string, payload []byte) ([]byte, error) {
+	return f.CallCodecContext(context.Background(), c, methodName, payload)
+}
+
+// CallCodecContext is CallCodec within the context, see CallContext
+func (f *FuncUtil) CallCodecContext(ctx context.Context, c Codec, methodName string, payload []byte) ([]byte, error) {
+	params, err := c.UnmarshalParams(payload)
+	if err != nil {
+		return nil, err
+	}
+	rets, err := f.CallContext(ctx, methodName, params...)
+	if err != nil {
+		return nil, err
+	}
+	out, encErr := c.Marshal(ResultValues(rets))
+	if encErr != nil {
+		return nil, encErr
+	}
+	return out, resultError(rets)
+}
+
+// CallJSON invokes the method with the params decoded from a JSON array, each of them
+// decoded into its parameter type, and returns the results encoded as a JSON array.
+// Error results are encoded as their message (or null), a failing call or a non nil
+// error result is returned as error.
+//
+//	out, err := f.CallJSON("service.Stop", []byte(`[true]`))
+func (f *FuncUtil) CallJSON(methodName string, payload []byte) ([]byte, error) {
+	return f.CallCodec(JSONCodec, methodName, payload)
+}
+
+// EncodeJSONResults encodes the results of a call as a JSON array,
+// error results are encoded as their message or null
+func EncodeJSONResults(rets []interface{}) ([]byte, error) {
+	return JSONCodec.Marshal(ResultValues(rets))
+}
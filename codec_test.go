@@ -0,0 +1,101 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type calculator struct {
+}
+
+func (c *calculator) Add(a, b int64) int64 {
+	return a + b
+}
+
+func (c *calculator) Sum(values []float64, scale *float64) (float64, error) {
+	if len(values) == 0 {
+		return 0, errors.New("no values")
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum * *scale, nil
+}
+
+func (c *calculator) Describe(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
+
+func TestCallJSON(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &profiles{})
+	tests := []struct {
+		method  string
+		payload string
+		expect  string
+	}{
+		{"calculator.Add", `[9007199254740993, 1]`, `[9007199254740994]`},
+		{"calculator.Sum", `[[1, 2.5], 2]`, `[7,null]`},
+		{"calculator.Describe", `[{"a": 1}]`, `["map[string]interface {}"]`},
+		{"profiles.Save", `[{"nick": "gopher"}]`, `[]`},
+	}
+	for _, test := range tests {
+		out, err := f.CallJSON(test.method, []byte(test.payload))
+		if err != nil {
+			t.Errorf("%s: %v", test.method, err)
+			continue
+		}
+		if string(out) != test.expect {
+			t.Errorf("%s: Should be %s got %s", test.method, test.expect, out)
+		}
+	}
+	out, err := f.CallJSON("calculator.Sum", []byte(`[[], 1]`))
+	if err == nil || err.Error() != "no values" || string(out) != `[0,"no values"]` {
+		t.Errorf("Should fail with no values got %s %v", out, err)
+	}
+	if _, err := f.CallJSON("calculator.Add", []byte(`{}`)); err == nil {
+		t.Error("should failed due to non array payload")
+	}
+	if _, err := f.CallJSON("calculator.Add", []byte(`["a", 1]`)); err == nil {
+		t.Error("should failed due to wrong argument type")
+	}
+}
+
+func TestEncodeJSONResults(t *testing.T) {
+	out, err := EncodeJSONResults([]interface{}{1.5, nil, errors.New("no values")})
+	if err != nil || string(out) != `[1.5,null,"no values"]` {
+		t.Errorf("Should be [1.5,null,\"no values\"] got %s %v", out, err)
+	}
+}
+
+func TestLookupCodec(t *testing.T) {
+	c, exists := LookupCodec("application/json")
+	if !exists || c != JSONCodec {
+		t.Error("json codec should be registered")
+	}
+	if _, exists := LookupCodec("text/html"); exists {
+		t.Error("html codec should not be registered")
+	}
+	values := ResultValues([]interface{}{1, errors.New("failed"), nil})
+	if values[0] != 1 || values[1] != "failed" || values[2] != nil {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestTextCodec(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	out, err := f.CallCodec(TextCodec, "calculator.Describe", []byte("hello\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "string\n" {
+		t.Errorf("Should be string got %q", out)
+	}
+	out, _ = TextCodec.Marshal([]interface{}{1, nil, "a"})
+	if string(out) != "1\n<nil>\na\n" {
+		t.Errorf("Unexpected text %q", out)
+	}
+}
@@ -0,0 +1,35 @@
+package funcutil
+
+import (
+	"strings"
+)
+
+// Candidate is a method completing a prefix
+type Candidate struct {
+	Name string
+	// Params are the hints of the parameters: "name type" when the parameters are
+	// named, see SetParamNames, their types otherwise
+	Params []string
+	Doc    string
+}
+
+// Complete returns the methods whose names start with prefix sorted by name, so the
+// shells can complete the method names and show their parameters
+func (f *FuncUtil) Complete(prefix string) []Candidate {
+	candidates := []Candidate{}
+	f.Range(func(name string, mi MethodInfo) bool {
+		if !strings.HasPrefix(name, prefix) {
+			return true
+		}
+		params := make([]string, len(mi.Params))
+		for i, t := range mi.Params {
+			params[i] = t.String()
+			if len(mi.ParamNames) == len(mi.Params) {
+				params[i] = mi.ParamNames[i] + " " + params[i]
+			}
+		}
+		candidates = append(candidates, Candidate{Name: name, Params: params, Doc: mi.Doc})
+		return true
+	})
+	return candidates
+}
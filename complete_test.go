@@ -0,0 +1,30 @@
+package funcutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &calculator{})
+	f.SetParamNames("calculator.Add", "a", "b")
+	f.SetDoc("calculator.Add", "Add sums a and b")
+	candidates := f.Complete("calculator.")
+	if len(candidates) != 3 {
+		t.Fatalf("Should be 3 got %v", candidates)
+	}
+	add := Candidate{Name: "calculator.Add", Params: []string{"a int64", "b int64"}, Doc: "Add sums a and b"}
+	if !reflect.DeepEqual(candidates[0], add) {
+		t.Errorf("Unexpected %+v", candidates[0])
+	}
+	if c := candidates[1]; c.Name != "calculator.Describe" || !reflect.DeepEqual(c.Params, []string{"interface {}"}) {
+		t.Errorf("Unexpected %+v", c)
+	}
+	if c := f.Complete("service.St"); len(c) != 1 || c[0].Name != "service.Stop" {
+		t.Errorf("Should be service.Stop got %v", c)
+	}
+	if c := f.Complete("x"); len(c) != 0 {
+		t.Errorf("Should be empty got %v", c)
+	}
+}
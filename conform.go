@@ -0,0 +1,89 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Conforms checks the registry exposes the expected methods with their signatures,
+// in the format of Dump with the types named like reflect does, e.g. []int or
+// *funcutil.Point, at boot time. It returns ErrNonConforming listing the missing and
+// mismatched methods. The spaces of the signatures are ignored.
+//
+//	err := f.Conforms(map[string]string{
+//		"service.Stop": "service.Stop(bool)",
+//		"calculator.Add": "calculator.Add(int64,int64) int64",
+//		"calculator.Sum": "calculator.Sum([]float64,*float64) (float64,error)",
+//	})
+func (f *FuncUtil) Conforms(expected map[string]string) error {
+	names := []string{}
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f.Lock()
+	defer f.Unlock()
+	e := ErrNonConforming{}
+	for _, name := range names {
+		ci, err := f.lookup(name)
+		if err != nil {
+			e.Missing = append(e.Missing, name)
+			continue
+		}
+		// the versioned and aliased methods are signed with the name looked up
+		sig := typeSignature(name, ci)
+		if stripSpaces(sig) != stripSpaces(expected[name]) {
+			e.Mismatched = append(e.Mismatched, sig)
+		}
+	}
+	if len(e.Missing) > 0 || len(e.Mismatched) > 0 {
+		return e
+	}
+	return nil
+}
+
+// ConformsTo checks the registry exposes the methods of the interface I named
+// <prefix>.<method> with the same parameters and results, see Conforms
+//
+//	funcutil.ConformsTo[Runner](f, "service")
+func ConformsTo[I any](f *FuncUtil, prefix string) error {
+	it := reflect.TypeOf((*I)(nil)).Elem()
+	if it.Kind() != reflect.Interface {
+		return ErrNotInterface
+	}
+	expected := map[string]string{}
+	for i := 0; i < it.NumMethod(); i++ {
+		m := it.Method(i)
+		name := prefix + "." + m.Name
+		expected[name] = typeSignature(name, callInfo{
+			argTypes: f.getArgumentTypes(m.Type),
+			retTypes: f.getReturnTypes(m.Type),
+		})
+	}
+	return f.Conforms(expected)
+}
+
+// typeSignature returns the signature of the method with the full names of the types,
+// unlike generateSignature the unnamed types like []int are told apart
+func typeSignature(name string, ci callInfo) string {
+	args := make([]string, len(ci.argTypes))
+	for i, t := range ci.argTypes {
+		args[i] = t.String()
+	}
+	rets := make([]string, len(ci.retTypes))
+	for i, t := range ci.retTypes {
+		rets[i] = t.String()
+	}
+	ret := strings.Join(rets, ",")
+	if len(rets) > 1 {
+		ret = "(" + ret + ")"
+	}
+	return fmt.Sprintf("%s(%s) %s", name, strings.Join(args, ","), ret)
+}
+
+func stripSpaces(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
@@ -0,0 +1,68 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+type stopper interface {
+	Stop(wait bool)
+	Running() bool
+}
+
+type calculating interface {
+	Add(a, b int) int
+}
+
+type summing interface {
+	Sum(values []float64, scale *float64) (float64, error)
+}
+
+type intSumming interface {
+	Sum(values []int, scale *float64) (float64, error)
+}
+
+func TestConforms(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &calculator{})
+	err := f.Conforms(map[string]string{
+		"service.Stop":   "service.Stop(bool) ",
+		"calculator.Add": "calculator.Add(int64, int64) int64",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = f.Conforms(map[string]string{
+		"service.Stop":    "service.Stop()",
+		"service.Restart": "service.Restart()",
+	})
+	var e ErrNonConforming
+	if !errors.As(err, &e) || len(e.Missing) != 1 || len(e.Mismatched) != 1 {
+		t.Fatalf("should failed due to missing and mismatched methods got %v", err)
+	}
+	if err.Error() != "contract: missing service.Restart; mismatched service.Stop(bool) " {
+		t.Errorf("Should be missing and mismatched got %s", err)
+	}
+	if err := ConformsTo[stopper](f, "service"); err != nil {
+		t.Error(err)
+	}
+	if err := ConformsTo[calculating](f, "calculator"); err == nil {
+		t.Error("should failed due to mismatched calculator.Add")
+	}
+	if err := ConformsTo[summing](f, "calculator"); err != nil {
+		t.Error(err)
+	}
+	if err := ConformsTo[intSumming](f, "calculator"); err == nil {
+		t.Error("should failed due to mismatched slice type")
+	}
+	err = f.Conforms(map[string]string{"calculator.Sum": "calculator.Sum([]float64, *float64) (float64, error)"})
+	if err != nil {
+		t.Error(err)
+	}
+	if err := f.Conforms(map[string]string{"calculator.Sum": "calculator.Sum([]int, *float64) (float64, error)"}); err == nil {
+		t.Error("should failed due to mismatched slice type")
+	}
+	if err := ConformsTo[service](f, "service"); err != ErrNotInterface {
+		t.Errorf("should failed due to not an interface got %v", err)
+	}
+}
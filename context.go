@@ -0,0 +1,61 @@
+package funcutil
+
+import (
+	"context"
+	"reflect"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+type callerKey struct{}
+
+// ContextWithCaller returns a copy of ctx carrying the identity of the caller, see WithAuditLogger
+func ContextWithCaller(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, callerKey{}, identity)
+}
+
+// CallerFromContext returns the identity of the caller carried by ctx
+func CallerFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(callerKey{}).(string)
+	return identity, ok
+}
+
+// CallContext invokes the method like Call within the context.
+// The methods taking a context.Context as first parameter receive ctx unless
+// the caller supplies it.
+//
+//	// func (s *users) Find(ctx context.Context, id int) *User
+//	f.CallContext(ctx, "users.Find", 42)
+func (f *FuncUtil) CallContext(ctx context.Context, methodName string, params ...interface{}) ([]interface{}, error) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return nil, opts.mapError(methodName, err)
+	}
+	return opts.call(ctx, ci, params)
+}
+
+// call invokes the resolved method within ctx and returns its results
+func (o options) call(ctx context.Context, ci callInfo, params []interface{}) ([]interface{}, error) {
+	n := o.resultCount(ci)
+	if n == 0 {
+		return nil, o.invoke(ctx, ci, params, nil)
+	}
+	results := make([]interface{}, n)
+	if err := o.invoke(ctx, ci, params, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// withContext prepends ctx to the params of the methods taking a context
+func (mi *callInfo) withContext(ctx context.Context, params []interface{}) []interface{} {
+	if len(mi.argTypes) == 0 || mi.argTypes[0] != contextType {
+		return params
+	}
+	if len(params) > 0 {
+		if _, ok := params[0].(context.Context); ok {
+			return params
+		}
+	}
+	return append([]interface{}{ctx}, params...)
+}
@@ -0,0 +1,37 @@
+package funcutil
+
+import (
+	"context"
+	"testing"
+)
+
+type session struct {
+}
+
+func (s *session) Whoami(ctx context.Context, prefix string) string {
+	caller, _ := CallerFromContext(ctx)
+	return prefix + caller
+}
+
+func TestCallContext(t *testing.T) {
+	f := New()
+	f.Register(&session{})
+	ctx := ContextWithCaller(context.Background(), "alice")
+	rets, err := f.CallContext(ctx, "session.Whoami", "user:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rets[0] != "user:alice" {
+		t.Errorf("Should be user:alice got %v", rets[0])
+	}
+	rets, err = f.Call("session.Whoami", ContextWithCaller(context.Background(), "bob"), "user:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rets[0] != "user:bob" {
+		t.Errorf("Should be user:bob got %v", rets[0])
+	}
+	if rets, _ = f.Call("session.Whoami", "user:"); rets[0] != "user:" {
+		t.Errorf("Should be user: got %v", rets[0])
+	}
+}
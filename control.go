@@ -0,0 +1,122 @@
+package funcutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotSocket   = errors.New("Path exists and is not a socket")
+	ErrSocketInUse = errors.New("Socket is served by another process")
+)
+
+// ServeControlSocket serves the registry on the unix socket at path with a line
+// protocol, so the local tools can call the methods of a running process:
+//
+//	$ echo 'service.Stop true' | socat - UNIX-CONNECT:/run/app.sock
+//	OK []
+//
+// Every line is a command as parsed by ParseCommand, answered by a line holding OK
+// and the results as JSON, see ResultValues, or ERR and the error message. A stale
+// socket file is removed, it fails with ErrSocketInUse when the socket is still served
+// and ErrNotSocket when path is another file. The socket is only accessible by the
+// owner of the process, so the commands aren't authenticated. The returned closer
+// stops serving.
+func (f *FuncUtil) ServeControlSocket(path string) (io.Closer, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, ErrNotSocket
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, ErrSocketInUse
+		}
+		os.Remove(path)
+	}
+	// the socket is created within a private directory then linked once restricted,
+	// so it is never accessible by the other users
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".control")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	// unlike a rename, the link doesn't replace a file created meanwhile
+	if err := os.Link(tmp, path); err != nil {
+		l.Close()
+		return nil, err
+	}
+	go f.ServeControl(l)
+	return &controlSocket{Listener: l, path: path}, nil
+}
+
+// controlSocket removes the socket file when closed
+type controlSocket struct {
+	net.Listener
+	path string
+}
+
+func (s *controlSocket) Close() error {
+	err := s.Listener.Close()
+	os.Remove(s.path)
+	return err
+}
+
+// ServeControl serves the line protocol of ServeControlSocket on the connections of
+// the listener until it is closed. The commands aren't authenticated, the listener
+// must only be reachable by the trusted local users.
+func (f *FuncUtil) ServeControl(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			f.serveControlConn(conn, conn)
+		}()
+	}
+}
+
+// serveControlConn answers the commands read from r until it is exhausted
+func (f *FuncUtil) serveControlConn(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, ReplyLine(f.Exec(line))); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ReplyLine formats the outcome of a command as a reply of the line protocol, see
+// ServeControlSocket
+func ReplyLine(rets []interface{}, err error) string {
+	if err == nil {
+		var b []byte
+		if b, err = json.Marshal(ResultValues(rets)); err == nil {
+			return "OK " + string(b)
+		}
+	}
+	return "ERR " + strings.Replace(err.Error(), "\n", " ", -1)
+}
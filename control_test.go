@@ -0,0 +1,116 @@
+package funcutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeControl(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &service{})
+	in := strings.NewReader("calculator.Add 40 2\n\nservice.Stop true\ncalculator.Sum [] 1\ncalculator.NotExists\nservice.Stop \"open\n")
+	out := &bytes.Buffer{}
+	if err := f.serveControlConn(in, out); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"OK [42]",
+		"OK []",
+		`OK [0,"no values"]`,
+		"ERR " + ErrMethodNotFound.Error(),
+		"ERR " + ErrUnbalancedQuotes.Error(),
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unexpected replies %q", lines)
+	}
+}
+
+func TestServeControlSocket(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	path := filepath.Join(t.TempDir(), "control.sock")
+	closer, err := f.ServeControlSocket(path)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer closer.Close()
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("Should be 0600 got %v %v", fi.Mode(), err)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for i := 0; i < 2; i++ {
+		fmt.Fprintf(conn, "calculator.Add %d 2\n", i)
+		if line, _ := r.ReadString('\n'); line != fmt.Sprintf("OK [%d]\n", i+2) {
+			t.Errorf("Should be OK [%d] got %q", i+2, line)
+		}
+	}
+}
+
+func TestServeControlSocketClose(t *testing.T) {
+	f := New()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "control.sock")
+	closer, err := f.ServeControlSocket(path)
+	if err != nil {
+		t.Skip(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Should be only the socket got %v", entries)
+	}
+	closer.Close()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("Should be removed got %v", err)
+	}
+}
+
+func TestServeControlSocketExisting(t *testing.T) {
+	f := New()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, []byte("data"), 0644)
+	if _, err := f.ServeControlSocket(file); err != ErrNotSocket {
+		t.Errorf("Should be ErrNotSocket got %v", err)
+	}
+	if b, _ := os.ReadFile(file); string(b) != "data" {
+		t.Error("Should keep the file")
+	}
+
+	path := filepath.Join(dir, "control.sock")
+	closer, err := f.ServeControlSocket(path)
+	if err != nil {
+		t.Skip(err)
+	}
+	if _, err := f.ServeControlSocket(path); err != ErrSocketInUse {
+		t.Errorf("Should be ErrSocketInUse got %v", err)
+	}
+	if conn, err := net.Dial("unix", path); err != nil {
+		t.Errorf("Should still be served got %v", err)
+	} else {
+		conn.Close()
+	}
+	closer.Close()
+
+	// a stale socket is replaced
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	closer, err = f.ServeControlSocket(path)
+	if err != nil {
+		t.Fatalf("Should replace the stale socket got %v", err)
+	}
+	closer.Close()
+}
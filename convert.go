@@ -0,0 +1,417 @@
+package funcutil
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Converter converts a parameter value into the type it is registered for.
+// Returning SkipConversion leaves the value to the built-in conversions.
+type Converter func(v interface{}) (interface{}, error)
+
+// SkipConversion is returned by a Converter which doesn't handle the given value
+var SkipConversion = errors.New("skip this conversion")
+
+// RegisterConverter registers the converter used for the parameters (and their
+// elements) of type t whenever the supplied value is of a different type
+//
+//	f.RegisterConverter(reflect.TypeOf(ID(0)), func(v interface{}) (interface{}, error) {
+//		return ParseID(v.(string))
+//	})
+func (f *FuncUtil) RegisterConverter(t reflect.Type, c Converter) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts = f.opts.clone()
+	f.opts.converters[t] = c
+	return nil
+}
+
+// defaultConverters returns the built-in converters of every registry
+func defaultConverters() map[reflect.Type]Converter {
+	return map[reflect.Type]Converter{
+		reflect.TypeOf(time.Time{}):      timeConverter,
+		reflect.TypeOf(time.Duration(0)): durationConverter,
+		readerType:                       readerConverter,
+	}
+}
+
+// timeConverter parses RFC3339 strings into time.Time
+func timeConverter(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, SkipConversion
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// durationConverter parses strings like "1h30m" into time.Duration
+func durationConverter(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, SkipConversion
+	}
+	return time.ParseDuration(s)
+}
+
+// parametersMatch verifies the params can be passed to the method
+func (o options) parametersMatch(ci callInfo, params []interface{}) error {
+	if len(params) != len(ci.argTypes) {
+		return ErrArgCount{Want: len(ci.argTypes), Got: len(params)}
+	}
+	for i, t := range ci.argTypes {
+		if _, err := o.argValue(params[i], t); err != nil {
+			return argTypeError(i, params[i], t, err)
+		}
+	}
+	return nil
+}
+
+// convertArgs appends the params converted into the method argument types to args
+func (o options) convertArgs(ci callInfo, params []interface{}, args []reflect.Value) ([]reflect.Value, error) {
+	for i, p := range params {
+		v, err := o.argValue(p, ci.argTypes[i])
+		if err != nil {
+			return args, argTypeError(i, p, ci.argTypes[i], err)
+		}
+		if o.logger != nil {
+			o.logConversion(ci.name, i, p, v)
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+// argValue returns the value of p as type t, converting it if they are convertible
+func (o options) argValue(p interface{}, t reflect.Type) (reflect.Value, error) {
+	if p == nil {
+		if !isNilable(t) {
+			return reflect.Value{}, fmt.Errorf("arguments: nil is not convertible to %v", t)
+		}
+		return reflect.Zero(t), nil
+	}
+	if o.mode == Strict {
+		return o.strictValue(p, t)
+	}
+	pt := reflect.TypeOf(p)
+	v := reflect.ValueOf(p)
+	if pt == t {
+		return v, nil
+	}
+	if c, exists := o.converters[t]; exists {
+		cv, err := c(p)
+		switch {
+		case err == SkipConversion:
+		case err != nil:
+			return v, fmt.Errorf("arguments: %w", err)
+		case cv == nil || !reflect.TypeOf(cv).AssignableTo(t) || (t.Kind() != reflect.Interface && reflect.TypeOf(cv) != t):
+			return v, fmt.Errorf("arguments: converter returned %v instead of %v", reflect.TypeOf(cv), t)
+		default:
+			return reflect.ValueOf(cv).Convert(t), nil
+		}
+	}
+	if mv, handled, err := o.unmarshalProto(p, t); handled {
+		if err != nil {
+			return v, fmt.Errorf("arguments: %w", err)
+		}
+		return mv, nil
+	}
+	if bv, handled, err := o.decodeBytes(p, t); handled {
+		if err != nil {
+			return v, fmt.Errorf("arguments: %w", err)
+		}
+		return bv, nil
+	}
+	if uv, handled, err := unmarshalArg(p, t); handled {
+		if err != nil {
+			return v, fmt.Errorf("arguments: %w", err)
+		}
+		return uv, nil
+	}
+	if pt.Kind() == reflect.Map && pt.Key().Kind() == reflect.String && isStruct(t) {
+		return o.bindStruct(v, t)
+	}
+	if t.Kind() == reflect.Ptr && pt.Kind() != reflect.Ptr {
+		return o.addressOf(p, t)
+	}
+	if pt.Kind() == reflect.Ptr && t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		if v.IsNil() {
+			return v, fmt.Errorf("arguments: nil %v can not be dereferenced to %v", pt, t)
+		}
+		return o.argValue(v.Elem().Interface(), t)
+	}
+	// the slices are converted into arrays element by element, checking their length
+	if pt.Kind() == reflect.Slice && t.Kind() == reflect.Array {
+		return o.convertElems(v, t)
+	}
+	if pt.AssignableTo(t) || (!o.noImplicit && pt.ConvertibleTo(t)) {
+		if err := overflows(v, t); err != nil {
+			return v, err
+		}
+		return v.Convert(t), nil
+	}
+	switch {
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && (pt.Kind() == reflect.Slice || pt.Kind() == reflect.Array):
+		return o.convertElems(v, t)
+	case t.Kind() == reflect.Map && pt.Kind() == reflect.Map:
+		return o.convertEntries(v, t)
+	case t.Kind() == reflect.Func && pt.Kind() == reflect.Func:
+		return o.adaptFunc(v, t)
+	}
+	return v, fmt.Errorf("arguments: %v is not convertible to %v", pt, t)
+}
+
+// overflows returns an error when the numeric value v doesn't fit in t
+func overflows(v reflect.Value, t reflect.Type) error {
+	dst := reflect.Zero(t)
+	overflow := false
+	switch {
+	case isInt(v.Kind()) && isInt(t.Kind()):
+		overflow = dst.OverflowInt(v.Int())
+	case isInt(v.Kind()) && isUint(t.Kind()):
+		overflow = v.Int() < 0 || dst.OverflowUint(uint64(v.Int()))
+	case isUint(v.Kind()) && isInt(t.Kind()):
+		overflow = v.Uint() > math.MaxInt64 || dst.OverflowInt(int64(v.Uint()))
+	case isUint(v.Kind()) && isUint(t.Kind()):
+		overflow = dst.OverflowUint(v.Uint())
+	case isFloat(v.Kind()) && isInt(t.Kind()):
+		f := v.Float()
+		overflow = math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 || dst.OverflowInt(int64(f))
+	case isFloat(v.Kind()) && isUint(t.Kind()):
+		f := v.Float()
+		overflow = math.IsNaN(f) || f <= -1 || f >= math.MaxUint64 || dst.OverflowUint(uint64(f))
+	case isFloat(v.Kind()) && isFloat(t.Kind()):
+		overflow = dst.OverflowFloat(v.Float())
+	}
+	if overflow {
+		return fmt.Errorf("arguments: %v overflows %v", v.Interface(), t)
+	}
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// elemValue converts a container element, errors are prefixed by its position
+func (o options) elemValue(e reflect.Value, t reflect.Type, pos interface{}) (reflect.Value, error) {
+	ev, err := o.argValue(e.Interface(), t)
+	if err != nil {
+		return ev, fmt.Errorf("[%v]: %v", pos, strings.TrimPrefix(err.Error(), "arguments: "))
+	}
+	return ev, nil
+}
+
+// convertElems converts the slice or array v element by element into t
+func (o options) convertElems(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	var out reflect.Value
+	if t.Kind() == reflect.Array {
+		if v.Len() != t.Len() {
+			return v, fmt.Errorf("arguments: %v of length %d is not convertible to %v", v.Type(), v.Len(), t)
+		}
+		out = reflect.New(t).Elem()
+	} else {
+		out = reflect.MakeSlice(t, v.Len(), v.Len())
+	}
+	for i := 0; i < v.Len(); i++ {
+		ev, err := o.elemValue(v.Index(i), t.Elem(), i)
+		if err != nil {
+			return v, fmt.Errorf("arguments: %w", err)
+		}
+		out.Index(i).Set(ev)
+	}
+	return out, nil
+}
+
+// convertEntries converts the map v key by key and value by value into t
+func (o options) convertEntries(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.MakeMapWithSize(t, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k, err := o.elemValue(iter.Key(), t.Key(), iter.Key())
+		if err != nil {
+			return v, fmt.Errorf("arguments: key %v", err)
+		}
+		ev, err := o.elemValue(iter.Value(), t.Elem(), iter.Key())
+		if err != nil {
+			return v, fmt.Errorf("arguments: %w", err)
+		}
+		out.SetMapIndex(k, ev)
+	}
+	return out, nil
+}
+
+// adaptFunc wraps the function v into a function of type t converting the arguments
+// and the results between them, a failed conversion panics when the function is called
+func (o options) adaptFunc(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	vt := v.Type()
+	if vt.NumIn() != t.NumIn() || vt.NumOut() != t.NumOut() || vt.IsVariadic() != t.IsVariadic() {
+		return v, fmt.Errorf("arguments: %v is not convertible to %v", vt, t)
+	}
+	if v.IsNil() {
+		return reflect.Zero(t), nil
+	}
+	convert := func(in []reflect.Value, types func(int) reflect.Type) []reflect.Value {
+		out := make([]reflect.Value, len(in))
+		for i, a := range in {
+			var err error
+			if out[i], err = o.argValue(a.Interface(), types(i)); err != nil {
+				panic(fmt.Sprintf("funcutil: %v called as %v: %v", vt, t, err))
+			}
+		}
+		return out
+	}
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		args = convert(args, vt.In)
+		var rets []reflect.Value
+		if vt.IsVariadic() {
+			rets = v.CallSlice(args)
+		} else {
+			rets = v.Call(args)
+		}
+		return convert(rets, t.Out)
+	}), nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// unmarshalArg decodes strings for the types implementing encoding.TextUnmarshaler
+// or json.Unmarshaler and raw JSON or MessagePack values for any type, reports false
+// when it doesn't apply
+func unmarshalArg(p interface{}, t reflect.Type) (reflect.Value, bool, error) {
+	_, raw := p.(json.RawMessage)
+	_, mraw := p.(MsgpackRaw)
+	if t.Kind() == reflect.Interface && !((raw || mraw) && t.NumMethod() == 0) {
+		return reflect.Value{}, false, nil
+	}
+	// the value to unmarshal into and the type of its pointer
+	ptr := reflect.New(t)
+	pt := reflect.PtrTo(t)
+	if t.Kind() == reflect.Ptr {
+		ptr = reflect.New(t.Elem())
+		pt = t
+	}
+	result := func(err error) (reflect.Value, bool, error) {
+		if t.Kind() == reflect.Ptr {
+			return ptr, true, err
+		}
+		return ptr.Elem(), true, err
+	}
+	switch v := p.(type) {
+	case string:
+		if pt.Implements(textUnmarshalerType) {
+			return result(ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)))
+		}
+		if pt.Implements(jsonUnmarshalerType) {
+			b, _ := json.Marshal(v)
+			return result(ptr.Interface().(json.Unmarshaler).UnmarshalJSON(b))
+		}
+	case json.RawMessage:
+		// json.Unmarshal honors json.Unmarshaler
+		return result(json.Unmarshal(v, ptr.Interface()))
+	case MsgpackRaw:
+		return result(UnmarshalMsgpack(v, ptr.Interface()))
+	}
+	return reflect.Value{}, false, nil
+}
+
+// addressOf returns a pointer of type t to a copy of p, the method changes through
+// the pointer are not visible to the caller
+func (o options) addressOf(p interface{}, t reflect.Type) (reflect.Value, error) {
+	ev, err := o.argValue(p, t.Elem())
+	if err != nil {
+		return ev, fmt.Errorf("arguments: can not take the address of %v as %v: %v", reflect.TypeOf(p), t, err)
+	}
+	ptr := reflect.New(t.Elem())
+	ptr.Elem().Set(ev)
+	return ptr, nil
+}
+
+// isNilable reports whether nil is a valid value of t
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// isStruct reports whether t is a struct or a pointer to struct
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct)
+}
+
+// bindStruct populates a new struct of type t (struct or *struct) from the map entries,
+// keys are matched against the json names of the fields, then their names regardless the case
+func (o options) bindStruct(m reflect.Value, t reflect.Type) (reflect.Value, error) {
+	st := t
+	if t.Kind() == reflect.Ptr {
+		st = t.Elem()
+	}
+	sv := reflect.New(st).Elem()
+	for _, key := range m.MapKeys() {
+		i, found := fieldByName(st, key.String())
+		if !found {
+			continue
+		}
+		fv := m.MapIndex(key).Interface()
+		if fv == nil {
+			continue
+		}
+		v, err := o.argValue(fv, st.Field(i).Type)
+		if err != nil {
+			return sv, fmt.Errorf("field %s: %v", st.Field(i).Name, err)
+		}
+		sv.Field(i).Set(v)
+	}
+	if t.Kind() == reflect.Ptr {
+		return sv.Addr(), nil
+	}
+	return sv, nil
+}
+
+// fieldByName returns the index of the exported field named name, honoring the json tags
+func fieldByName(t reflect.Type, name string) (int, bool) {
+	fold := -1
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fieldName := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				fieldName = n
+			}
+		}
+		if fieldName == name {
+			return i, true
+		}
+		if fold < 0 && strings.EqualFold(fieldName, name) {
+			fold = i
+		}
+	}
+	return fold, fold >= 0
+}
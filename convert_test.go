@@ -0,0 +1,464 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Profile struct {
+	Nick    string `json:"nick"`
+	Level   int
+	Address *Address `json:"address"`
+	Ignored string   `json:"-"`
+}
+
+type profiles struct {
+	last Profile
+}
+
+func (p *profiles) Save(pr Profile) {
+	p.last = pr
+}
+
+func (p *profiles) SaveRef(pr *Profile) {
+	p.last = *pr
+}
+
+func TestMapToStructBinding(t *testing.T) {
+	f := New()
+	p := &profiles{}
+	f.Register(p)
+	payload := map[string]interface{}{
+		"nick":    "gopher",
+		"level":   float64(3),
+		"address": map[string]interface{}{"City": "Bali"},
+		"Ignored": "value",
+		"unknown": true,
+	}
+	if _, err := f.Call("profiles.Save", payload); err != nil {
+		t.Fatal(err)
+	}
+	if p.last.Nick != "gopher" || p.last.Level != 3 || p.last.Address.City != "Bali" || p.last.Ignored != "" {
+		t.Errorf("Unexpected binding %+v", p.last)
+	}
+	if _, err := f.Call("profiles.SaveRef", map[string]interface{}{"nick": "ref"}); err != nil {
+		t.Fatal(err)
+	}
+	if p.last.Nick != "ref" {
+		t.Errorf("Should be ref got %s", p.last.Nick)
+	}
+	if _, err := f.Call("profiles.Save", map[string]interface{}{"Level": true}); err == nil {
+		t.Error("should failed due to wrong field type")
+	}
+	// bound structs are validated
+	f.Register(&registry{})
+	if _, err := f.Call("registry.Add", map[string]interface{}{"Name": "gopher"}); err == nil {
+		t.Error("should failed due to validation")
+	}
+}
+
+type counters struct {
+	total int
+}
+
+func (c *counters) AddRef(n *int) {
+	c.total += *n
+}
+
+func (c *counters) Add(n int) {
+	c.total += n
+}
+
+func TestPointerAddressing(t *testing.T) {
+	f := New()
+	c := &counters{}
+	f.Register(c)
+	n := 2
+	if _, err := f.Call("counters.AddRef", &n); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("counters.AddRef", 3); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("counters.AddRef", int8(4)); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("counters.Add", &n); err != nil {
+		t.Error(err)
+	}
+	if c.total != 11 {
+		t.Errorf("Should be 11 got %d", c.total)
+	}
+	var nilRef *int
+	if _, err := f.Call("counters.Add", nilRef); err == nil {
+		t.Error("should failed due to nil pointer")
+	}
+	if _, err := f.Call("counters.AddRef", true); err == nil {
+		t.Error("should failed due to wrong argument type")
+	}
+}
+
+type ID int
+
+func (id ID) String() string {
+	return fmt.Sprintf("#%d", int(id))
+}
+
+type tagger struct {
+	tags   []string
+	ids    []ID
+	scores map[string]float64
+}
+
+func (tg *tagger) SetTags(tags []string) {
+	tg.tags = tags
+}
+
+func (tg *tagger) SetIDs(ids []ID) {
+	tg.ids = ids
+}
+
+func (tg *tagger) SetScores(scores map[string]float64) {
+	tg.scores = scores
+}
+
+func TestElementConversion(t *testing.T) {
+	f := New()
+	tg := &tagger{}
+	f.Register(tg)
+	if _, err := f.Call("tagger.SetTags", []interface{}{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tg.tags) != 2 || tg.tags[1] != "b" {
+		t.Errorf("Unexpected tags %v", tg.tags)
+	}
+	if _, err := f.Call("tagger.SetScores", map[string]interface{}{"a": 1, "b": 2.5}); err != nil {
+		t.Fatal(err)
+	}
+	if tg.scores["a"] != 1 || tg.scores["b"] != 2.5 {
+		t.Errorf("Unexpected scores %v", tg.scores)
+	}
+	_, err := f.Call("tagger.SetTags", []interface{}{"a", true})
+	expect := "arguments: parameter 0: [1]: bool is not convertible to string"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Should be %s got %v", expect, err)
+	}
+	_, err = f.Call("tagger.SetScores", map[string]interface{}{"a": "x"})
+	expect = "arguments: parameter 0: [a]: string is not convertible to float64"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Should be %s got %v", expect, err)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	f := New()
+	tg := &tagger{}
+	f.Register(tg)
+	f.RegisterConverter(reflect.TypeOf(ID(0)), func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not an ID", v)
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+		return ID(n), err
+	})
+	if _, err := f.Call("tagger.SetIDs", []interface{}{"#1", "#20"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tg.ids) != 2 || tg.ids[1] != 20 {
+		t.Errorf("Unexpected ids %v", tg.ids)
+	}
+	if _, err := f.Call("tagger.SetIDs", []interface{}{"#1", 2}); err == nil {
+		t.Error("should failed due to wrong ID")
+	}
+}
+
+type scheduler struct {
+	at    time.Time
+	every time.Duration
+}
+
+func (s *scheduler) Schedule(at time.Time, every time.Duration) {
+	s.at = at
+	s.every = every
+}
+
+func TestTimeConversion(t *testing.T) {
+	f := New()
+	s := &scheduler{}
+	f.Register(s)
+	if _, err := f.Call("scheduler.Schedule", "2020-01-02T03:04:05Z", "1h30m"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.at.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) || s.every != 90*time.Minute {
+		t.Errorf("Unexpected schedule %v %v", s.at, s.every)
+	}
+	// durations are still convertible from numbers
+	if _, err := f.Call("scheduler.Schedule", time.Now(), int64(time.Second)); err != nil || s.every != time.Second {
+		t.Errorf("Should be 1s got %v %v", s.every, err)
+	}
+	if _, err := f.Call("scheduler.Schedule", "yesterday", "1h"); err == nil {
+		t.Error("should failed due to bad time")
+	}
+	if _, err := f.Call("scheduler.Schedule", time.Now(), "forever"); err == nil {
+		t.Error("should failed due to bad duration")
+	}
+}
+
+type Level int
+
+func (l *Level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %s", text)
+	}
+	return nil
+}
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) UnmarshalJSON(b []byte) error {
+	var xy [2]int
+	if err := json.Unmarshal(b, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+type canvas struct {
+	level Level
+	at    *Point
+}
+
+func (c *canvas) Draw(level Level, at *Point) {
+	c.level = level
+	c.at = at
+}
+
+func TestUnmarshalerConversion(t *testing.T) {
+	f := New()
+	c := &canvas{}
+	f.Register(c)
+	if _, err := f.Call("canvas.Draw", "high", json.RawMessage("[3,4]")); err != nil {
+		t.Fatal(err)
+	}
+	if c.level != 2 || c.at.X != 3 || c.at.Y != 4 {
+		t.Errorf("Unexpected values %v %v", c.level, c.at)
+	}
+	// still convertible from numbers
+	if _, err := f.Call("canvas.Draw", 1, &Point{}); err != nil || c.level != 1 {
+		t.Errorf("Should be 1 got %v %v", c.level, err)
+	}
+	if _, err := f.Call("canvas.Draw", "medium", &Point{}); err == nil {
+		t.Error("should failed due to unknown level")
+	}
+}
+
+type nilables struct {
+	calls int
+}
+
+func (n *nilables) Interface(v interface{}) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Pointer(v *Point) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Slice(v []string) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Map(v map[string]int) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Chan(v chan int) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Func(v func()) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Error(v error) bool {
+	n.calls++
+	return v == nil
+}
+
+func (n *nilables) Int(v int) bool {
+	n.calls++
+	return false
+}
+
+func TestNilArguments(t *testing.T) {
+	f := New()
+	n := &nilables{}
+	f.Register(n)
+	for _, kind := range []string{"Interface", "Pointer", "Slice", "Map", "Chan", "Func", "Error"} {
+		rets, err := f.Call("nilables."+kind, nil)
+		if err != nil {
+			t.Errorf("%s: %v", kind, err)
+			continue
+		}
+		if !rets[0].(bool) {
+			t.Errorf("%s: should receive nil", kind)
+		}
+	}
+	if _, err := f.Call("nilables.Int", nil); err == nil {
+		t.Error("should failed due to nil int")
+	}
+	if n.calls != 7 {
+		t.Errorf("Should be called 7 times got %d", n.calls)
+	}
+	f.Register(&tagger{})
+	if _, err := f.Call("tagger.SetTags", []interface{}{"a", nil}); err == nil {
+		t.Error("should failed due to nil string")
+	}
+}
+
+type narrow struct{}
+
+func (n *narrow) Int32(v int32) int32 {
+	return v
+}
+
+func (n *narrow) Uint8(v uint8) uint8 {
+	return v
+}
+
+func (n *narrow) Float32(v float32) float32 {
+	return v
+}
+
+func TestOverflow(t *testing.T) {
+	f := New()
+	f.Register(&narrow{})
+	for _, c := range []struct {
+		method string
+		param  interface{}
+	}{
+		{"narrow.Int32", int64(1e12)},
+		{"narrow.Int32", 1e12},
+		{"narrow.Int32", uint64(1 << 63)},
+		{"narrow.Uint8", -1},
+		{"narrow.Uint8", 256},
+		{"narrow.Uint8", -2.5},
+		{"narrow.Float32", 1e300},
+	} {
+		ci, _ := f.lookup(c.method)
+		if err := f.opts.parametersMatch(ci, []interface{}{c.param}); err == nil {
+			t.Errorf("%s(%v) should failed due to overflow", c.method, c.param)
+		}
+		if _, err := f.Call(c.method, c.param); err == nil {
+			t.Errorf("%s(%v) should failed due to overflow", c.method, c.param)
+		}
+	}
+	if rets, err := f.Call("narrow.Uint8", 255.9); err != nil || rets[0] != uint8(255) {
+		t.Errorf("Should be 255 got %v %v", rets, err)
+	}
+	if rets, err := f.Call("narrow.Int32", int64(-1<<31)); err != nil || rets[0] != int32(-1<<31) {
+		t.Errorf("Should be %d got %v %v", -1<<31, rets, err)
+	}
+}
+
+type walker struct{}
+
+func (w *walker) ForEach(items []string, fn func(item string)) {
+	for _, item := range items {
+		fn(item)
+	}
+}
+
+func (w *walker) Map(items []string, fn func(string) int) []int {
+	out := []int{}
+	for _, item := range items {
+		out = append(out, fn(item))
+	}
+	return out
+}
+
+func TestFuncArguments(t *testing.T) {
+	f := New()
+	f.Register(&walker{})
+	seen := []string{}
+	if _, err := f.Call("walker.ForEach", []string{"a", "b"}, func(item string) {
+		seen = append(seen, item)
+	}); err != nil || strings.Join(seen, ",") != "a,b" {
+		t.Errorf("Should be a,b got %v %v", seen, err)
+	}
+	seen = seen[:0]
+	if _, err := f.Call("walker.ForEach", []string{"c"}, func(item interface{}) {
+		seen = append(seen, item.(string))
+	}); err != nil || strings.Join(seen, ",") != "c" {
+		t.Errorf("Should be c got %v %v", seen, err)
+	}
+	rets, err := f.Call("walker.Map", []string{"ab", "abc"}, func(item interface{}) float64 {
+		return float64(len(item.(string)))
+	})
+	if err != nil || fmt.Sprint(rets[0]) != "[2 3]" {
+		t.Errorf("Should be [2 3] got %v %v", rets, err)
+	}
+	if _, err := f.Call("walker.Map", []string{"a"}, func(a, b string) int { return 0 }); err == nil {
+		t.Error("should failed due to function arity")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("should panic due to inconvertible result")
+			}
+		}()
+		f.Call("walker.Map", []string{"a"}, func(item string) []string { return nil })
+	}()
+}
+
+type quad struct {
+}
+
+func (q *quad) Sum(v [4]int) int {
+	return v[0] + v[1] + v[2] + v[3]
+}
+
+func (q *quad) SumPtr(v *[4]int) int {
+	return q.Sum(*v)
+}
+
+func TestSliceToArrayConversion(t *testing.T) {
+	f := New()
+	f.Register(&quad{})
+	if results, err := f.Call("quad.Sum", []int{1, 2, 3, 4}); err != nil || results[0] != 10 {
+		t.Errorf("Should be 10 got %v %v", results, err)
+	}
+	if results, err := f.Call("quad.SumPtr", []float64{1, 2, 3, 4}); err != nil || results[0] != 10 {
+		t.Errorf("Should be 10 got %v %v", results, err)
+	}
+	var argType ErrArgType
+	for _, name := range []string{"quad.Sum", "quad.SumPtr"} {
+		if _, err := f.Call(name, []int{1, 2}); !errors.As(err, &argType) {
+			t.Errorf("should failed due to short slice got %v", err)
+		}
+		if err := f.Validate(name, []int{1, 2}); !errors.As(err, &argType) {
+			t.Errorf("should failed due to short slice got %v", err)
+		}
+	}
+}
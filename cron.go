@@ -0,0 +1,287 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidSchedule = errors.New("Invalid schedule")
+)
+
+// OverlapPolicy tells what a scheduled job does when the previous run is not done
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run, it is the default
+	OverlapSkip OverlapPolicy = iota
+	// OverlapAllow runs concurrently with the previous runs
+	OverlapAllow
+	// OverlapWait delays the run until the previous one is done
+	OverlapWait
+)
+
+// schedule returns the time of the next run after t
+type schedule interface {
+	next(t time.Time) time.Time
+}
+
+// Job is a method called periodically, see Schedule
+type Job struct {
+	sync.Mutex
+	f        *FuncUtil
+	schedule schedule
+	method   string
+	params   []interface{}
+	overlap  OverlapPolicy
+	stop     chan struct{}
+	running  int
+	runs     int
+	last     Reply
+}
+
+// Schedule calls the method periodically on the executor, see SetExecutor, until the
+// job is stopped. The spec is a cron expression of the minute, hour, day of month,
+// month and day of week fields in local time, or one of @hourly, @daily, @weekly,
+// @monthly, @yearly and @every <duration>.
+//
+//	job, _ := f.Schedule("*/5 * * * *", "service.Heartbeat")
+//	job.SetOverlap(funcutil.OverlapWait)
+//	...
+//	job.Stop()
+func (f *FuncUtil) Schedule(spec, methodName string, params ...interface{}) (*Job, error) {
+	s, err := parseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := f.resolve(methodName); err != nil {
+		return nil, err
+	}
+	j := &Job{f: f, schedule: s, method: methodName, params: params}
+	j.Start()
+	return j, nil
+}
+
+// SetOverlap sets what the job does when the previous run is not done
+func (j *Job) SetOverlap(policy OverlapPolicy) {
+	j.Lock()
+	defer j.Unlock()
+	j.overlap = policy
+}
+
+// Start restarts the stopped job
+func (j *Job) Start() {
+	j.Lock()
+	defer j.Unlock()
+	if j.stop != nil {
+		return
+	}
+	j.stop = make(chan struct{})
+	go j.loop(j.stop)
+}
+
+// Stop stops the job, the runs in progress are not interrupted
+func (j *Job) Stop() {
+	j.Lock()
+	defer j.Unlock()
+	if j.stop != nil {
+		close(j.stop)
+		j.stop = nil
+	}
+}
+
+// Last returns the number of runs done and the reply of the last one
+func (j *Job) Last() (int, Reply) {
+	j.Lock()
+	defer j.Unlock()
+	return j.runs, j.last
+}
+
+func (j *Job) loop(stop chan struct{}) {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(j.schedule.next(now).Sub(now))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			j.run()
+		}
+	}
+}
+
+func (j *Job) run() {
+	j.Lock()
+	policy := j.overlap
+	if policy == OverlapSkip && j.running > 0 {
+		j.Unlock()
+		return
+	}
+	j.running++
+	j.Unlock()
+
+	reply := make(chan Reply, 1)
+	priority, _ := j.f.priorityOf(j.method)
+	j.f.submitCall(context.Background(), priority, j.method, j.params, reply)
+	done := func() {
+		r := <-reply
+		j.Lock()
+		defer j.Unlock()
+		j.running--
+		j.runs++
+		j.last = r
+	}
+	if policy == OverlapWait {
+		done()
+		return
+	}
+	go done()
+}
+
+// every runs at a fixed interval
+type every time.Duration
+
+func (e every) next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule is the set of the values matched by every field
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// the days match either field when both are restricted
+	domAny, dowAny bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSchedule, spec)
+		}
+		return every(d), nil
+	}
+	if expr, exists := cronDescriptors[spec]; exists {
+		spec = expr
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %s: expected 5 fields", ErrInvalidSchedule, spec)
+	}
+	s := &cronSchedule{
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSchedule, spec, err)
+		}
+		*b.field = bits
+	}
+	// sunday is either 0 or 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses the comma separated *, n, a-b with an optional /step
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %s", part)
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(rng[:i])
+			hi, err2 = strconv.Atoi(rng[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %s", part)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %s", part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				// n/step starts at n
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%s out of range %d-%d", part, min, max)
+		}
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// the impossible dates like February 30 are given up after a few years
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return limit
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
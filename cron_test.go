@@ -0,0 +1,103 @@
+package funcutil
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2019, 5, 3, 10, 2, 30, 0, time.UTC) // friday
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"*/5 * * * *", time.Date(2019, 5, 3, 10, 5, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2019, 5, 3, 10, 3, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2019, 5, 4, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2019, 5, 3, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2019, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2019, 5, 15, 0, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week
+		{"0 0 13 * 1", time.Date(2019, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+	for _, test := range tests {
+		s, err := parseSchedule(test.spec)
+		if err != nil {
+			t.Errorf("%s: %v", test.spec, err)
+			continue
+		}
+		if next := s.next(base); !next.Equal(test.next) {
+			t.Errorf("%s: Should be %v got %v", test.spec, test.next, next)
+		}
+	}
+	for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "a * * * *", "5-1 * * * *", "@every -1s", "@often"} {
+		if _, err := parseSchedule(spec); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: should failed due to invalid schedule got %v", spec, err)
+		}
+	}
+}
+
+type heartbeat struct {
+	beats int32
+	delay time.Duration
+}
+
+func (h *heartbeat) Beat() {
+	atomic.AddInt32(&h.beats, 1)
+	time.Sleep(h.delay)
+}
+
+func TestSchedule(t *testing.T) {
+	f := New()
+	h := &heartbeat{}
+	f.Register(h)
+	job, err := f.Schedule("@every 10ms", "heartbeat.Beat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(55 * time.Millisecond)
+	job.Stop()
+	beats := atomic.LoadInt32(&h.beats)
+	if beats < 3 {
+		t.Errorf("Should be at least 3 beats got %d", beats)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&h.beats) != beats {
+		t.Error("Should not beat after stop")
+	}
+	if runs, last := job.Last(); runs < 3 || last.Err != nil {
+		t.Errorf("Should be at least 3 runs got %d %v", runs, last.Err)
+	}
+	job.Start()
+	time.Sleep(25 * time.Millisecond)
+	job.Stop()
+	if atomic.LoadInt32(&h.beats) == beats {
+		t.Error("Should beat after restart")
+	}
+
+	if _, err := f.Schedule("@every 10ms", "heartbeat.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	if _, err := f.Schedule("* *", "heartbeat.Beat"); !errors.Is(err, ErrInvalidSchedule) {
+		t.Error("should failed due to invalid schedule")
+	}
+}
+
+func TestScheduleOverlap(t *testing.T) {
+	f := New()
+	h := &heartbeat{delay: 35 * time.Millisecond}
+	f.Register(h)
+	job, _ := f.Schedule("@every 10ms", "heartbeat.Beat")
+	time.Sleep(60 * time.Millisecond)
+	job.Stop()
+	// the runs overlapping the slow beats are skipped
+	if beats := atomic.LoadInt32(&h.beats); beats > 2 {
+		t.Errorf("Should be at most 2 beats got %d", beats)
+	}
+}
@@ -0,0 +1,87 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrNotStruct           = errors.New("Destination must be a pointer to struct")
+	ErrResultNamesMismatch = errors.New("Result names mismatch the results")
+	ErrParamNamesMismatch  = errors.New("Parameter names mismatch the parameters")
+)
+
+// SetResultNames names the results of the method, e.g. after its named results,
+// so CallDecode can match them with the fields of the destination
+//
+//	// func (s *users) Find(id int) (user *User, found bool)
+//	f.SetResultNames("users.Find", "user", "found")
+func (f *FuncUtil) SetResultNames(methodName string, names ...string) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	if len(names) != len(ci.retTypes) {
+		return ErrResultNamesMismatch
+	}
+	ci.retNames = names
+	return f.store(ci)
+}
+
+// CallDecode invokes the method and stores its results into the fields of the struct
+// pointed by out. The named results (see SetResultNames) go to the fields of the same
+// name, honoring the json tags, the others to the exported field at their position.
+//
+//	var found struct {
+//		User  *User
+//		Found bool
+//	}
+//	f.CallDecode("users.Find", &found, 42)
+func (f *FuncUtil) CallDecode(methodName string, out interface{}, params ...interface{}) error {
+	ov := reflect.ValueOf(out)
+	if ov.Kind() != reflect.Ptr || ov.IsNil() || ov.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return opts.mapError(methodName, err)
+	}
+	rets, err := opts.call(context.Background(), ci, params)
+	if err != nil {
+		return err
+	}
+	sv := ov.Elem()
+	st := sv.Type()
+	fields := exportedFields(st)
+	for i, ret := range rets {
+		field, found := -1, false
+		if i < len(ci.retNames) && ci.retNames[i] != "" {
+			field, found = fieldByName(st, ci.retNames[i])
+		} else if i < len(fields) {
+			field, found = fields[i], true
+		}
+		if !found {
+			continue
+		}
+		if err := opts.assign(sv.Field(field).Addr().Interface(), ret); err != nil {
+			return fmt.Errorf("results: %s: %v", st.Field(field).Name, err)
+		}
+	}
+	return nil
+}
+
+// exportedFields returns the indexes of the exported fields of t
+func exportedFields(t reflect.Type) []int {
+	fields := []int{}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,57 @@
+package funcutil
+
+import "testing"
+
+type directory struct {
+}
+
+func (d *directory) Find(id int) (string, int, bool) {
+	return "gopher", id, id > 0
+}
+
+func TestCallDecode(t *testing.T) {
+	f := New()
+	f.Register(&directory{})
+	var positional struct {
+		Name  string
+		ID    int64
+		Found bool
+	}
+	if err := f.CallDecode("directory.Find", &positional, 7); err != nil {
+		t.Fatal(err)
+	}
+	if positional.Name != "gopher" || positional.ID != 7 || !positional.Found {
+		t.Errorf("Unexpected result %+v", positional)
+	}
+
+	if err := f.SetResultNames("directory.Find", "name", "id", "ok"); err != nil {
+		t.Fatal(err)
+	}
+	var named struct {
+		Found bool `json:"ok"`
+		Name  string
+		ID    int
+	}
+	if err := f.CallDecode("directory.Find", &named, 7); err != nil {
+		t.Fatal(err)
+	}
+	if named.Name != "gopher" || named.ID != 7 || !named.Found {
+		t.Errorf("Unexpected result %+v", named)
+	}
+	if mi, _ := f.Info("directory.Find"); len(mi.ResultNames) != 3 {
+		t.Errorf("Should be 3 got %d", len(mi.ResultNames))
+	}
+
+	if err := f.CallDecode("directory.Find", named, 7); err != ErrNotStruct {
+		t.Error("should failed due to non pointer destination")
+	}
+	if err := f.SetResultNames("directory.Find", "name"); err != ErrResultNamesMismatch {
+		t.Error("should failed due to missing names")
+	}
+	var wrong struct {
+		Name []int
+	}
+	if err := f.CallDecode("directory.Find", &wrong, 7); err == nil {
+		t.Error("should failed due to inconvertible result")
+	}
+}
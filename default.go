@@ -0,0 +1,42 @@
+package funcutil
+
+import "sync"
+
+var (
+	defaultOnce     sync.Once
+	defaultRegistry *FuncUtil
+)
+
+// Default returns the registry used by the package level functions,
+// it is created at the first use
+func Default() *FuncUtil {
+	defaultOnce.Do(func() {
+		defaultRegistry = New()
+	})
+	return defaultRegistry
+}
+
+// Register registers the structs into the default registry
+func Register(vars ...interface{}) error {
+	return Default().Register(vars...)
+}
+
+// RegisterMap registers the functions into the default registry
+func RegisterMap(funcs map[string]interface{}) error {
+	return Default().RegisterMap(funcs)
+}
+
+// Call invokes the method of the default registry
+func Call(methodName string, params ...interface{}) ([]interface{}, error) {
+	return Default().Call(methodName, params...)
+}
+
+// CallInto invokes the method of the default registry storing its results into results
+func CallInto(methodName string, results []interface{}, params ...interface{}) error {
+	return Default().CallInto(methodName, results, params...)
+}
+
+// Dump returns the signatures of the methods of the default registry
+func Dump() []string {
+	return Default().Dump()
+}
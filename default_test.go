@@ -0,0 +1,21 @@
+package funcutil
+
+import "testing"
+
+func TestDefaultRegistry(t *testing.T) {
+	Register(&Monitor{})
+	RegisterMap(map[string]interface{}{"answer": func() int { return 42 }})
+	if Default() != Default() {
+		t.Error("default registry should be created once")
+	}
+	if rets, err := Call("Monitor.Display"); err != nil || rets[0] != "Display()" {
+		t.Errorf("Should be Display() got %v %v", rets, err)
+	}
+	results := make([]interface{}, 1)
+	if err := CallInto("answer", results); err != nil || results[0] != 42 {
+		t.Errorf("Should be 42 got %v %v", results, err)
+	}
+	if len(Dump()) != 2 {
+		t.Errorf("Should be 2 methods got %d", len(Dump()))
+	}
+}
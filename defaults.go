@@ -0,0 +1,45 @@
+package funcutil
+
+import "errors"
+
+var (
+	ErrTooManyDefaults = errors.New("Defaults exceed the method parameters")
+)
+
+// SetDefaults sets the default values of the trailing parameters of the method,
+// so callers may omit them. The last default belongs to the last parameter.
+//
+//	f.SetDefaults("service.Stop", true)
+//	f.Call("service.Stop") // same as f.Call("service.Stop", true)
+func (f *FuncUtil) SetDefaults(methodName string, defaults ...interface{}) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	argTypes := ci.argTypes
+	if len(defaults) > len(argTypes) {
+		return ErrTooManyDefaults
+	}
+	offset := len(argTypes) - len(defaults)
+	for i, d := range defaults {
+		if _, err := f.opts.argValue(d, argTypes[offset+i]); err != nil {
+			return err
+		}
+	}
+	ci.defaults = defaults
+	return f.store(ci)
+}
+
+// withDefaults fills the omitted trailing params with the method defaults
+func (mi *callInfo) withDefaults(params []interface{}) []interface{} {
+	missing := len(mi.argTypes) - len(params)
+	if missing <= 0 || missing > len(mi.defaults) {
+		return params
+	}
+	filled := make([]interface{}, 0, len(params)+missing)
+	filled = append(filled, params...)
+	return append(filled, mi.defaults[len(mi.defaults)-missing:]...)
+}
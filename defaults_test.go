@@ -0,0 +1,47 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+type mailer struct {
+	sent []string
+}
+
+func (m *mailer) Send(to, subject string, urgent bool) {
+	if urgent {
+		subject = "URGENT " + subject
+	}
+	m.sent = append(m.sent, to+": "+subject)
+}
+
+func TestDefaults(t *testing.T) {
+	f := New()
+	m := &mailer{}
+	f.Register(m)
+	if err := f.SetDefaults("mailer.Send", "hello", false); err != nil {
+		t.Fatal(err)
+	}
+	f.Call("mailer.Send", "a")
+	f.Call("mailer.Send", "b", "hi")
+	f.Call("mailer.Send", "c", "hi", true)
+	expect := []string{"a: hello", "b: hi", "c: URGENT hi"}
+	for i := range expect {
+		if m.sent[i] != expect[i] {
+			t.Errorf("Should be %s got %s", expect[i], m.sent[i])
+		}
+	}
+	if _, err := f.Call("mailer.Send"); !errors.Is(err, ErrParametersMismatch) {
+		t.Error("should failed due to missing argument")
+	}
+	if err := f.SetDefaults("mailer.Send", "a", "b", true, 1); err != ErrTooManyDefaults {
+		t.Error("should failed due to too many defaults")
+	}
+	if err := f.SetDefaults("mailer.Send", 12); err == nil {
+		t.Error("should failed due to wrong default type")
+	}
+	if err := f.SetDefaults("mailer.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,65 @@
+package funcutil
+
+import "log"
+
+// DeprecatedCallFunc is called when a deprecated method is invoked
+type DeprecatedCallFunc func(name, note string)
+
+// Deprecate marks the method deprecated, calls still succeed but fire the
+// deprecation callback, see OnDeprecatedCall. The note usually tells the replacement.
+//
+//	f.Deprecate("service.Old", "use service.New")
+func (f *FuncUtil) Deprecate(methodName, note string) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.deprecated = true
+	ci.deprecation = note
+	return f.store(ci)
+}
+
+// OnDeprecatedCall replaces the callback fired by the calls of the deprecated methods,
+// which logs them by default. Nil keeps the calls silent.
+func (f *FuncUtil) OnDeprecatedCall(fn DeprecatedCallFunc) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if fn == nil {
+		fn = func(name, note string) {}
+	}
+	f.opts.onDeprecated = fn
+	return nil
+}
+
+// warnDeprecated fires the deprecation callback if the method is deprecated
+func (o options) warnDeprecated(ci callInfo) {
+	if !ci.deprecated {
+		return
+	}
+	if o.onDeprecated != nil {
+		o.onDeprecated(ci.name, ci.deprecation)
+		return
+	}
+	if o.logger != nil {
+		o.logger.Warn("deprecated method called", "method", ci.name, "note", ci.deprecation)
+		return
+	}
+	log.Printf("funcutil: %s is deprecated: %s", ci.name, ci.deprecation)
+}
+
+// deprecationSuffix returns the deprecation note appended to the method signature
+func deprecationSuffix(deprecated bool, note string) string {
+	if !deprecated {
+		return ""
+	}
+	if note == "" {
+		return " // Deprecated"
+	}
+	return " // Deprecated: " + note
+}
@@ -0,0 +1,41 @@
+package funcutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeprecate(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	if err := f.Deprecate("service.Info", "use service.Status"); err != nil {
+		t.Fatal(err)
+	}
+	warned := []string{}
+	f.OnDeprecatedCall(func(name, note string) {
+		warned = append(warned, name+": "+note)
+	})
+	if _, err := f.Call("service.Info"); err != nil {
+		t.Error(err)
+	}
+	f.Call("service.Run")
+	if len(warned) != 1 || warned[0] != "service.Info: use service.Status" {
+		t.Errorf("Unexpected warnings %v", warned)
+	}
+	mi, _ := f.Info("service.Info")
+	if !mi.Deprecated || mi.Deprecation != "use service.Status" {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+	found := false
+	for _, sig := range f.Dump() {
+		if strings.HasPrefix(sig, "service.Info(") {
+			found = strings.HasSuffix(sig, "// Deprecated: use service.Status")
+		}
+	}
+	if !found {
+		t.Errorf("Dump should flag the deprecation %v", f.Dump())
+	}
+	if err := f.Deprecate("service.NotExists", ""); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown method")
+	}
+}
@@ -0,0 +1,15 @@
+package funcutil
+
+// dispatchMethod is the name of the method generated by funcutilgen
+const dispatchMethod = "FuncutilDispatch"
+
+// Dispatcher is implemented by the code generated with funcutilgen.
+// FuncutilDispatch calls the method by its plain name without reflection and
+// reports false when it can not handle the call (e.g. the params need conversion),
+// in which case Call falls back to the reflection path.
+//
+//		//go:generate funcutilgen -type=service
+//
+type Dispatcher interface {
+	FuncutilDispatch(method string, params []interface{}) ([]interface{}, bool)
+}
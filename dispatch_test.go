@@ -0,0 +1,50 @@
+package funcutil
+
+import "testing"
+
+type counter struct {
+	n          int
+	dispatched int
+}
+
+func (c *counter) Add(n int) int {
+	c.n += n
+	return c.n
+}
+
+// FuncutilDispatch is what funcutilgen generates for counter
+func (c *counter) FuncutilDispatch(method string, params []interface{}) ([]interface{}, bool) {
+	c.dispatched++
+	switch method {
+	case "Add":
+		if len(params) != 1 {
+			return nil, false
+		}
+		p0, ok := params[0].(int)
+		if !ok {
+			return nil, false
+		}
+		r0 := c.Add(p0)
+		return []interface{}{r0}, true
+	}
+	return nil, false
+}
+
+func TestDispatcher(t *testing.T) {
+	c := &counter{}
+	f := New()
+	f.Register(c)
+	if len(f.Dump()) != 1 {
+		t.Error("Registered methods should be 1")
+	}
+	if rets, err := f.Call("counter.Add", 2); err != nil || rets[0] != 2 {
+		t.Errorf("Should be 2 got %v %v", rets, err)
+	}
+	// needs conversion so it falls back to reflection
+	if rets, err := f.Call("counter.Add", int8(3)); err != nil || rets[0] != 5 {
+		t.Errorf("Should be 5 got %v %v", rets, err)
+	}
+	if c.dispatched != 2 {
+		t.Errorf("Should be dispatched 2 times got %d", c.dispatched)
+	}
+}
@@ -0,0 +1,46 @@
+package funcutil
+
+import (
+	"context"
+	"reflect"
+)
+
+// Validate checks the method can be called with the params without calling it, e.g.
+// to reject the bad requests early. The caller is authorized and the params are
+// converted and validated like Call does, the hooks are not run.
+func (f *FuncUtil) Validate(methodName string, params ...interface{}) error {
+	return f.ValidateContext(context.Background(), methodName, params...)
+}
+
+// ValidateContext is Validate for the calls made by CallContext
+func (f *FuncUtil) ValidateContext(ctx context.Context, methodName string, params ...interface{}) error {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return opts.mapError(methodName, err)
+	}
+	return opts.mapError(ci.name, opts.check(ctx, ci, params))
+}
+
+// check runs the steps of invoke preceding the call
+func (o options) check(ctx context.Context, ci callInfo, params []interface{}) error {
+	if err := o.authorize(ctx, ci.name); err != nil {
+		return err
+	}
+	if err := ci.checkRoles(ctx); err != nil {
+		return err
+	}
+	params = ci.withContext(ctx, params)
+	params, err := o.inject(ci, params)
+	if err != nil {
+		return err
+	}
+	params = ci.withDefaults(params)
+	if len(params) != len(ci.argTypes) {
+		return ErrArgCount{Want: len(ci.argTypes), Got: len(params)}
+	}
+	args, err := o.withContext(ctx).convertArgs(ci, params, make([]reflect.Value, 0, len(params)))
+	if err != nil {
+		return err
+	}
+	return o.validate(args)
+}
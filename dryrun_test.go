@@ -0,0 +1,43 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	f := New()
+	r := &registry{}
+	f.Register(r, &calculator{})
+	if err := f.Validate("registry.Add", User{Name: "gopher", Age: 10, Address: Address{"Bali"}}); err != nil {
+		t.Error(err)
+	}
+	if len(r.users) != 0 {
+		t.Error("method should not be called")
+	}
+	if _, ok := f.Validate("registry.Add", User{Name: "a long name"}).(*ValidationError); !ok {
+		t.Error("should failed due to invalid user")
+	}
+	if err := f.Validate("calculator.Add", 1, "two"); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to parameters mismatch got %v", err)
+	}
+	if err := f.Validate("calculator.Add", 1); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to parameters mismatch got %v", err)
+	}
+	if err := f.Validate("calculator.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	f.SetConversionMode(Strict)
+	if err := f.Validate("calculator.Add", 1, 2); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to strict conversion got %v", err)
+	}
+	ctx := ContextWithConversionMode(context.Background(), Lenient)
+	if err := f.ValidateContext(ctx, "calculator.Add", 1, 2); err != nil {
+		t.Error(err)
+	}
+	f.Require("calculator.Add", "admin")
+	if err := f.ValidateContext(ctx, "calculator.Add", 1, 2); !errors.Is(err, ErrForbidden) {
+		t.Errorf("should failed due to missing role got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package funcutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var (
+	ErrEnvelopeTooLarge = errors.New("Envelope too large")
+)
+
+// CallRequest is the envelope of a call shipped over a byte transport
+type CallRequest struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	// Credential is given to the authenticator, see Authenticate
+	Credential string `json:"credential,omitempty"`
+}
+
+// CallResponse is the envelope of the outcome of a call, the errors returned by the
+// method are replaced by their message like in ResultValues
+type CallResponse struct {
+	Results []interface{} `json:"results"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// EnvelopeEncoding is the encoding of the call envelopes
+type EnvelopeEncoding int
+
+const (
+	// EnvelopeGob encodes the envelopes with encoding/gob, the types of the struct
+	// params and results must be registered with gob.Register
+	EnvelopeGob EnvelopeEncoding = iota
+	// EnvelopeJSON encodes the envelopes as JSON objects
+	EnvelopeJSON
+)
+
+// MaxEnvelopeSize is the size limit of the decoded envelopes
+var MaxEnvelopeSize = 16 << 20
+
+// EncodeCall writes the envelope of the call to w, see DecodeAndCall. The envelopes
+// are prefixed by their size so a stream can carry any number of them.
+//
+//	funcutil.EncodeCall(conn, funcutil.EnvelopeJSON, "service.Stop", true)
+//	resp, err := funcutil.DecodeResponse(conn, funcutil.EnvelopeJSON)
+func EncodeCall(w io.Writer, e EnvelopeEncoding, methodName string, params ...interface{}) error {
+	return EncodeRequest(w, e, CallRequest{Method: methodName, Params: params})
+}
+
+// EncodeRequest writes the envelope of the call to w, see EncodeCall
+func EncodeRequest(w io.Writer, e EnvelopeEncoding, req CallRequest) error {
+	if req.Params == nil {
+		req.Params = []interface{}{}
+	}
+	return writeEnvelope(w, e, &req)
+}
+
+// DecodeCall reads the envelope of a call from r
+func DecodeCall(r io.Reader, e EnvelopeEncoding) (CallRequest, error) {
+	req := CallRequest{}
+	err := readEnvelope(r, e, &req)
+	return req, err
+}
+
+// EncodeResponse writes the envelope of the outcome of a call to w
+func EncodeResponse(w io.Writer, e EnvelopeEncoding, resp CallResponse) error {
+	if resp.Results == nil {
+		resp.Results = []interface{}{}
+	}
+	return writeEnvelope(w, e, &resp)
+}
+
+// DecodeResponse reads the envelope of the outcome of a call from r
+func DecodeResponse(r io.Reader, e EnvelopeEncoding) (CallResponse, error) {
+	resp := CallResponse{}
+	err := readEnvelope(r, e, &resp)
+	return resp, err
+}
+
+// Handle authenticates the credential of the request, invokes the method and returns
+// its outcome
+func (f *FuncUtil) Handle(req CallRequest) CallResponse {
+	ctx, err := f.Authenticate(context.Background(), req.Credential)
+	if err != nil {
+		return CallResponse{Error: err.Error()}
+	}
+	rets, err := f.CallContext(ctx, req.Method, req.Params...)
+	if err != nil {
+		return CallResponse{Error: err.Error()}
+	}
+	return CallResponse{Results: ResultValues(rets)}
+}
+
+// DecodeAndCall reads the envelope of a call from r, invokes the method and writes
+// the envelope of its outcome to w. The error is returned when the envelopes can't
+// be read or written, the call errors are sent in the response.
+func (f *FuncUtil) DecodeAndCall(r io.Reader, w io.Writer, e EnvelopeEncoding) error {
+	req, err := DecodeCall(r, e)
+	if err != nil {
+		return err
+	}
+	return EncodeResponse(w, e, f.Handle(req))
+}
+
+// writeEnvelope writes the size of the encoded envelope followed by its content
+func writeEnvelope(w io.Writer, e EnvelopeEncoding, v interface{}) error {
+	buf := bytes.NewBuffer(make([]byte, 4, 256))
+	var err error
+	if e == EnvelopeJSON {
+		err = json.NewEncoder(buf).Encode(v)
+	} else {
+		err = gob.NewEncoder(buf).Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	_, err = w.Write(b)
+	return err
+}
+
+// readEnvelope reads an envelope written by writeEnvelope into v
+func readEnvelope(r io.Reader, e EnvelopeEncoding, v interface{}) error {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if uint64(n) > uint64(MaxEnvelopeSize) {
+		return ErrEnvelopeTooLarge
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return err
+	}
+	if e == EnvelopeJSON {
+		return json.Unmarshal(b, v)
+	}
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
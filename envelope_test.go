@@ -0,0 +1,61 @@
+package funcutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"testing"
+)
+
+type Coords struct {
+	X, Y int
+}
+
+type geometry struct{}
+
+func (g *geometry) Move(c Coords, dx int) Coords {
+	return Coords{c.X + dx, c.Y}
+}
+
+func TestEnvelope(t *testing.T) {
+	gob.Register(Coords{})
+	f := New()
+	f.Register(&geometry{}, &calculator{})
+	for _, e := range []EnvelopeEncoding{EnvelopeGob, EnvelopeJSON} {
+		in, out := &bytes.Buffer{}, &bytes.Buffer{}
+		// a stream carries several envelopes
+		EncodeCall(in, e, "calculator.Add", 1, 2)
+		EncodeCall(in, e, "geometry.Move", Coords{1, 2}, 3)
+		EncodeCall(in, e, "calculator.Sum", []float64{}, 1.0)
+		EncodeCall(in, e, "calculator.NotExists")
+		for in.Len() > 0 {
+			if err := f.DecodeAndCall(in, out, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		resp, err := DecodeResponse(out, e)
+		if err != nil || len(resp.Results) != 1 || resp.Results[0] != int64(3) && resp.Results[0] != 3.0 {
+			t.Errorf("%d: Should be 3 got %v %v", e, resp, err)
+		}
+		resp, _ = DecodeResponse(out, e)
+		if c, ok := resp.Results[0].(Coords); e == EnvelopeGob && (!ok || c.X != 4) {
+			t.Errorf("%d: Should be {4 2} got %v", e, resp)
+		}
+		if m, ok := resp.Results[0].(map[string]interface{}); e == EnvelopeJSON && (!ok || m["X"] != 4.0) {
+			t.Errorf("%d: Should be {4 2} got %v", e, resp)
+		}
+		resp, _ = DecodeResponse(out, e)
+		if resp.Error != "" || resp.Results[1] != "no values" {
+			t.Errorf("%d: Should be no values got %v", e, resp)
+		}
+		resp, _ = DecodeResponse(out, e)
+		if resp.Error != ErrMethodNotFound.Error() {
+			t.Errorf("%d: Should be method not found got %v", e, resp)
+		}
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(MaxEnvelopeSize+1))
+	if _, err := DecodeCall(bytes.NewReader(b), EnvelopeJSON); err != ErrEnvelopeTooLarge {
+		t.Errorf("should failed due to envelope size got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package funcutil
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+// ErrorMapper replaces the errors returned by the calls of the method, e.g. to keep
+// the internal details from the remote callers
+type ErrorMapper func(methodName string, err error) error
+
+// ErrorTemplateData is the data of the error templates
+type ErrorTemplateData struct {
+	Method string
+	Err    error
+}
+
+type errorTemplate struct {
+	target error
+	tmpl   *template.Template
+}
+
+// mappedError is the message of the error template, it unwraps to the original error
+// so the transports still map it to their status codes
+type mappedError struct {
+	msg string
+	err error
+}
+
+func (e mappedError) Error() string {
+	return e.msg
+}
+
+func (e mappedError) Unwrap() error {
+	return e.err
+}
+
+// SetErrorMapper replaces the errors of the calls by the mapper, after the error
+// templates are applied. Nil removes the mapper.
+func (f *FuncUtil) SetErrorMapper(m ErrorMapper) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.errorMapper = m
+	return nil
+}
+
+// SetErrorTemplate replaces the message of the errors matching target by the text
+// template tmpl executed with ErrorTemplateData, the latest template matching the error
+// is used. The replaced errors still match their original errors with errors.Is.
+//
+//	f.SetErrorTemplate(ErrParametersMismatch, "invalid arguments of {{.Method}}")
+//	f.SetErrorTemplate(ErrArgType{}, "invalid parameter {{.Err.Index}} of {{.Method}}")
+func (f *FuncUtil) SetErrorTemplate(target error, tmpl string) error {
+	t, err := template.New("error").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts = f.opts.clone()
+	f.opts.errorTemplates = append(f.opts.errorTemplates, errorTemplate{target: target, tmpl: t})
+	return nil
+}
+
+func (o options) hasErrorMapping() bool {
+	return len(o.errorTemplates) > 0 || o.errorMapper != nil
+}
+
+// mapError applies the error templates and the mapper to the error of the call
+func (o options) mapError(methodName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	for i := len(o.errorTemplates) - 1; i >= 0; i-- {
+		et := o.errorTemplates[i]
+		if !matchesTarget(err, et.target) {
+			continue
+		}
+		msg := &strings.Builder{}
+		if et.tmpl.Execute(msg, ErrorTemplateData{Method: methodName, Err: err}) == nil {
+			err = mappedError{msg: msg.String(), err: err}
+		}
+		break
+	}
+	if o.errorMapper != nil {
+		err = o.errorMapper(methodName, err)
+	}
+	return err
+}
+
+// matchesTarget reports whether err is target, or of its type for the error structs
+func matchesTarget(err, target error) bool {
+	switch target.(type) {
+	case ErrArgType:
+		var e ErrArgType
+		return errors.As(err, &e)
+	case ErrArgCount:
+		var e ErrArgCount
+		return errors.As(err, &e)
+	case ErrMissingRoles:
+		var e ErrMissingRoles
+		return errors.As(err, &e)
+	}
+	return errors.Is(err, target)
+}
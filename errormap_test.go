@@ -0,0 +1,90 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorTemplate(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	if err := f.SetErrorTemplate(ErrParametersMismatch, "invalid arguments of {{.Method}}"); err != nil {
+		t.Fatal(err)
+	}
+	f.SetErrorTemplate(ErrArgType{}, "invalid parameter {{.Err.Index}} of {{.Method}}")
+	f.SetErrorTemplate(ErrMethodNotFound, "unknown method {{.Method}}")
+	_, err := f.Call("calculator.Add", 1)
+	if err == nil || err.Error() != "invalid arguments of calculator.Add" || !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("Should be invalid arguments of calculator.Add got %v", err)
+	}
+	_, err = f.Call("calculator.Add", 1, "two")
+	if err == nil || err.Error() != "invalid parameter 1 of calculator.Add" {
+		t.Errorf("Should be invalid parameter 1 of calculator.Add got %v", err)
+	}
+	var argType ErrArgType
+	if !errors.As(err, &argType) || argType.Index != 1 {
+		t.Errorf("Should unwrap to ErrArgType got %v", err)
+	}
+	if _, err := f.Call("calculator.NotExists"); err == nil || err.Error() != "unknown method calculator.NotExists" {
+		t.Errorf("Should be unknown method got %v", err)
+	}
+	plan, _ := f.Compile("calculator.Add")
+	if _, err := plan.Invoke(1); err == nil || err.Error() != "invalid arguments of calculator.Add" {
+		t.Errorf("Should be invalid arguments of calculator.Add got %v", err)
+	}
+	// the errors returned by the methods are kept
+	if rets, _ := f.Call("calculator.Sum", []float64{}, 2.0); rets[1].(error).Error() != "no values" {
+		t.Errorf("Should be no values got %v", rets[1])
+	}
+	if err := f.SetErrorTemplate(ErrMethodNotFound, "{{.Method"); err == nil {
+		t.Error("should failed due to invalid template")
+	}
+}
+
+func TestErrorMapper(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	internal := errors.New("Internal error")
+	f.SetErrorMapper(func(name string, err error) error {
+		if errors.Is(err, ErrMethodNotFound) {
+			return err
+		}
+		return internal
+	})
+	if _, err := f.Call("calculator.Add", 1); err != internal {
+		t.Errorf("Should be internal error got %v", err)
+	}
+	if _, err := f.Call("calculator.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	if _, err := f.Call("calculator.Add", 1, 2); err != nil {
+		t.Error(err)
+	}
+	f.SetErrorMapper(nil)
+	if _, err := f.Call("calculator.Add", 1); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to parameters mismatch got %v", err)
+	}
+}
+
+func TestErrorTemplateCallHelpers(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	f.SetErrorTemplate(ErrMethodNotFound, "unknown method {{.Method}}")
+	var out struct{}
+	errs := map[string]error{}
+	_, errs["Call1"] = f.Call1("calculator.NotExists")
+	errs["CallScan"] = f.CallScan("calculator.NotExists", nil)
+	errs["CallDecode"] = f.CallDecode("calculator.NotExists", &out)
+	_, errs["CallMap"] = f.CallMap("calculator.NotExists")
+	_, errs["CallStream"] = f.CallStream("calculator.NotExists")
+	_, errs["CallProto"] = f.CallProto("calculator.NotExists")
+	for name, err := range errs {
+		if err == nil || err.Error() != "unknown method calculator.NotExists" || !errors.Is(err, ErrMethodNotFound) {
+			t.Errorf("%s: Should be unknown method got %v", name, err)
+		}
+	}
+	f.Freeze()
+	if ret, err := f.Call1("calculator.Add", 1, 2); err != nil || ret != int64(3) {
+		t.Errorf("Should be 3 got %v %v", ret, err)
+	}
+}
@@ -0,0 +1,85 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrArgCount is returned when the number of params doesn't match the parameters,
+// it matches ErrParametersMismatch with errors.Is
+type ErrArgCount struct {
+	Want int
+	Got  int
+}
+
+func (e ErrArgCount) Error() string {
+	return fmt.Sprintf("%v: expected %d got %d", ErrParametersMismatch, e.Want, e.Got)
+}
+
+func (e ErrArgCount) Is(target error) bool {
+	return target == ErrParametersMismatch
+}
+
+// ErrArgType is returned when a param can't be converted into its parameter type,
+// it matches ErrParametersMismatch with errors.Is and unwraps to the conversion error
+type ErrArgType struct {
+	Index int
+	Want  reflect.Type
+	// Got is nil for the nil params
+	Got reflect.Type
+	Err error
+}
+
+func (e ErrArgType) Error() string {
+	return fmt.Sprintf("arguments: parameter %d: %s", e.Index, strings.TrimPrefix(e.Err.Error(), "arguments: "))
+}
+
+func (e ErrArgType) Is(target error) bool {
+	return target == ErrParametersMismatch
+}
+
+func (e ErrArgType) Unwrap() error {
+	return e.Err
+}
+
+// argTypeError wraps the conversion error of the i-th param
+func argTypeError(i int, p interface{}, t reflect.Type, err error) error {
+	return ErrArgType{Index: i, Want: t, Got: reflect.TypeOf(p), Err: err}
+}
+
+// ErrMissingRoles is returned when the caller lacks the roles required by the method,
+// it matches ErrForbidden with errors.Is
+type ErrMissingRoles struct {
+	Method string
+	Roles  []string
+}
+
+func (e ErrMissingRoles) Error() string {
+	return fmt.Sprintf("%v: %s requires the roles %s", ErrForbidden, e.Method, strings.Join(e.Roles, ", "))
+}
+
+func (e ErrMissingRoles) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// ErrNonConforming is returned when the registry doesn't conform to the expected
+// methods, see Conforms
+type ErrNonConforming struct {
+	// Missing are the expected methods not registered
+	Missing []string
+	// Mismatched are the signatures of the registered methods not matching the
+	// expected ones
+	Mismatched []string
+}
+
+func (e ErrNonConforming) Error() string {
+	problems := []string{}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Mismatched) > 0 {
+		problems = append(problems, "mismatched "+strings.Join(e.Mismatched, ", "))
+	}
+	return "contract: " + strings.Join(problems, "; ")
+}
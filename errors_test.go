@@ -0,0 +1,39 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypedErrors(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	_, err := f.Call("calculator.Add", 1)
+	count := ErrArgCount{}
+	if !errors.As(err, &count) || count.Want != 2 || count.Got != 1 {
+		t.Errorf("Should be ErrArgCount got %v", err)
+	}
+	if !errors.Is(err, ErrParametersMismatch) {
+		t.Error("ErrArgCount should match ErrParametersMismatch")
+	}
+
+	_, err = f.Call("calculator.Add", 1, []int{})
+	argType := ErrArgType{}
+	if !errors.As(err, &argType) || argType.Index != 1 || argType.Want != reflect.TypeOf(int64(0)) || argType.Got != reflect.TypeOf([]int{}) {
+		t.Errorf("Should be ErrArgType got %v", err)
+	}
+	if !errors.Is(err, ErrParametersMismatch) {
+		t.Error("ErrArgType should match ErrParametersMismatch")
+	}
+	if err.Error() != "arguments: parameter 1: []int is not convertible to int64" {
+		t.Errorf("Unexpected message %v", err)
+	}
+
+	_, err = f.CallJSON("calculator.Add", []byte(`[1, "a"]`))
+	var syntax *json.UnmarshalTypeError
+	if !errors.As(err, &syntax) {
+		t.Errorf("Should unwrap to the json error got %v", err)
+	}
+}
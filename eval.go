@@ -0,0 +1,217 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrInvalidExpression = errors.New("Invalid expression")
+)
+
+// Eval parses the call expression and invokes the method like Call.
+// The arguments are literals: double quoted strings, numbers, true, false, null,
+// arrays and objects like in JSON, object keys may be left unquoted.
+// Integers are passed as int and the other numbers as float64.
+//
+//	f.Eval(`service.Stop(true)`)
+//	f.Eval(`users.Create({name: "Bob", tags: ["admin"]}, 3)`)
+func (f *FuncUtil) Eval(expr string) ([]interface{}, error) {
+	name, params, err := ParseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(name, params...)
+}
+
+// ParseExpression returns the method name and the params of the call expression, see Eval
+func ParseExpression(expr string) (string, []interface{}, error) {
+	p := &exprParser{s: expr}
+	p.skipSpace()
+	name := p.ident()
+	if name == "" {
+		return "", nil, p.errorf("method name expected")
+	}
+	p.skipSpace()
+	if !p.consume('(') {
+		return "", nil, p.errorf("( expected")
+	}
+	params := []interface{}{}
+	if err := p.list(')', func() error {
+		v, err := p.value()
+		params = append(params, v)
+		return err
+	}); err != nil {
+		return "", nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return "", nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return name, params, nil
+}
+
+// exprParser is a recursive descent parser of the call expressions
+type exprParser struct {
+	s   string
+	pos int
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at %d", ErrInvalidExpression, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips c if it is the next character
+func (p *exprParser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// ident reads a method name or an object key
+func (p *exprParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := rune(p.s[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("_.@-", c) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// list parses the comma separated items up to the closing character
+func (p *exprParser) list(closing byte, item func() error) error {
+	p.skipSpace()
+	if p.consume(closing) {
+		return nil
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.consume(closing) {
+			return nil
+		}
+		if !p.consume(',') {
+			return p.errorf("%q or , expected", closing)
+		}
+		p.skipSpace()
+	}
+}
+
+// value parses a literal
+func (p *exprParser) value() (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("value expected")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '"':
+		return p.str()
+	case c == '[':
+		p.pos++
+		items := []interface{}{}
+		err := p.list(']', func() error {
+			v, err := p.value()
+			items = append(items, v)
+			return err
+		})
+		return items, err
+	case c == '{':
+		p.pos++
+		entries := map[string]interface{}{}
+		err := p.list('}', func() error {
+			key, err := p.key()
+			if err != nil {
+				return err
+			}
+			entries[key], err = p.value()
+			return err
+		})
+		return entries, err
+	case c == '-' || c == '+' || (c >= '0' && c <= '9'):
+		return p.number()
+	}
+	start := p.pos
+	switch word := p.ident(); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null", "nil":
+		return nil, nil
+	}
+	p.pos = start
+	return nil, p.errorf("unexpected %q", p.s[p.pos:])
+}
+
+// key parses an object key followed by its colon
+func (p *exprParser) key() (string, error) {
+	var key string
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		var err error
+		if key, err = p.str(); err != nil {
+			return "", err
+		}
+	} else if key = p.ident(); key == "" {
+		return "", p.errorf("key expected")
+	}
+	p.skipSpace()
+	if !p.consume(':') {
+		return "", p.errorf(": expected")
+	}
+	return key, nil
+}
+
+// str parses a double quoted string with the Go escapes
+func (p *exprParser) str() (string, error) {
+	start := p.pos
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(p.s[start:p.pos])
+			if err != nil {
+				p.pos = start
+				return "", p.errorf("invalid string")
+			}
+			return s, nil
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+// number parses an int or a float64
+func (p *exprParser) number() (interface{}, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.s) && strings.IndexByte("0123456789.eE+-_xXabcdefABCDEF", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	lit := p.s[start:p.pos]
+	if i, err := strconv.ParseInt(lit, 0, 0); err == nil {
+		return int(i), nil
+	}
+	if v, err := strconv.ParseFloat(lit, 64); err == nil {
+		return v, nil
+	}
+	p.pos = start
+	return nil, p.errorf("invalid number %q", lit)
+}
@@ -0,0 +1,57 @@
+package funcutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	name, params, err := ParseExpression(` users.Create ( {name: "Bob \"B\"", "tags": ["admin", 1.5e2]}, -3, 0x10, true, null ) `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"name": `Bob "B"`, "tags": []interface{}{"admin", 150.0}},
+		-3, 16, true, nil,
+	}
+	if name != "users.Create" || !reflect.DeepEqual(params, expected) {
+		t.Errorf("Unexpected %s %#v", name, params)
+	}
+	if _, params, err := ParseExpression(`service.Run()`); err != nil || len(params) != 0 {
+		t.Errorf("Should be no params got %v %v", params, err)
+	}
+	for _, expr := range []string{
+		``,
+		`service.Stop`,
+		`service.Stop(true`,
+		`service.Stop(true) x`,
+		`service.Stop(yes)`,
+		`service.Stop("open)`,
+		`service.Stop([1 2])`,
+		`service.Stop({a 1})`,
+		`service.Stop(1.2.3)`,
+	} {
+		if _, _, err := ParseExpression(expr); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("%s should failed due to invalid expression got %v", expr, err)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	f := New()
+	s := &service{}
+	f.Register(s, &calculator{})
+	if _, err := f.Eval(`service.Stop(true)`); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Eval(`calculator.Add(40, 2)`); err != nil || rets[0] != int64(42) {
+		t.Errorf("Should be 42 got %v %v", rets, err)
+	}
+	if rets, err := f.Eval(`calculator.Sum([1, 2.5], 2)`); err != nil || rets[0] != 7.0 {
+		t.Errorf("Should be 7 got %v %v", rets, err)
+	}
+	if _, err := f.Eval(`service.NotExists()`); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,184 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrNotEventHandler = errors.New("Method must take exactly one parameter to handle events")
+)
+
+type eventBus struct {
+	sync.Mutex
+	subs    map[string][]string
+	queues  map[string]*eventQueue
+	pending sync.WaitGroup
+	onError func(topic, methodName string, err error)
+}
+
+// eventQueue keeps the async events of a topic in publishing order
+type eventQueue struct {
+	payloads []interface{}
+	running  bool
+}
+
+// Subscribe makes the registered method a handler of the topic.
+// The method must take the event payload as its only parameter.
+func (f *FuncUtil) Subscribe(topic, methodName string) error {
+	f.Lock()
+	ci, err := f.lookup(methodName)
+	f.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(ci.argTypes) != 1 {
+		return ErrNotEventHandler
+	}
+	f.events.Lock()
+	defer f.events.Unlock()
+	if f.events.subs == nil {
+		f.events.subs = map[string][]string{}
+	}
+	for _, name := range f.events.subs[topic] {
+		if name == methodName {
+			return nil
+		}
+	}
+	f.events.subs[topic] = append(f.events.subs[topic], methodName)
+	return nil
+}
+
+// Unsubscribe removes the method from the handlers of the topic
+func (f *FuncUtil) Unsubscribe(topic, methodName string) {
+	f.events.Lock()
+	defer f.events.Unlock()
+	names := []string{}
+	for _, name := range f.events.subs[topic] {
+		if name != methodName {
+			names = append(names, name)
+		}
+	}
+	f.events.subs[topic] = names
+}
+
+// OnEventError sets the function to be notified when a handler fails to handle
+// an event published with PublishAsync
+func (f *FuncUtil) OnEventError(fn func(topic, methodName string, err error)) {
+	f.events.Lock()
+	defer f.events.Unlock()
+	f.events.onError = fn
+}
+
+func (f *FuncUtil) subscribers(topic string) []string {
+	f.events.Lock()
+	defer f.events.Unlock()
+	return append([]string{}, f.events.subs[topic]...)
+}
+
+// deliver calls the handlers of the topic in subscription order
+func (f *FuncUtil) deliver(topic string, payload interface{}, onError func(topic, methodName string, err error)) {
+	for _, name := range f.subscribers(topic) {
+		if err := f.handle(name, payload); err != nil && onError != nil {
+			onError(topic, name, err)
+		}
+	}
+}
+
+// handle calls a handler with the payload, its panic is recovered and returned
+// as the error so the remaining handlers and events are still delivered
+func (f *FuncUtil) handle(methodName string, payload interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	rets, err := f.Call(methodName, payload)
+	if err == nil {
+		err = resultError(rets)
+	}
+	return err
+}
+
+// Publish delivers the payload to every handler of the topic and waits for them.
+// All the handlers are called, the first error is returned.
+func (f *FuncUtil) Publish(topic string, payload interface{}) error {
+	var firstErr error
+	f.deliver(topic, payload, func(topic, methodName string, err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	})
+	return firstErr
+}
+
+// PublishAsync queues the payload for delivery and returns immediately.
+// Events of the same topic are delivered in publishing order, failures and panics are reported
+// to the function set by OnEventError.
+func (f *FuncUtil) PublishAsync(topic string, payload interface{}) {
+	f.events.Lock()
+	if f.events.queues == nil {
+		f.events.queues = map[string]*eventQueue{}
+	}
+	q, exists := f.events.queues[topic]
+	if !exists {
+		q = &eventQueue{}
+		f.events.queues[topic] = q
+	}
+	f.events.pending.Add(1)
+	q.payloads = append(q.payloads, payload)
+	start := !q.running
+	q.running = true
+	f.events.Unlock()
+	if !start {
+		return
+	}
+	// submitted unlocked, the executor may wait for the drains in progress
+	err := f.published().opts.submit(PriorityNormal, func() {
+		f.drain(topic, q)
+	})
+	if err != nil {
+		// the publisher delivers the events when the executor is closed
+		f.drain(topic, q)
+	}
+}
+
+// drain delivers the queued events of the topic until the queue is empty
+func (f *FuncUtil) drain(topic string, q *eventQueue) {
+	drained := false
+	defer func() {
+		// a panicking error callback leaves the queue to the next PublishAsync
+		if !drained {
+			f.events.Lock()
+			q.running = false
+			f.events.Unlock()
+		}
+	}()
+	for {
+		f.events.Lock()
+		if len(q.payloads) == 0 {
+			q.running = false
+			drained = true
+			f.events.Unlock()
+			return
+		}
+		payload := q.payloads[0]
+		q.payloads = q.payloads[1:]
+		onError := f.events.onError
+		f.events.Unlock()
+
+		f.deliverQueued(topic, payload, onError)
+	}
+}
+
+// deliverQueued delivers a queued event and marks it delivered however it ends
+func (f *FuncUtil) deliverQueued(topic string, payload interface{}, onError func(topic, methodName string, err error)) {
+	defer f.events.pending.Done()
+	f.deliver(topic, payload, onError)
+}
+
+// WaitEvents blocks until all the events published with PublishAsync are delivered
+func (f *FuncUtil) WaitEvents() {
+	f.events.pending.Wait()
+}
@@ -0,0 +1,121 @@
+package funcutil
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type listener struct {
+	sync.Mutex
+	events []string
+}
+
+func (l *listener) HandleEvent(e string) error {
+	l.Lock()
+	defer l.Unlock()
+	if e == "bad" {
+		return errors.New("bad event")
+	}
+	if e == "panic" {
+		panic("panicking event")
+	}
+	l.events = append(l.events, e)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	f := New()
+	l := &listener{}
+	f.Register(l, &service{})
+	if err := f.Subscribe("events", "listener.HandleEvent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Subscribe("events", "service.Run"); err != ErrNotEventHandler {
+		t.Error("should failed due to missing event parameter")
+	}
+	if err := f.Subscribe("events", "listener.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	if err := f.Publish("events", "first"); err != nil {
+		t.Error(err)
+	}
+	if err := f.Publish("events", "bad"); err == nil {
+		t.Error("should failed due to bad event")
+	}
+	if err := f.Publish("nobody", "first"); err != nil {
+		t.Error(err)
+	}
+	f.Unsubscribe("events", "listener.HandleEvent")
+	f.Publish("events", "second")
+	if len(l.events) != 1 {
+		t.Errorf("Should receive 1 event got %d", len(l.events))
+	}
+}
+
+func TestPublishAsync(t *testing.T) {
+	f := New()
+	l := &listener{}
+	f.Register(l)
+	f.Subscribe("events", "listener.HandleEvent")
+	failures := 0
+	f.OnEventError(func(topic, methodName string, err error) {
+		failures++
+	})
+	expect := []string{"1", "2", "3", "4", "5"}
+	for _, e := range expect {
+		f.PublishAsync("events", e)
+	}
+	f.PublishAsync("events", "bad")
+	f.WaitEvents()
+	if len(l.events) != len(expect) {
+		t.Fatalf("Should receive %d events got %d", len(expect), len(l.events))
+	}
+	for i, e := range expect {
+		if l.events[i] != e {
+			t.Errorf("Should be %s got %s", e, l.events[i])
+		}
+	}
+	if failures != 1 {
+		t.Errorf("Should fail 1 time got %d", failures)
+	}
+}
+
+func TestPublishAsyncPanic(t *testing.T) {
+	for _, pooled := range []bool{false, true} {
+		f := New()
+		l := &listener{}
+		f.Register(l)
+		f.Subscribe("events", "listener.HandleEvent")
+		if pooled {
+			pool := NewPool(2, 10)
+			defer pool.Close()
+			f.SetExecutor(pool)
+		}
+		var mu sync.Mutex
+		failures := []error{}
+		f.OnEventError(func(topic, methodName string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, err)
+		})
+		f.PublishAsync("events", "panic")
+		f.WaitEvents()
+		f.PublishAsync("events", "1")
+		f.PublishAsync("events", "panic")
+		f.PublishAsync("events", "2")
+		f.WaitEvents()
+		if len(l.events) != 2 {
+			t.Errorf("Should receive 2 events got %d", len(l.events))
+		}
+		if len(failures) != 2 {
+			t.Errorf("Should fail 2 times got %d", len(failures))
+		}
+	}
+	f := New()
+	f.Register(&listener{})
+	f.Subscribe("events", "listener.HandleEvent")
+	if err := f.Publish("events", "panic"); err == nil {
+		t.Error("should failed due to panicking handler")
+	}
+}
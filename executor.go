@@ -0,0 +1,321 @@
+package funcutil
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrPoolClosed = errors.New("Pool is closed")
+)
+
+// Executor runs the asynchronous calls of CallAsync and CallBatch and the deliveries
+// of the events published by PublishAsync
+type Executor interface {
+	Submit(task func()) error
+}
+
+// Priority orders the tasks queued by a PriorityExecutor, the higher priorities run first
+type Priority int
+
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 10
+)
+
+// PriorityExecutor is an Executor running the queued tasks by priority, the tasks of
+// the same priority run in submission order
+type PriorityExecutor interface {
+	Executor
+	SubmitPriority(p Priority, task func()) error
+}
+
+// ExecutorFunc adapts a function to Executor
+type ExecutorFunc func(task func()) error
+
+func (fn ExecutorFunc) Submit(task func()) error {
+	return fn(task)
+}
+
+// goExecutor runs every task on its own goroutine, it is the default executor
+var goExecutor = ExecutorFunc(func(task func()) error {
+	go task()
+	return nil
+})
+
+// Pool is a bounded goroutine pool, the tasks wait in a bounded queue for the workers
+// and Submit blocks while the queue is full, so a flood of calls can't spawn unbounded
+// goroutines. The queued tasks run by priority, see SubmitPriority. The panics of the
+// tasks are recovered, see OnPanic.
+type Pool struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    taskQueue
+	// seq keeps the submission order of the tasks of the same priority
+	seq     uint64
+	size    int
+	closed  bool
+	workers sync.WaitGroup
+	onPanic func(err error)
+}
+
+// NewPool starts a pool of workers goroutines queueing up to size tasks, both are
+// at least 1
+//
+//	pool := funcutil.NewPool(runtime.NumCPU(), 1024)
+//	defer pool.Close()
+//	f.SetExecutor(pool)
+func NewPool(workers, size int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if size < 1 {
+		size = 1
+	}
+	p := &Pool{size: size}
+	p.notEmpty = sync.NewCond(&p.mu)
+	p.notFull = sync.NewCond(&p.mu)
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues the task with the normal priority, waiting while the queue is full
+func (p *Pool) Submit(task func()) error {
+	return p.SubmitPriority(PriorityNormal, task)
+}
+
+// SubmitPriority queues the task ahead of the queued tasks of lower priority, waiting
+// while the queue is full
+func (p *Pool) SubmitPriority(priority Priority, task func()) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for !p.closed && len(p.queue) >= p.size {
+		p.notFull.Wait()
+	}
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.seq++
+	heap.Push(&p.queue, queuedTask{priority: priority, seq: p.seq, run: task})
+	p.notEmpty.Signal()
+	return nil
+}
+
+// OnPanic sets the function receiving the panics of the tasks as errors
+func (p *Pool) OnPanic(fn func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onPanic = fn
+}
+
+// Close stops the pool after running the queued tasks
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.notEmpty.Broadcast()
+	p.notFull.Broadcast()
+	p.mu.Unlock()
+	p.workers.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.notEmpty.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		task := heap.Pop(&p.queue).(queuedTask)
+		p.notFull.Signal()
+		p.mu.Unlock()
+		p.run(task.run)
+	}
+}
+
+// run runs the task, its panic is recovered and reported so the worker keeps running
+func (p *Pool) run(task func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.mu.Lock()
+			onPanic := p.onPanic
+			p.mu.Unlock()
+			if onPanic != nil {
+				onPanic(fmt.Errorf("task panic: %v", r))
+			}
+		}
+	}()
+	task()
+}
+
+type queuedTask struct {
+	priority Priority
+	seq      uint64
+	run      func()
+}
+
+// taskQueue is the heap of the queued tasks
+type taskQueue []queuedTask
+
+func (q taskQueue) Len() int {
+	return len(q)
+}
+
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *taskQueue) Push(x interface{}) {
+	*q = append(*q, x.(queuedTask))
+}
+
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	t := old[len(old)-1]
+	old[len(old)-1] = queuedTask{}
+	*q = old[:len(old)-1]
+	return t
+}
+
+// SetExecutor runs the asynchronous calls and event deliveries on e, e.g. a Pool.
+// They run on their own goroutines by default, nil restores the default.
+// The tasks waiting for other tasks of a bounded executor, e.g. CallBatch within
+// CallAsync, may wait forever when all its workers are waiting.
+func (f *FuncUtil) SetExecutor(e Executor) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.executor = e
+	return nil
+}
+
+// submit runs the task on the executor, the priority is ignored unless it is a
+// PriorityExecutor
+func (o options) submit(priority Priority, task func()) error {
+	if o.executor == nil {
+		return goExecutor(task)
+	}
+	if pe, ok := o.executor.(PriorityExecutor); ok {
+		return pe.SubmitPriority(priority, task)
+	}
+	return o.executor.Submit(task)
+}
+
+// SetPriority sets the priority of the asynchronous calls of the method made by
+// CallAsync and CallBatch, e.g. to run the latency sensitive methods ahead of the
+// background ones when the pool is saturated
+func (f *FuncUtil) SetPriority(methodName string, priority Priority) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.priority = priority
+	return f.store(ci)
+}
+
+// priorityOf returns the priority set for the method
+func (f *FuncUtil) priorityOf(methodName string) (Priority, options) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return PriorityNormal, opts
+	}
+	return ci.priority, opts
+}
+
+// BatchCall is a call of CallBatch
+type BatchCall struct {
+	Method string
+	Params []interface{}
+}
+
+// CallAsync calls the method on the executor and returns the channel receiving its
+// reply, the panics of the method are replied as errors
+//
+//	reply := f.CallAsync("report.Generate", month)
+//	r := <-reply
+func (f *FuncUtil) CallAsync(methodName string, params ...interface{}) <-chan Reply {
+	return f.CallAsyncContext(context.Background(), methodName, params...)
+}
+
+// CallAsyncContext is CallAsync calling the method with CallContext
+func (f *FuncUtil) CallAsyncContext(ctx context.Context, methodName string, params ...interface{}) <-chan Reply {
+	priority, _ := f.priorityOf(methodName)
+	return f.callAsync(ctx, priority, methodName, params)
+}
+
+// CallAsyncWithPriority is CallAsync with the priority instead of the priority of the
+// method, see SetPriority
+func (f *FuncUtil) CallAsyncWithPriority(priority Priority, methodName string, params ...interface{}) <-chan Reply {
+	return f.callAsync(context.Background(), priority, methodName, params)
+}
+
+func (f *FuncUtil) callAsync(ctx context.Context, priority Priority, methodName string, params []interface{}) <-chan Reply {
+	reply := make(chan Reply, 1)
+	f.submitCall(ctx, priority, methodName, params, reply)
+	return reply
+}
+
+// submitCall sends the reply of the call made on the executor to the buffered reply
+func (f *FuncUtil) submitCall(ctx context.Context, priority Priority, methodName string, params []interface{}, reply chan<- Reply) {
+	task := func() {
+		reply <- f.tryCallContext(ctx, methodName, params)
+	}
+	if err := f.published().opts.submit(priority, task); err != nil {
+		reply <- Reply{Err: err}
+	}
+}
+
+// CallBatch runs the calls concurrently on the executor and returns their replies
+// in order once all of them are done
+func (f *FuncUtil) CallBatch(calls []BatchCall) []Reply {
+	replies := make([]Reply, len(calls))
+	var wg sync.WaitGroup
+	for i, c := range calls {
+		i, c := i, c
+		priority, opts := f.priorityOf(c.Method)
+		wg.Add(1)
+		err := opts.submit(priority, func() {
+			defer wg.Done()
+			replies[i] = f.tryCallContext(context.Background(), c.Method, c.Params)
+		})
+		if err != nil {
+			replies[i] = Reply{Err: err}
+			wg.Done()
+		}
+	}
+	wg.Wait()
+	return replies
+}
+
+// tryCallContext calls the method replying its panic as error
+func (f *FuncUtil) tryCallContext(ctx context.Context, methodName string, params []interface{}) (r Reply) {
+	defer func() {
+		if p := recover(); p != nil {
+			r = Reply{Err: fmt.Errorf("%s: panic: %v", methodName, p)}
+		}
+	}()
+	r.Results, r.Err = f.CallContext(ctx, methodName, params...)
+	return r
+}
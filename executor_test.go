@@ -0,0 +1,191 @@
+package funcutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool(2, 1)
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		err := p.Submit(func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&peak)
+				if n <= m || atomic.CompareAndSwapInt32(&peak, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("Should be at most 2 running got %d", peak)
+	}
+	p.Close()
+	if err := p.Submit(func() {}); err != ErrPoolClosed {
+		t.Errorf("should failed due to closed pool got %v", err)
+	}
+}
+
+func TestPoolMinimalQueue(t *testing.T) {
+	p := NewPool(1, 0)
+	defer p.Close()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	// the queue holds a single task
+	p.Submit(func() {})
+	submitted := make(chan struct{})
+	go func() {
+		p.Submit(func() {})
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Error("Should wait for room in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-submitted
+}
+
+func TestCallAsync(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &fragile{})
+	pool := NewPool(2, 4)
+	defer pool.Close()
+	f.SetExecutor(pool)
+	r := <-f.CallAsync("calculator.Add", 1, 2)
+	if r.Err != nil || r.Results[0] != int64(3) {
+		t.Errorf("Should be 3 got %v %v", r.Results, r.Err)
+	}
+	if r := <-f.CallAsync("fragile.Index", []int{}, 1); r.Err == nil {
+		t.Error("should failed due to panic")
+	}
+	replies := f.CallBatch([]BatchCall{
+		{Method: "calculator.Add", Params: []interface{}{1, 2}},
+		{Method: "calculator.NotExists"},
+		{Method: "calculator.Add", Params: []interface{}{3, 4}},
+	})
+	if len(replies) != 3 || replies[0].Results[0] != int64(3) || replies[1].Err != ErrMethodNotFound || replies[2].Results[0] != int64(7) {
+		t.Errorf("Should be 3, not found and 7 got %v", replies)
+	}
+
+	closed := NewPool(1, 1)
+	closed.Close()
+	f.SetExecutor(closed)
+	if r := <-f.CallAsync("calculator.Add", 1, 2); r.Err != ErrPoolClosed {
+		t.Errorf("should failed due to closed pool got %v", r.Err)
+	}
+	f.SetExecutor(nil)
+	if r := <-f.CallAsync("calculator.Add", 1, 2); r.Err != nil {
+		t.Error(r.Err)
+	}
+}
+
+func TestPublishAsyncExecutor(t *testing.T) {
+	f := New()
+	l := &listener{}
+	f.Register(l)
+	f.Subscribe("events", "listener.HandleEvent")
+	submitted := int32(0)
+	pool := NewPool(1, 1)
+	defer pool.Close()
+	f.SetExecutor(ExecutorFunc(func(task func()) error {
+		atomic.AddInt32(&submitted, 1)
+		return pool.Submit(task)
+	}))
+	for _, e := range []string{"first", "second", "third"} {
+		f.PublishAsync("events", e)
+	}
+	f.WaitEvents()
+	if atomic.LoadInt32(&submitted) == 0 || len(l.events) != 3 || l.events[2] != "third" {
+		t.Errorf("Should deliver 3 events in order on the executor got %v", l.events)
+	}
+}
+
+func TestPoolPriority(t *testing.T) {
+	p := NewPool(1, 10)
+	defer p.Close()
+	// hold the worker while the tasks are queued
+	hold := make(chan bool)
+	p.Submit(func() {
+		<-hold
+	})
+	order := make(chan string, 4)
+	p.SubmitPriority(PriorityLow, func() { order <- "low" })
+	p.Submit(func() { order <- "normal" })
+	p.SubmitPriority(PriorityHigh, func() { order <- "high" })
+	p.SubmitPriority(PriorityHigh, func() { order <- "high2" })
+	close(hold)
+	for _, expect := range []string{"high", "high2", "normal", "low"} {
+		if got := <-order; got != expect {
+			t.Errorf("Should be %s got %s", expect, got)
+		}
+	}
+}
+
+func TestCallAsyncWithPriority(t *testing.T) {
+	f := New()
+	l := &listener{}
+	f.Register(l)
+	pool := NewPool(1, 10)
+	defer pool.Close()
+	f.SetExecutor(pool)
+	if err := f.SetPriority("listener.HandleEvent", PriorityLow); err != nil {
+		t.Fatal(err)
+	}
+	hold := make(chan bool)
+	pool.Submit(func() {
+		<-hold
+	})
+	bulk := f.CallAsync("listener.HandleEvent", "bulk")
+	urgent := f.CallAsyncWithPriority(PriorityHigh, "listener.HandleEvent", "urgent")
+	close(hold)
+	<-bulk
+	<-urgent
+	if len(l.events) != 2 || l.events[0] != "urgent" {
+		t.Errorf("Should be urgent first got %v", l.events)
+	}
+	if err := f.SetPriority("listener.NotExists", PriorityHigh); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+func TestPoolPanic(t *testing.T) {
+	p := NewPool(1, 1)
+	defer p.Close()
+	reported := make(chan error, 1)
+	p.OnPanic(func(err error) {
+		reported <- err
+	})
+	p.Submit(func() {
+		panic("boom")
+	})
+	if err := <-reported; err == nil || err.Error() != "task panic: boom" {
+		t.Errorf("Should be task panic: boom got %v", err)
+	}
+	done := make(chan bool)
+	p.Submit(func() {
+		done <- true
+	})
+	if !<-done {
+		t.Error("the worker should keep running")
+	}
+}
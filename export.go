@@ -0,0 +1,93 @@
+package funcutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format is the output format of Export
+type Format int
+
+const (
+	// FormatText lists the signatures one per line like Dump
+	FormatText Format = iota
+	// FormatJSON is an array of method objects
+	FormatJSON
+	// FormatMarkdown is a table of the methods
+	FormatMarkdown
+)
+
+type exportedMethod struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Receiver  string   `json:"receiver,omitempty"`
+	Method    string   `json:"method,omitempty"`
+	Signature string   `json:"signature"`
+	Params    []string `json:"params"`
+	Results   []string `json:"results"`
+	Doc       string   `json:"doc,omitempty"`
+	// Deprecated holds the deprecation note, see Deprecate
+	Deprecated *string `json:"deprecated,omitempty"`
+}
+
+// Export describes all the registered methods sorted by name in the given format,
+// for generating service documentation
+func (f *FuncUtil) Export(format Format) ([]byte, error) {
+	methods := f.Methods()
+	b := &bytes.Buffer{}
+	switch format {
+	case FormatText:
+		for _, mi := range methods {
+			fmt.Fprintln(b, mi.Signature+deprecationSuffix(mi.Deprecated, mi.Deprecation))
+		}
+	case FormatJSON:
+		out := []exportedMethod{}
+		for _, mi := range methods {
+			em := exportedMethod{
+				Name:      mi.Name,
+				Namespace: mi.Namespace,
+				Receiver:  mi.Receiver,
+				Method:    mi.Method,
+				Signature: mi.Signature,
+				Params:    []string{},
+				Results:   []string{},
+				Doc:       mi.Doc,
+			}
+			if mi.Deprecated {
+				em.Deprecated = &mi.Deprecation
+			}
+			for _, t := range mi.Params {
+				em.Params = append(em.Params, t.String())
+			}
+			for _, t := range mi.Results {
+				em.Results = append(em.Results, t.String())
+			}
+			out = append(out, em)
+		}
+		return json.MarshalIndent(out, "", "  ")
+	case FormatMarkdown:
+		fmt.Fprintln(b, "| Method | Namespace | Signature | Description |")
+		fmt.Fprintln(b, "| --- | --- | --- | --- |")
+		for _, mi := range methods {
+			doc := mi.Doc
+			if mi.Deprecated {
+				doc = strings.TrimSpace("**Deprecated:** " + mi.Deprecation + " " + doc)
+			}
+			fmt.Fprintf(b, "| %s | %s | `%s` | %s |\n",
+				markdownEscape(mi.Name),
+				markdownEscape(mi.Namespace),
+				strings.TrimSpace(mi.Signature),
+				markdownEscape(strings.Replace(doc, "\n", " ", -1)))
+		}
+	default:
+		return nil, fmt.Errorf("unknown export format %d", format)
+	}
+	return b.Bytes(), nil
+}
+
+// markdownEscape keeps the text within its table cell
+func markdownEscape(s string) string {
+	return strings.Replace(s, "|", "\\|", -1)
+}
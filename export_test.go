@@ -0,0 +1,47 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	f := New("com.example")
+	f.Register(&service{})
+	f.SetDoc("com.example.service.Stop", "Stop stops | halts the service")
+
+	b, err := f.Export(FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 5 || lines[0] != "com.example.service.Info() string" {
+		t.Errorf("Unexpected text export %q", b)
+	}
+
+	b, err = f.Export(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := []map[string]interface{}{}
+	if err := json.Unmarshal(b, &methods); err != nil {
+		t.Fatal(err)
+	}
+	stop := methods[4]
+	if stop["name"] != "com.example.service.Stop" || stop["namespace"] != "com.example" || stop["params"].([]interface{})[0] != "bool" {
+		t.Errorf("Unexpected json export %v", stop)
+	}
+
+	b, err = f.Export(FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "| com.example.service.Stop | com.example | `com.example.service.Stop(bool)` | Stop stops \\| halts the service |"
+	if !strings.Contains(string(b), expect) {
+		t.Errorf("Should contain %s got %s", expect, b)
+	}
+
+	if _, err := f.Export(Format(42)); err == nil {
+		t.Error("should failed due to unknown format")
+	}
+}
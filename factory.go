@@ -0,0 +1,15 @@
+package funcutil
+
+// RegisterFactory registers the methods of the struct created by factory, which is
+// called for every call to get a fresh receiver (e.g. for request scoped state)
+// instead of sharing a single instance. The factory is called once at registration
+// to learn the type and must always return the same *struct type.
+//
+//	f.RegisterFactory(func() interface{} {
+//		return &request{started: time.Now()}
+//	})
+func (f *FuncUtil) RegisterFactory(factory func() interface{}) error {
+	f.Lock()
+	defer f.Unlock()
+	return f.register(factory(), registration{ns: f.ns, factory: factory})
+}
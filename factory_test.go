@@ -0,0 +1,54 @@
+package funcutil
+
+import "testing"
+
+type request struct {
+	seen []string
+}
+
+func (r *request) Add(s string) int {
+	r.seen = append(r.seen, s)
+	return len(r.seen)
+}
+
+func TestRegisterFactory(t *testing.T) {
+	f := New()
+	created := 0
+	err := f.RegisterFactory(func() interface{} {
+		created++
+		return &request{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if rets, err := f.Call("request.Add", "x"); err != nil || rets[0] != 1 {
+			t.Errorf("Should be 1 got %v %v", rets, err)
+		}
+	}
+	plan, _ := f.Compile("request.Add")
+	if rets, err := plan.Invoke("x"); err != nil || rets[0] != 1 {
+		t.Errorf("Should be 1 got %v %v", rets, err)
+	}
+	if created != 5 {
+		t.Errorf("Should create 5 instances got %d", created)
+	}
+	if err := f.RegisterFactory(func() interface{} { return request{} }); err != ErrNotStructPointer {
+		t.Error("should failed due to non pointer instance")
+	}
+}
+
+func TestFactoryTypeMismatch(t *testing.T) {
+	f := New()
+	first := true
+	f.RegisterFactory(func() interface{} {
+		if first {
+			first = false
+			return &request{}
+		}
+		return &Monitor{}
+	})
+	if _, err := f.Call("request.Add", "x"); err != ErrTypeMismatch {
+		t.Errorf("should failed due to different type got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package funcutil
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// MethodFilter selects the methods of the registered structs
+type MethodFilter func(m reflect.Method) bool
+
+// RegisterFiltered registers only the exported methods of v selected by allow, so
+// the large structs expose an intentional subset of their methods
+//
+//	f.RegisterFiltered(&service{}, funcutil.NameMatches(`^(Start|Stop)$`))
+func (f *FuncUtil) RegisterFiltered(v interface{}, allow MethodFilter) error {
+	f.Lock()
+	defer f.Unlock()
+	return f.register(v, registration{ns: f.ns, allow: allow})
+}
+
+// NameMatches selects the methods whose names match the regular expression, it
+// panics when the expression doesn't compile
+func NameMatches(expr string) MethodFilter {
+	re := regexp.MustCompile(expr)
+	return func(m reflect.Method) bool {
+		return re.MatchString(m.Name)
+	}
+}
+
+// Arity selects the methods taking in parameters and returning out results, a
+// negative count matches any
+func Arity(in, out int) MethodFilter {
+	return func(m reflect.Method) bool {
+		// the method type includes the receiver
+		t := m.Type
+		return (in < 0 || t.NumIn()-1 == in) && (out < 0 || t.NumOut() == out)
+	}
+}
+
+// ReturnsError selects the methods whose last result is an error
+func ReturnsError() MethodFilter {
+	return func(m reflect.Method) bool {
+		n := m.Type.NumOut()
+		return n > 0 && m.Type.Out(n-1) == errorType
+	}
+}
+
+// Not selects the methods not selected by filter
+func Not(filter MethodFilter) MethodFilter {
+	return func(m reflect.Method) bool {
+		return !filter(m)
+	}
+}
+
+// All selects the methods selected by all the filters
+func All(filters ...MethodFilter) MethodFilter {
+	return func(m reflect.Method) bool {
+		for _, filter := range filters {
+			if !filter(m) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,35 @@
+package funcutil
+
+import (
+	"testing"
+)
+
+func TestRegisterFiltered(t *testing.T) {
+	f := New()
+	if err := f.RegisterFiltered(&service{}, NameMatches(`^(Run|Stop)$`)); err != nil {
+		t.Fatal(err)
+	}
+	if f.Len() != 2 || !f.Has("service.Run") || !f.Has("service.Stop") {
+		t.Errorf("Should be service.Run and service.Stop got %v", f.Dump())
+	}
+
+	f = New()
+	f.RegisterFiltered(&calculator{}, Arity(2, -1))
+	if f.Len() != 2 || !f.Has("calculator.Add") || !f.Has("calculator.Sum") {
+		t.Errorf("Should be calculator.Add and calculator.Sum got %v", f.Dump())
+	}
+
+	f = New()
+	f.RegisterFiltered(&calculator{}, All(Arity(-1, 1), Not(ReturnsError())))
+	if f.Len() != 2 || !f.Has("calculator.Add") || !f.Has("calculator.Describe") {
+		t.Errorf("Should be calculator.Add and calculator.Describe got %v", f.Dump())
+	}
+	f = New()
+	f.RegisterFiltered(&calculator{}, ReturnsError())
+	if f.Len() != 1 || !f.Has("calculator.Sum") {
+		t.Errorf("Should be calculator.Sum got %v", f.Dump())
+	}
+	if err := f.RegisterFiltered(calculator{}, ReturnsError()); err != ErrNotStructPointer {
+		t.Errorf("should failed due to non pointer got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package funcutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrFingerprintMismatch = errors.New("Fingerprint mismatches")
+)
+
+// Fingerprint returns a stable hash of the names and the parameter and result types
+// of all the registered methods. Registries exposing the same methods have the same
+// fingerprint regardless the registration order.
+func (f *FuncUtil) Fingerprint() string {
+	f.Lock()
+	defer f.Unlock()
+	names := make([]string, 0, len(f.calls))
+	for name := range f.calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		ci := f.calls[name]
+		params := make([]string, len(ci.argTypes))
+		for i, t := range ci.argTypes {
+			params[i] = t.String()
+		}
+		results := make([]string, len(ci.retTypes))
+		for i, t := range ci.retTypes {
+			results[i] = t.String()
+		}
+		fmt.Fprintf(h, "%s(%s)(%s)\n", name, strings.Join(params, ","), strings.Join(results, ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyFingerprint checks the registry has the expected fingerprint, e.g. the one
+// of the remote registry, so the drift is detected before making calls
+func (f *FuncUtil) VerifyFingerprint(expected string) error {
+	if actual := f.Fingerprint(); actual != expected {
+		return fmt.Errorf("%w: expected %s got %s", ErrFingerprintMismatch, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := New()
+	a.Register(&service{}, &calculator{})
+	b := New()
+	b.Register(&calculator{}, &service{})
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("fingerprints should match")
+	}
+	if err := b.VerifyFingerprint(a.Fingerprint()); err != nil {
+		t.Error(err)
+	}
+	b.RegisterMap(map[string]interface{}{"extra": func() {}})
+	if err := b.VerifyFingerprint(a.Fingerprint()); !errors.Is(err, ErrFingerprintMismatch) {
+		t.Error("should failed due to extra method")
+	}
+	c := New()
+	c.RegisterMap(map[string]interface{}{"calc": func(v []int) {}})
+	d := New()
+	d.RegisterMap(map[string]interface{}{"calc": func(v []string) {}})
+	if c.Fingerprint() == d.Fingerprint() {
+		t.Error("fingerprints should differ by parameter types")
+	}
+}
@@ -0,0 +1,41 @@
+package funcutil
+
+import "errors"
+
+var ErrFrozen = errors.New("Registry is frozen")
+
+// Freeze makes the registry read-only for serving, the calls then look the methods up
+// without locking. The lazy structs are constructed, the registrations and all the
+// setters fail with ErrFrozen afterwards. Clone returns an unfrozen copy.
+//
+//	f.Register(&service{})
+//	f.Freeze()
+//	http.ListenAndServe(":8080", httpapi.New(f))
+func (f *FuncUtil) Freeze() error {
+	f.Lock()
+	defer f.Unlock()
+	if f.frozen.Load() {
+		return nil
+	}
+	prefixes := make([]string, 0, len(f.lazy))
+	for prefix := range f.lazy {
+		prefixes = append(prefixes, prefix)
+	}
+	for _, prefix := range prefixes {
+		// any method name constructs the struct
+		if _, err := f.find(prefix + "."); err != nil && err != ErrMethodNotFound {
+			return err
+		}
+	}
+	// the signatures are cached at the first use
+	for name := range f.calls {
+		f.signatureOf(name)
+	}
+	f.frozen.Store(true)
+	return nil
+}
+
+// Frozen reports whether the registry is frozen
+func (f *FuncUtil) Frozen() bool {
+	return f.frozen.Load()
+}
@@ -0,0 +1,58 @@
+package funcutil
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.RegisterLazy("Monitor", func() interface{} {
+		return &Monitor{}
+	})
+	if err := f.Freeze(); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Frozen() {
+		t.Error("Should be frozen")
+	}
+	if len(f.MethodsByPrefix("Monitor.")) != 1 {
+		t.Error("lazy struct should be constructed")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Call("Monitor.Display"); err != nil {
+				t.Error(err)
+			}
+			f.Info("service.Stop")
+		}()
+	}
+	wg.Wait()
+	if err := f.RegisterFiltered(&calculator{}, nil); err != ErrFrozen {
+		t.Errorf("should failed due to frozen registry got %v", err)
+	}
+	if err := f.SetDoc("service.Stop", "stops"); err != ErrFrozen {
+		t.Errorf("should failed due to frozen registry got %v", err)
+	}
+	if _, err := f.Call("service.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	if err := f.OnSlowCall(0, nil); err != ErrFrozen {
+		t.Errorf("should failed due to frozen registry got %v", err)
+	}
+	if err := f.WithLogger(nil); err != ErrFrozen {
+		t.Errorf("should failed due to frozen registry got %v", err)
+	}
+	c := f.Clone()
+	if c.Frozen() {
+		t.Error("Should not be frozen")
+	}
+	c.Register(&calculator{})
+	if !c.Has("calculator.Add") || f.Has("calculator.Add") {
+		t.Error("Should be registered to the clone only")
+	}
+}
@@ -0,0 +1,140 @@
+// Package funcdoc attaches the godoc comments of the registered methods to a funcutil registry.
+//
+// It parses the Go source of the packages declaring the registered structs and sets
+// the documentation of every method it finds, so it shows up in MethodInfo and the
+// generated API documents.
+//
+//	f := funcutil.New()
+//	f.Register(&service{})
+//	funcdoc.Attach(f, "./service")
+package funcdoc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// key of a method documentation: <receiver type>.<method>
+func key(receiver, method string) string {
+	return receiver + "." + method
+}
+
+// methods calls fn for every method declared in the Go source of dir
+func methods(dir string, fn func(receiver string, fd *ast.FuncDecl)) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+					continue
+				}
+				t := fd.Recv.List[0].Type
+				if star, ok := t.(*ast.StarExpr); ok {
+					t = star.X
+				}
+				id, ok := t.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				fn(id.Name, fd)
+			}
+		}
+	}
+	return nil
+}
+
+// Parse returns the doc comments of the methods declared in the Go source of dir,
+// keyed by <receiver type>.<method>
+func Parse(dir string) (map[string]string, error) {
+	docs := map[string]string{}
+	err := methods(dir, func(receiver string, fd *ast.FuncDecl) {
+		if fd.Doc != nil {
+			docs[key(receiver, fd.Name.Name)] = strings.TrimSpace(fd.Doc.Text())
+		}
+	})
+	return docs, err
+}
+
+// ParseResultNames returns the names of the named results of the methods declared
+// in the Go source of dir, keyed by <receiver type>.<method>
+func ParseResultNames(dir string) (map[string][]string, error) {
+	names := map[string][]string{}
+	err := methods(dir, func(receiver string, fd *ast.FuncDecl) {
+		results := fd.Type.Results
+		if results == nil || len(results.List) == 0 || len(results.List[0].Names) == 0 {
+			return
+		}
+		n := []string{}
+		for _, field := range results.List {
+			for _, name := range field.Names {
+				n = append(n, name.Name)
+			}
+		}
+		names[key(receiver, fd.Name.Name)] = n
+	})
+	return names, err
+}
+
+// Attach sets the documentation of the registered methods found in the source
+// directories, it returns the number of documented methods
+func Attach(f *funcutil.FuncUtil, dirs ...string) (int, error) {
+	docs := map[string]string{}
+	for _, dir := range dirs {
+		d, err := Parse(dir)
+		if err != nil {
+			return 0, err
+		}
+		for k, v := range d {
+			docs[k] = v
+		}
+	}
+	n := 0
+	for _, mi := range f.Methods() {
+		doc, exists := docs[key(mi.Receiver, mi.Method)]
+		if !exists || mi.Receiver == "" {
+			continue
+		}
+		if err := f.SetDoc(mi.Name, doc); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// AttachResultNames names the results of the registered methods declaring named
+// results in the source directories, see funcutil.SetResultNames. It returns the
+// number of methods whose results are named.
+func AttachResultNames(f *funcutil.FuncUtil, dirs ...string) (int, error) {
+	names := map[string][]string{}
+	for _, dir := range dirs {
+		n, err := ParseResultNames(dir)
+		if err != nil {
+			return 0, err
+		}
+		for k, v := range n {
+			names[k] = v
+		}
+	}
+	n := 0
+	for _, mi := range f.Methods() {
+		results, exists := names[key(mi.Receiver, mi.Method)]
+		if !exists || mi.Receiver == "" || len(results) != len(mi.Results) {
+			continue
+		}
+		if err := f.SetResultNames(mi.Name, results...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
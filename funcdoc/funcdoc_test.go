@@ -0,0 +1,72 @@
+package funcdoc
+
+import (
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type service struct {
+	running bool
+}
+
+// Run starts the service.
+func (s *service) Run() {
+	s.running = true
+}
+
+// Running reports whether
+// the service is running.
+func (s service) Running() bool {
+	return s.running
+}
+
+func (s *service) Stop() {
+	s.running = false
+}
+
+func TestAttach(t *testing.T) {
+	f := funcutil.New("example")
+	f.Register(&service{})
+	n, err := Attach(f, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Should document 2 methods got %d", n)
+	}
+	mi, _ := f.Info("example.service.Running")
+	if mi.Doc != "Running reports whether\nthe service is running." {
+		t.Errorf("Unexpected doc %q", mi.Doc)
+	}
+	mi, _ = f.Info("example.service.Stop")
+	if mi.Doc != "" {
+		t.Errorf("Should be undocumented got %q", mi.Doc)
+	}
+	if _, err := Attach(f, "./notexists"); err == nil {
+		t.Error("should failed due to missing directory")
+	}
+}
+
+func (s *service) Status() (running bool, err error) {
+	return s.running, nil
+}
+
+func TestAttachResultNames(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&service{})
+	n, err := AttachResultNames(f, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Should name 1 method got %d", n)
+	}
+	res, err := f.CallMap("service.Status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["running"] != false || res["err"] != nil {
+		t.Errorf("Unexpected results %v", res)
+	}
+}
@@ -0,0 +1,48 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterMap registers plain functions by name, the registry namespace is
+// prefixed to the names. Nothing is registered when any value is not a function.
+//
+//	f.RegisterMap(map[string]interface{}{
+//		"config.reload": reload,
+//		"config.get":    get,
+//	})
+func (f *FuncUtil) RegisterMap(funcs map[string]interface{}) error {
+	return f.registerMap(funcs, f.ns)
+}
+
+func (f *FuncUtil) registerMap(funcs map[string]interface{}, ns string) error {
+	for name, fn := range funcs {
+		if fn == nil || reflect.TypeOf(fn).Kind() != reflect.Func {
+			return fmt.Errorf("%s: %v is not a function", name, reflect.TypeOf(fn))
+		}
+	}
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	for name, fn := range funcs {
+		if ns != "" {
+			name = ns + "." + name
+		}
+		v := reflect.ValueOf(fn)
+		// the signature is generated at the first use
+		f.calls[name] = callInfo{
+			name:     name,
+			ns:       ns,
+			argTypes: f.getArgumentTypes(v.Type()),
+			retTypes: f.getReturnTypes(v.Type()),
+			fn:       v,
+		}
+	}
+	if f.opts.logger != nil {
+		f.opts.logger.Info("registered", "namespace", ns, "functions", len(funcs))
+	}
+	return nil
+}
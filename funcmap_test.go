@@ -0,0 +1,47 @@
+package funcutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterMap(t *testing.T) {
+	f := New("app")
+	reloaded := false
+	err := f.RegisterMap(map[string]interface{}{
+		"config.reload": func() { reloaded = true },
+		"config.upper":  strings.ToUpper,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Call("app.config.reload"); err != nil || !reloaded {
+		t.Errorf("config should be reloaded %v", err)
+	}
+	if rets, err := f.Call("app.config.upper", "hello"); err != nil || rets[0] != "HELLO" {
+		t.Errorf("Should be HELLO got %v %v", rets, err)
+	}
+	plan, _ := f.Compile("app.config.upper")
+	if rets, err := plan.Invoke("plan"); err != nil || rets[0] != "PLAN" {
+		t.Errorf("Should be PLAN got %v %v", rets, err)
+	}
+	found := false
+	for _, s := range f.Dump() {
+		if s == "app.config.upper(string) string" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Should dump app.config.upper(string) string")
+	}
+	err = f.RegisterMap(map[string]interface{}{
+		"config.set": func(string) {},
+		"config.bad": "not a function",
+	})
+	if err == nil {
+		t.Error("should failed due to non function value")
+	}
+	if _, err := f.Call("app.config.set", "x"); err != ErrMethodNotFound {
+		t.Error("nothing should be registered")
+	}
+}
@@ -30,50 +30,135 @@
 package funcutil
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type callInfo struct {
-	argTypes  []reflect.Type
-	retTypes  []reflect.Type
+	name string
+	ns   string
+	// argTypes excludes the receiver
+	argTypes []reflect.Type
+	// argNames are the names of the parameters if known
+	argNames []string
+	retTypes []reflect.Type
+	// retNames are the names of the results if known
+	retNames []string
+	// m and v are the method and its receiver, both are unset for plain functions
 	m         *reflect.Method
 	v         reflect.Value
+	fn        reflect.Value
+	factory   func() interface{}
 	signature string
+	defaults  []interface{}
+	doc       string
+	retry     *RetryPolicy
+	breaker   *breaker
+	limit     *limiter
+	worker    *worker
+	// deprecation is the note of the deprecated methods
+	deprecated  bool
+	deprecation string
+	// promotedFrom is the name of the embedded type declaring the method
+	promotedFrom string
+	// roles are required from the callers, see Require
+	roles []string
+	// tags are attached by Tag
+	tags []string
+	// priority orders the asynchronous calls, see SetPriority
+	priority Priority
+	// pacer debounces or throttles the calls, see Debounce and Throttle
+	pacer *pacer
+	// memo caches the results, see Memoize
+	memo *memoCache
 }
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 var (
-	ErrMethodNotFound = errors.New("Method not found")
+	ErrMethodNotFound     = errors.New("Method not found")
+	ErrParametersMismatch = errors.New("Parameters mismatches")
+	ErrResultsMismatch    = errors.New("Results mismatches")
+	ErrNotStructPointer   = errors.New("Type must be kind of *struct")
 )
 
-func (mi *callInfo) parametersMatch(params ...interface{}) error {
-	var paramTypes []reflect.Type
-	if len(mi.argTypes) > 1 {
-		paramTypes = mi.argTypes[1:]
+type FuncUtil struct {
+	sync.RWMutex
+	calls  map[string]callInfo
+	lazy   map[string]*lazyStruct
+	ns     string
+	events eventBus
+	opts   options
+	// versionPolicy and defaultVersions pick the version of the unversioned calls
+	versionPolicy   VersionPolicy
+	defaultVersions map[string]string
+	// aliases maps the namespace aliases to their namespaces, see AliasNamespace
+	aliases map[string]string
+	// promoted tells how the promoted methods are registered
+	promoted PromotedPolicy
+	// typeNameFormat names the generic structs, see SetTypeNameFormat
+	typeNameFormat TypeNameFormat
+	// frozen registries are read without locking, see Freeze
+	frozen atomic.Bool
+	// table is read by the calls, see published
+	table atomic.Pointer[table]
+}
+
+// options holds the configuration applied to every call,
+// call plans take a copy of them when compiled.
+// Their maps and slices are copied on write, the calls in flight keep using the
+// options they started with.
+type options struct {
+	hooks      hooks
+	validator  Validator
+	converters map[reflect.Type]Converter
+	providers  map[reflect.Type]Provider
+	audit      *auditLogger
+	recorder   *callRecorder
+	// executor is nil for the goroutine per task, see SetExecutor
+	executor Executor
+	// captureWriters injects the io.Writer params, see CaptureWriters
+	captureWriters bool
+	bytesEncoding  BytesEncoding
+	proto          ProtoCodec
+	mode           ConversionMode
+	noImplicit     bool
+	authenticator  Authenticator
+	authorizer     Authorizer
+	// onDeprecated is nil for the default logging
+	onDeprecated DeprecatedCallFunc
+	// logger is nil for the silent registries, see WithLogger
+	logger *slog.Logger
+	// errorTemplates and errorMapper replace the errors of the calls, see mapError
+	errorTemplates []errorTemplate
+	errorMapper    ErrorMapper
+	slow           slowCall
+}
+
+func (o options) clone() options {
+	o.hooks = hooks{
+		before: append([]BeforeCallFunc{}, o.hooks.before...),
+		after:  append([]AfterCallFunc{}, o.hooks.after...),
 	}
-	if len(params) != len(paramTypes) {
-		return errors.New("Parameters mismatches")
+	converters := map[reflect.Type]Converter{}
+	for t, c := range o.converters {
+		converters[t] = c
 	}
-	for i, p := range paramTypes {
-		pt := reflect.TypeOf(params[i])
-		if pt == p {
-			continue
-		}
-		if !pt.ConvertibleTo(p) {
-			return fmt.Errorf("arguments: %v is not convertible to %v", pt, p)
-		}
+	o.converters = converters
+	providers := map[reflect.Type]Provider{}
+	for t, p := range o.providers {
+		providers[t] = p
 	}
-	return nil
-}
-
-type FuncUtil struct {
-	sync.Mutex
-	calls map[string]callInfo
-	ns    string
+	o.providers = providers
+	o.errorTemplates = append([]errorTemplate{}, o.errorTemplates...)
+	return o
 }
 
 func (f *FuncUtil) getReturnTypes(t reflect.Type) []reflect.Type {
@@ -100,11 +185,8 @@ func (f *FuncUtil) getArgumentTypes(t reflect.Type) []reflect.Type {
 
 func (f *FuncUtil) generateSignature(name string, ci callInfo) string {
 	args := []string{}
-	if len(ci.argTypes) > 1 {
-		argTypes := ci.argTypes[1:]
-		for _, t := range argTypes {
-			args = append(args, t.Name())
-		}
+	for _, t := range ci.argTypes {
+		args = append(args, t.Name())
 	}
 	rets := []string{}
 	if len(ci.retTypes) > 0 {
@@ -119,8 +201,33 @@ func (f *FuncUtil) generateSignature(name string, ci callInfo) string {
 	return fmt.Sprintf("%s(%s) %s", name, strings.Join(args, ","), ret)
 }
 
-func (f *FuncUtil) register(s interface{}) {
+// registration tells how to register a struct
+type registration struct {
+	ns string
+	// allow selects the methods when not nil
+	allow func(m reflect.Method) bool
+	// factory creates the receiver of every call when not nil
+	factory func() interface{}
+	// version is appended to the method names when not empty
+	version string
+}
+
+// register registers the exported methods of s
+func (f *FuncUtil) register(s interface{}, r registration) (err error) {
+	if f.opts.logger != nil {
+		defer func() {
+			if err != nil {
+				f.opts.logger.Error("register failed", "type", reflect.TypeOf(s), "error", err)
+			}
+		}()
+	}
+	if err := f.change(); err != nil {
+		return err
+	}
 	t := reflect.TypeOf(s)
+	if t == nil {
+		return ErrNotStructPointer
+	}
 	// element type
 	et := t
 	v := reflect.ValueOf(s)
@@ -128,8 +235,10 @@ func (f *FuncUtil) register(s interface{}) {
 		et = t.Elem()
 	}
 	if !(et.Kind() == reflect.Struct && t.Kind() == reflect.Ptr) {
-		log.Fatal("Type must be kind of *struct")
+		return ErrNotStructPointer
 	}
+	namer, named := s.(MethodNamer)
+	registered := 0
 	// enum methods
 	for i := 0; i < t.NumMethod(); i++ {
 		m := t.Method(i)
@@ -137,85 +246,351 @@ func (f *FuncUtil) register(s interface{}) {
 		if m.PkgPath != "" {
 			continue
 		}
+		if r.allow != nil && !r.allow(m) {
+			continue
+		}
+		// the generated dispatcher and the namer are not service methods
+		if m.Name == dispatchMethod || (named && m.Name == namerMethod) {
+			continue
+		}
 		// normalize the name regardless the receiver type
 		namespace := ""
-		if f.ns != "" {
-			namespace = f.ns + "."
+		if r.ns != "" {
+			namespace = r.ns + "."
+		}
+		typeName := f.typeName(et)
+		origin, promoted := promotedFrom(t, m.Name)
+		if promoted {
+			switch f.promoted {
+			case PromotedSkip:
+				continue
+			case PromotedEmbedded:
+				typeName = f.typeName(origin)
+			}
+		}
+		mn := fmt.Sprintf("%s%s.%s", namespace, typeName, m.Name)
+		if named {
+			exposed := namer.FuncutilName(m.Name)
+			if exposed == "" {
+				continue
+			}
+			mn = namespace + exposed
+		}
+		if r.version != "" {
+			mn += "@" + r.version
 		}
-		mn := fmt.Sprintf("%s%s.%s", namespace, et.Name(), m.Name)
 		funcType := m.Func.Type()
-		argTypes := f.getArgumentTypes(funcType)
+		// exclude the receiver type
+		argTypes := f.getArgumentTypes(funcType)[1:]
 		retTypes := f.getReturnTypes(funcType)
 		mi := callInfo{
+			name:     mn,
+			ns:       r.ns,
 			argTypes: argTypes,
 			retTypes: retTypes,
 			m:        &m,
 			v:        v,
+			fn:       m.Func,
+			factory:  r.factory,
+		}
+		if promoted {
+			mi.promotedFrom = f.typeName(origin)
 		}
 		mi.signature = f.generateSignature(mn, mi)
 		f.calls[mn] = mi
+		registered++
 	}
+	if f.opts.logger != nil {
+		f.opts.logger.Info("registered", "type", t, "namespace", r.ns, "methods", registered)
+	}
+	return nil
 }
 
 // Register registers the structs that implement the some exported methods.
-// Each struct in vars must be pointer type
-func (f *FuncUtil) Register(vars ...interface{}) {
+// Each struct in vars must be pointer type, the registration stops at the first failure.
+func (f *FuncUtil) Register(vars ...interface{}) error {
 	f.Lock()
 	defer f.Unlock()
+	return f.registerAll(vars, f.ns)
+}
+
+// RegisterNS registers the structs like Register under the namespace ns instead of
+// the registry namespace, e.g. f.RegisterNS("billing", &service{}) registers
+// billing.service.* whatever the namespace given to New
+func (f *FuncUtil) RegisterNS(ns string, vars ...interface{}) error {
+	f.Lock()
+	defer f.Unlock()
+	return f.registerAll(vars, ns)
+}
+
+// registerAll registers the structs under the namespace until one fails.
+// The caller must hold the lock.
+func (f *FuncUtil) registerAll(vars []interface{}, ns string) error {
 	for _, s := range vars {
-		f.register(s)
+		if err := f.register(s, registration{ns: ns}); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // Call invokes the registered methods using the matching arguments
 // Argument type could be converted if they are convertible
 func (f *FuncUtil) Call(methodName string, params ...interface{}) ([]interface{}, error) {
+	return f.CallContext(context.Background(), methodName, params...)
+}
+
+// CallInto works like Call but stores the returned values into results instead of
+// allocating a new slice, results must have room for all of them.
+func (f *FuncUtil) CallInto(methodName string, results []interface{}, params ...interface{}) error {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return opts.mapError(methodName, err)
+	}
+	if len(results) < opts.resultCount(ci) {
+		return opts.mapError(methodName, ErrResultsMismatch)
+	}
+	return opts.invoke(context.Background(), ci, params, results)
+}
+
+// resolve returns the method and the options to call it with. The lock is released
+// before the method is invoked, so the methods may call the registry themselves.
+func (f *FuncUtil) resolve(methodName string) (callInfo, options, error) {
+	if f.frozen.Load() {
+		ci, err := f.lookup(methodName)
+		return ci, f.opts, err
+	}
+	t := f.published()
+	if ci, exists := t.lookup(methodName); exists {
+		return ci, t.opts, nil
+	}
+	if !t.lazy {
+		return callInfo{}, t.opts, ErrMethodNotFound
+	}
+	// the lazy lookups update the registry
 	f.Lock()
 	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	return ci, f.opts, err
+}
 
-	ci, exists := f.calls[methodName]
-	if !exists {
-		return nil, ErrMethodNotFound
+var argsPool = sync.Pool{
+	New: func() interface{} {
+		args := make([]reflect.Value, 0, 8)
+		return &args
+	},
+}
+
+// invoke calls the method and stores the returned values into results
+func (o options) invoke(ctx context.Context, ci callInfo, params []interface{}, results []interface{}) (err error) {
+	// the audit and the logger get the original errors
+	if o.hasErrorMapping() {
+		defer func() {
+			err = o.mapError(ci.name, err)
+		}()
+	}
+	// the audit log and the recorder get the converted args once they are converted
+	logged := params
+	if o.audit != nil {
+		defer func(start time.Time) {
+			o.audit.record(ctx, ci.name, logged, start, results, &err)
+		}(time.Now())
+	}
+	if o.recorder != nil {
+		defer func(start time.Time) {
+			o.recorder.record(ci.name, logged, start, results, &err)
+		}(time.Now())
+	}
+	if o.slow.fn != nil {
+		defer o.slow.check(ci.name, time.Now(), params)
+	}
+	if o.logger != nil {
+		done := o.logCall(ci.name, params)
+		defer func() {
+			done(results, err)
+		}()
+	}
+	if len(o.hooks.after) > 0 {
+		defer func() {
+			o.hooks.runAfter(ci.name, results, err)
+		}()
+	}
+	if err := o.authorize(ctx, ci.name); err != nil {
+		return err
+	}
+	if err := ci.checkRoles(ctx); err != nil {
+		return err
+	}
+	if now, err := o.pace(ctx, ci, params); !now {
+		return err
+	}
+	// the results are cached by the params of the caller
+	var key string
+	if ci.memo != nil {
+		key = ci.memo.key(params)
+	}
+	o.warnDeprecated(ci)
+	params = ci.withContext(ctx, params)
+	if params, err = o.inject(ci, params); err != nil {
+		return err
+	}
+	params = ci.withDefaults(params)
+	if err := o.hooks.runBefore(ci.name, params); err != nil {
+		return err
 	}
-	err := ci.parametersMatch(params...)
+	if len(params) != len(ci.argTypes) {
+		return ErrArgCount{Want: len(ci.argTypes), Got: len(params)}
+	}
+	recv, err := ci.receiver()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	// exclude the receiver type
-	argTypes := ci.argTypes[1:]
+	argsPtr := argsPool.Get().(*[]reflect.Value)
+	callParams := *argsPtr
 	// make first argument receiver value
-	callParams := []reflect.Value{ci.v}
+	if recv.IsValid() {
+		callParams = append(callParams, recv)
+	}
+	defer func() {
+		// don't keep the arguments alive in the pool
+		for i := range callParams {
+			callParams[i] = reflect.Value{}
+		}
+		*argsPtr = callParams[:0]
+		argsPool.Put(argsPtr)
+	}()
 	// construct the rest arguments from supplied params
-	for i, p := range params {
-		serviceParamType := argTypes[i]
-		callParamType := reflect.TypeOf(p)
-		v := reflect.ValueOf(p)
-		if callParamType != serviceParamType {
-			// try to convert if they are convertible
-			if callParamType.ConvertibleTo(serviceParamType) {
-				v = v.Convert(serviceParamType)
+	callParams, err = o.withContext(ctx).convertArgs(ci, params, callParams)
+	if err != nil {
+		return err
+	}
+	if o.audit != nil || o.recorder != nil {
+		logged = o.loggedArgs(ci, callParams[len(callParams)-len(params):])
+	}
+	if err := o.validate(callParams[len(callParams)-len(params):]); err != nil {
+		return err
+	}
+	// the cached results went through the hooks and the validation, not the limit
+	// and the breaker guarding the method
+	if ci.memo != nil {
+		if ci.memo.load(key, results) {
+			return nil
+		}
+		defer func() {
+			if err == nil {
+				ci.memo.save(key, results)
+			}
+		}()
+	}
+	if err := ci.limit.acquire(ctx); err != nil {
+		return err
+	}
+	defer ci.limit.release()
+	if err := ci.breaker.allow(); err != nil {
+		return err
+	}
+	// the panicking calls are failures
+	returned := false
+	if ci.breaker != nil {
+		defer func() {
+			failure := resultError(results[:len(ci.retTypes)])
+			if !returned {
+				failure = errPanicked
 			}
+			ci.breaker.done(failure)
+		}()
+	}
+	d, dispatched := dispatcher(recv)
+	w := ci.worker.outside(ctx)
+	for attempt := 1; ; attempt++ {
+		w.run(func() {
+			// use the generated dispatcher when available
+			handled := false
+			if dispatched {
+				var rets []interface{}
+				if rets, handled = d.FuncutilDispatch(ci.m.Name, params); handled {
+					copy(results, rets)
+				}
+			}
+			if !handled {
+				// calls the method
+				ci.storeResults(ci.fn.Call(callParams), results)
+			}
+		})
+		o.storeCaptured(ci, params, results)
+		if !ci.retry.retry(ctx, ci.name, attempt, resultError(results[:len(ci.retTypes)])) {
+			returned = true
+			return nil
 		}
-		callParams = append(callParams, v)
 	}
-	// calls the method
-	rets := ci.m.Func.Call(callParams)
-	// verify the returned values whether they are compatible and convertible
-	retValues := []interface{}{}
+}
+
+// receiver returns the receiver of the method, a new one for the factory registrations
+func (mi *callInfo) receiver() (reflect.Value, error) {
+	if mi.factory == nil {
+		return mi.v, nil
+	}
+	v := reflect.ValueOf(mi.factory())
+	if !v.IsValid() || v.Type() != mi.v.Type() {
+		return v, ErrTypeMismatch
+	}
+	return v, nil
+}
+
+// dispatcher returns the generated dispatcher of the receiver if any
+func dispatcher(recv reflect.Value) (Dispatcher, bool) {
+	if !recv.IsValid() {
+		return nil, false
+	}
+	d, ok := recv.Interface().(Dispatcher)
+	return d, ok
+}
+
+// results converts the returned values into their declared types
+func (mi *callInfo) results(rets []reflect.Value) []interface{} {
+	if len(rets) == 0 {
+		return nil
+	}
+	retValues := make([]interface{}, len(rets))
+	mi.storeResults(rets, retValues)
+	return retValues
+}
+
+// storeResults converts the returned values into their declared types and stores them into out
+func (mi *callInfo) storeResults(rets []reflect.Value, out []interface{}) {
 	for i, ret := range rets {
-		retType := ci.retTypes[i]
-		retValues = append(retValues, ret.Convert(retType).Interface())
+		out[i] = ret.Convert(mi.retTypes[i]).Interface()
+	}
+}
+
+// resultError returns the first non nil error within the returned values
+func resultError(rets []interface{}) error {
+	for _, ret := range rets {
+		if err, ok := ret.(error); ok && err != nil {
+			return err
+		}
 	}
-	if len(retValues) > 0 {
-		return retValues, nil
+	return nil
+}
+
+// signatureOf returns the signature of the method, generating it at the first use
+func (f *FuncUtil) signatureOf(name string) string {
+	ci := f.calls[name]
+	if ci.signature == "" {
+		ci.signature = f.generateSignature(name, ci)
+		f.calls[name] = ci
 	}
-	return nil, nil
+	return ci.signature
 }
 
+// Dump returns the signatures of all the registered methods
 func (f *FuncUtil) Dump() []string {
+	f.Lock()
+	defer f.Unlock()
 	services := []string{}
-	for _, v := range f.calls {
-		services = append(services, v.signature)
+	for name := range f.calls {
+		ci := f.calls[name]
+		services = append(services, f.signatureOf(name)+deprecationSuffix(ci.deprecated, ci.deprecation))
 	}
 	return services
 }
@@ -227,7 +602,16 @@ func New(vars ...string) *FuncUtil {
 		ns = vars[0]
 	}
 	return &FuncUtil{
-		calls: map[string]callInfo{},
-		ns:    ns,
+		calls:           map[string]callInfo{},
+		lazy:            map[string]*lazyStruct{},
+		ns:              ns,
+		defaultVersions: map[string]string{},
+		aliases:         map[string]string{},
+		opts: options{
+			validator:  TagValidator{},
+			converters: defaultConverters(),
+			providers:  map[reflect.Type]Provider{},
+			proto:      defaultProtoCodec,
+		},
 	}
 }
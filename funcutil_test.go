@@ -2,7 +2,9 @@ package funcutil
 
 import (
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 )
 
 type service struct {
@@ -69,6 +71,24 @@ func TestMethodCalls(t *testing.T) {
 	}
 }
 
+func TestCallInto(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &Monitor{})
+	results := make([]interface{}, 1)
+	if err := f.CallInto("Monitor.Display", results); err != nil {
+		t.Error(err)
+	}
+	if results[0] != "Display()" {
+		t.Error("Should be Display()")
+	}
+	if err := f.CallInto("Monitor.Display", nil); err != ErrResultsMismatch {
+		t.Error("should failed due to missing room for results")
+	}
+	if err := f.CallInto("service.Stop", nil, 12); err == nil {
+		t.Error("should failed due to wrong argument type")
+	}
+}
+
 func createMonitor() Monitor {
 	return Monitor{}
 }
@@ -95,3 +115,100 @@ func TestNamespace(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterNS(t *testing.T) {
+	f := New("com.example.device")
+	f.Register(&service{})
+	f.RegisterNS("billing", &Monitor{})
+	f.RegisterNS("", &calculator{})
+	for _, name := range []string{"com.example.device.service.Run", "billing.Monitor.Display", "calculator.Add"} {
+		if !f.Has(name) {
+			t.Errorf("%s should exists", name)
+		}
+	}
+	if mi, _ := f.Info("billing.Monitor.Display"); mi.Namespace != "billing" {
+		t.Errorf("Should be billing got %s", mi.Namespace)
+	}
+	if f.Has("com.example.device.Monitor.Display") {
+		t.Error("Monitor should not be registered under the registry namespace")
+	}
+}
+
+func BenchmarkCallInto(b *testing.B) {
+	f := New()
+	f.Register(&Arith{})
+	reply := 0
+	args := &Args{7, 8}
+	results := make([]interface{}, 1)
+	for i := 0; i < b.N; i++ {
+		f.CallInto("Arith.Multiply", results, args, &reply)
+	}
+}
+
+type nested struct {
+	f *FuncUtil
+}
+
+func (n *nested) Depth(depth int) (int, error) {
+	if depth == 0 {
+		return 0, nil
+	}
+	rets, err := n.f.Call("nested.Depth", depth-1)
+	if err != nil {
+		return 0, err
+	}
+	return rets[0].(int) + 1, nil
+}
+
+func (n *nested) Register() error {
+	n.f.Register(&service{})
+	_, err := n.f.Call("service.Stop", true)
+	return err
+}
+
+func (n *nested) Wait(started, done chan struct{}) {
+	started <- struct{}{}
+	<-done
+}
+
+func TestNestedCalls(t *testing.T) {
+	f := New()
+	n := &nested{f: f}
+	f.Register(n)
+	if rets, err := f.Call("nested.Depth", 5); err != nil || rets[0] != 5 || rets[1] != nil {
+		t.Errorf("Should be 5 got %v %v", rets, err)
+	}
+	results := make([]interface{}, 2)
+	if err := f.CallInto("nested.Depth", results, 3); err != nil || results[0] != 3 {
+		t.Errorf("Should be 3 got %v %v", results, err)
+	}
+	if all, err := f.CallAll("nested.D*", 2); err != nil || all["nested.Depth"][0] != 2 {
+		t.Errorf("Should be 2 got %v %v", all, err)
+	}
+	// the registry can be changed by a running method
+	if rets, err := f.Call("nested.Register"); err != nil || rets[0] != nil {
+		t.Errorf("Should be nil got %v %v", rets, err)
+	}
+
+	// a running method doesn't block the other calls
+	started, done := make(chan struct{}), make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f.Call("nested.Wait", started, done)
+	}()
+	<-started
+	finished := make(chan struct{})
+	go func() {
+		f.Call("nested.Depth", 1)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("call should not wait for the running method")
+	}
+	close(done)
+	wg.Wait()
+}
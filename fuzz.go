@@ -0,0 +1,178 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"runtime/debug"
+)
+
+// maxGenerateDepth limits the nesting of the generated pointers, slices and structs
+const maxGenerateDepth = 3
+
+// FuzzFailure is a call panicking with the generated params
+type FuzzFailure struct {
+	Method string
+	Params []interface{}
+	Panic  interface{}
+	Stack  string
+}
+
+func (e FuzzFailure) Error() string {
+	return fmt.Sprintf("%s%v panics: %v", e.Method, e.Params, e.Panic)
+}
+
+// GenerateArgs returns random params valid for the parameters of the method, nil if
+// the method is not registered. The numbers favor the edge values like zero, the
+// limits of their types and NaN.
+//
+//	r := rand.New(rand.NewSource(1))
+//	f.Call("calculator.Add", f.GenerateArgs("calculator.Add", r)...)
+func (f *FuncUtil) GenerateArgs(methodName string, r *rand.Rand) []interface{} {
+	ci, _, err := f.resolve(methodName)
+	if err != nil {
+		return nil
+	}
+	params := make([]interface{}, len(ci.argTypes))
+	for i, t := range ci.argTypes {
+		params[i] = randomValue(t, r, 0).Interface()
+	}
+	return params
+}
+
+// Fuzz calls every registered method rounds times with generated params and
+// returns the calls that panicked, e.g. from a test
+//
+//	for _, failure := range f.Fuzz(rand.New(rand.NewSource(1)), 100) {
+//		t.Error(failure, failure.Stack)
+//	}
+func (f *FuncUtil) Fuzz(r *rand.Rand, rounds int) []FuzzFailure {
+	failures := []FuzzFailure{}
+	for _, mi := range f.Methods() {
+		for i := 0; i < rounds; i++ {
+			params := f.GenerateArgs(mi.Name, r)
+			if failure, panicked := f.tryCall(mi.Name, params); panicked {
+				failures = append(failures, failure)
+				// the first panic is enough
+				break
+			}
+		}
+	}
+	return failures
+}
+
+// tryCall calls the method recovering its panic
+func (f *FuncUtil) tryCall(methodName string, params []interface{}) (failure FuzzFailure, panicked bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			failure = FuzzFailure{Method: methodName, Params: params, Panic: p, Stack: string(debug.Stack())}
+			panicked = true
+		}
+	}()
+	f.Call(methodName, params...)
+	return
+}
+
+var (
+	edgeInts   = []int64{0, 1, -1, math.MinInt64, math.MaxInt64}
+	edgeUints  = []uint64{0, 1, math.MaxUint64}
+	edgeFloats = []float64{0, -1, math.NaN(), math.Inf(1), math.Inf(-1), math.SmallestNonzeroFloat64}
+	alphabet   = []rune("abcXYZ019 _-./\\\"'\n\x00é世😀")
+)
+
+// randomValue returns a random value of t, the nested values stop at maxGenerateDepth
+func randomValue(t reflect.Type, r *rand.Rand, depth int) reflect.Value {
+	v := reflect.New(t).Elem()
+	// the integers and floats are truncated to their types
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.Intn(2) == 0 {
+			v.SetInt(edgeInts[r.Intn(len(edgeInts))])
+		} else {
+			v.SetInt(r.Int63() - r.Int63())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if r.Intn(2) == 0 {
+			v.SetUint(edgeUints[r.Intn(len(edgeUints))])
+		} else {
+			v.SetUint(r.Uint64())
+		}
+	case reflect.Float32, reflect.Float64:
+		if r.Intn(2) == 0 {
+			v.SetFloat(edgeFloats[r.Intn(len(edgeFloats))])
+		} else {
+			v.SetFloat(r.NormFloat64() * 1e6)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(r.NormFloat64(), r.NormFloat64()))
+	case reflect.String:
+		s := make([]rune, r.Intn(16))
+		for i := range s {
+			s[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		v.SetString(string(s))
+	case reflect.Slice:
+		if depth >= maxGenerateDepth {
+			break
+		}
+		n := r.Intn(5)
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := 0; i < n; i++ {
+			v.Index(i).Set(randomValue(t.Elem(), r, depth+1))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(randomValue(t.Elem(), r, depth+1))
+		}
+	case reflect.Map:
+		if depth >= maxGenerateDepth {
+			break
+		}
+		v.Set(reflect.MakeMap(t))
+		for i := r.Intn(4); i > 0; i-- {
+			v.SetMapIndex(randomValue(t.Key(), r, depth+1), randomValue(t.Elem(), r, depth+1))
+		}
+	case reflect.Ptr:
+		if depth >= maxGenerateDepth || r.Intn(8) == 0 {
+			break
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(randomValue(t.Elem(), r, depth+1))
+		v.Set(p)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				field.Set(randomValue(field.Type(), r, depth+1))
+			}
+		}
+	case reflect.Interface:
+		switch {
+		case t == contextType:
+			v.Set(reflect.ValueOf(context.Background()))
+		case t == errorType:
+			v.Set(reflect.ValueOf(errors.New("generated")))
+		case t.NumMethod() == 0:
+			// any of the basic types
+			basic := []reflect.Type{reflect.TypeOf(0), reflect.TypeOf(0.0), reflect.TypeOf(""), reflect.TypeOf(false)}
+			v.Set(randomValue(basic[r.Intn(len(basic))], r, depth+1))
+		}
+	case reflect.Func:
+		v.Set(reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+			results := make([]reflect.Value, t.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(t.Out(i))
+			}
+			return results
+		}))
+	case reflect.Chan:
+		if t.ChanDir() == reflect.BothDir {
+			v.Set(reflect.MakeChan(t, 1))
+		}
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package funcutil
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fragile struct{}
+
+func (s *fragile) Index(values []int, i int) int {
+	return values[i]
+}
+
+func (s *fragile) Greet(name string, opts *struct{ Loud bool }) string {
+	return "hello " + name
+}
+
+func TestGenerateArgs(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &fragile{})
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		params := f.GenerateArgs("calculator.Add", r)
+		if len(params) != 2 || reflect.TypeOf(params[0]).Kind() != reflect.Int64 {
+			t.Fatalf("Should be 2 int64 got %v", params)
+		}
+		if _, err := f.Call("calculator.Add", params...); err != nil {
+			t.Error(err)
+		}
+		params = f.GenerateArgs("fragile.Greet", r)
+		if _, err := f.Call("fragile.Greet", params...); err != nil {
+			t.Error(err)
+		}
+	}
+	if f.GenerateArgs("calculator.NotExists", r) != nil {
+		t.Error("method should not exists")
+	}
+}
+
+func TestFuzz(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &fragile{})
+	failures := f.Fuzz(rand.New(rand.NewSource(1)), 20)
+	// calculator.Sum dereferences the nil *float64
+	if len(failures) != 2 || failures[0].Method != "calculator.Sum" || failures[1].Method != "fragile.Index" {
+		t.Fatalf("Should be calculator.Sum and fragile.Index got %v", failures)
+	}
+	if !strings.Contains(failures[1].Error(), "index out of range") || failures[1].Stack == "" {
+		t.Errorf("Should be index out of range got %v", failures[1])
+	}
+}
@@ -0,0 +1,78 @@
+package funcutil
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// TypeNameFormat formats the name of an instantiated generic struct registered by
+// its methods, from its base name and its type arguments as printed by reflect,
+// e.g. "Cache" and ["github.com/acme/store.Item"]
+type TypeNameFormat func(base string, args []string) string
+
+// qualifier matches the package path and name qualifying a type
+var qualifier = regexp.MustCompile(`(?:[\w.\-]*/)*[\w\-]+\.`)
+
+// ShortTypeArgs keeps the type arguments without their packages, e.g. Cache[string,Item],
+// the default format
+func ShortTypeArgs(base string, args []string) string {
+	short := make([]string, len(args))
+	for i, arg := range args {
+		short[i] = qualifier.ReplaceAllString(arg, "")
+	}
+	return base + "[" + strings.Join(short, ",") + "]"
+}
+
+// UnderscoreTypeArgs joins the short type arguments with underscores, e.g.
+// Cache_string_Item, for the transports not allowing brackets in the names
+func UnderscoreTypeArgs(base string, args []string) string {
+	name := ShortTypeArgs(base, args)
+	return strings.Trim(strings.NewReplacer("[", "_", "]", "_", ",", "_", "*", "", " ", "").Replace(name), "_")
+}
+
+// SetTypeNameFormat sets how the structs registered later name the instantiated
+// generic structs, see ShortTypeArgs
+func (f *FuncUtil) SetTypeNameFormat(format TypeNameFormat) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.typeNameFormat = format
+	return nil
+}
+
+// typeName returns the name of the struct type t, formatting the generic ones
+func (f *FuncUtil) typeName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.IndexByte(name, '[')
+	if i < 0 || !strings.HasSuffix(name, "]") {
+		return name
+	}
+	format := f.typeNameFormat
+	if format == nil {
+		format = ShortTypeArgs
+	}
+	return format(name[:i], splitTypeArgs(name[i+1:len(name)-1]))
+}
+
+// splitTypeArgs splits the comma separated type arguments outside the brackets
+func splitTypeArgs(s string) []string {
+	args := []string{}
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
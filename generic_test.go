@@ -0,0 +1,66 @@
+package funcutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Cache[T any] struct {
+	items map[string]T
+}
+
+func (c *Cache[T]) Set(key string, v T) {
+	c.items[key] = v
+}
+
+func (c *Cache[T]) Get(key string) T {
+	return c.items[key]
+}
+
+type Pair[K comparable, V any] struct{}
+
+func (p *Pair[K, V]) Swap(k K, v V) (V, K) {
+	return v, k
+}
+
+func TestGenericReceivers(t *testing.T) {
+	f := New()
+	f.Register(&Cache[string]{items: map[string]string{}}, &Cache[*Profile]{items: map[string]*Profile{}})
+	f.Register(&Pair[int, map[string][]Profile]{})
+	if _, err := f.Call("Cache[string].Set", "a", "b"); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Call("Cache[string].Get", "a"); err != nil || rets[0] != "b" {
+		t.Errorf("Should be b got %v %v", rets, err)
+	}
+	if _, err := f.Call("Cache[*Profile].Set", "bob", map[string]interface{}{"nick": "bob"}); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Call("Cache[*Profile].Get", "bob"); err != nil || rets[0].(*Profile).Nick != "bob" {
+		t.Errorf("Should be bob got %v %v", rets, err)
+	}
+	if !f.Has("Pair[int,map[string][]Profile].Swap") {
+		t.Errorf("Unexpected names %v", f.Dump())
+	}
+	if mi, _ := f.Info("Cache[string].Get"); mi.Receiver != "Cache[string]" {
+		t.Errorf("Should be Cache[string] got %s", mi.Receiver)
+	}
+
+	f = New()
+	f.SetTypeNameFormat(UnderscoreTypeArgs)
+	f.Register(&Cache[*Profile]{}, &Pair[int, string]{})
+	if !f.Has("Cache_Profile.Get") || !f.Has("Pair_int_string.Swap") {
+		t.Errorf("Unexpected names %v", f.Dump())
+	}
+}
+
+func TestSplitTypeArgs(t *testing.T) {
+	args := splitTypeArgs("int, map[string]func(int, bool), github.com/acme/store.Item")
+	expected := []string{"int", "map[string]func(int, bool)", "github.com/acme/store.Item"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Unexpected %q", args)
+	}
+	if name := ShortTypeArgs("Cache", []string{"gopkg.in/yaml.v3.Node", "[]*github.com/acme/store.Item"}); name != "Cache[Node,[]*Item]" {
+		t.Errorf("Should be Cache[Node,[]*Item] got %s", name)
+	}
+}
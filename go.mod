@@ -0,0 +1,3 @@
+module github.com/kadekcipta/funcutil
+
+go 1.21
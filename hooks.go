@@ -0,0 +1,56 @@
+package funcutil
+
+// BeforeCallFunc is called before a method is invoked, returning an error aborts the call
+type BeforeCallFunc func(name string, params []interface{}) error
+
+// AfterCallFunc is called after a method is invoked or failed to be invoked
+type AfterCallFunc func(name string, results []interface{}, err error)
+
+type hooks struct {
+	before []BeforeCallFunc
+	after  []AfterCallFunc
+}
+
+func (h hooks) runBefore(name string, params []interface{}) error {
+	for _, fn := range h.before {
+		if err := fn(name, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h hooks) runAfter(name string, results []interface{}, err error) {
+	if err != nil {
+		results = nil
+	}
+	for _, fn := range h.after {
+		fn(name, results, err)
+	}
+}
+
+// OnBeforeCall adds a hook called before every method invocation in the order they are added.
+// The first hook returning an error aborts the call and the error is returned to the caller.
+func (f *FuncUtil) OnBeforeCall(fn BeforeCallFunc) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts = f.opts.clone()
+	f.opts.hooks.before = append(f.opts.hooks.before, fn)
+	return nil
+}
+
+// OnAfterCall adds a hook called after every method invocation with its results,
+// or with the error when the call failed or has been aborted
+func (f *FuncUtil) OnAfterCall(fn AfterCallFunc) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts = f.opts.clone()
+	f.opts.hooks.after = append(f.opts.hooks.after, fn)
+	return nil
+}
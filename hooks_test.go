@@ -0,0 +1,62 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCallHooks(t *testing.T) {
+	f := New()
+	s := &service{}
+	f.Register(s)
+	calls := []string{}
+	f.OnBeforeCall(func(name string, params []interface{}) error {
+		if name == "service.Run" {
+			return errors.New("service.Run is disabled")
+		}
+		calls = append(calls, "before "+name)
+		return nil
+	})
+	f.OnAfterCall(func(name string, results []interface{}, err error) {
+		if err != nil {
+			calls = append(calls, "failed "+name)
+			return
+		}
+		calls = append(calls, "after "+name)
+	})
+	if _, err := f.Call("service.Run"); err == nil {
+		t.Error("should be vetoed by the hook")
+	}
+	if s.running {
+		t.Error("service should not be running")
+	}
+	if _, err := f.Call("service.Info"); err != nil {
+		t.Error(err)
+	}
+	plan, _ := f.Compile("service.Pause")
+	plan.Invoke()
+	expect := []string{"failed service.Run", "before service.Info", "after service.Info", "before service.Pause", "after service.Pause"}
+	if len(calls) != len(expect) {
+		t.Fatalf("Should be %v got %v", expect, calls)
+	}
+	for i := range expect {
+		if calls[i] != expect[i] {
+			t.Errorf("Should be %s got %s", expect[i], calls[i])
+		}
+	}
+}
+
+func TestAfterCallHookFailures(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.Require("service.Run", "admin")
+	var failure error
+	f.OnAfterCall(func(name string, results []interface{}, err error) {
+		failure = err
+	})
+	_, err := f.Call("service.Run")
+	var missing ErrMissingRoles
+	if err == nil || !errors.As(failure, &missing) {
+		t.Errorf("Should be %v got %v", err, failure)
+	}
+}
@@ -0,0 +1,241 @@
+// Package httpapi serves a funcutil registry over HTTP.
+//
+// Each method is served at /<method name>, where slashes may be used instead of the
+// dots of the name. The params are either posted as a JSON array, as described by
+// the OpenAPI document of the registry, or supplied by the query string and the
+// url encoded form, bound to the parameters by name (see funcutil.SetParamNames)
+// or by position:
+//
+//	GET /service.Stop?wait=true
+//	GET /service/Stop?0=true
+//
+// The posted params and the results are encoded by the codecs registered for
+// the Content-Type and Accept headers, e.g. application/msgpack or text/plain, JSON
+// by default. The results are returned as an array, see funcutil.ResultValues.
+//
+// The posted bodies are limited to funcutil.MaxEnvelopeSize. The Authorization header
+// is the credential given to the authenticator of the registry, see
+// funcutil.FuncUtil.Authenticate.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Handler calls the registry methods
+type Handler struct {
+	f *funcutil.FuncUtil
+}
+
+// NewHandler creates the handler of the registry methods
+func NewHandler(f *funcutil.FuncUtil) *Handler {
+	return &Handler{f: f}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, strings.Replace(strings.Trim(r.URL.Path, "/"), "/", ".", -1))
+}
+
+// HandlerFor returns the handler of a single method of the registry, to be mounted
+// at any path of an existing router. The request is handled like by Handler.
+//
+//	mux.Handle("/info", httpapi.HandlerFor(f, "service.Info"))
+func HandlerFor(f *funcutil.FuncUtil, methodName string) http.HandlerFunc {
+	h := NewHandler(f)
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, methodName)
+	}
+}
+
+// serve calls the method with the params of the request
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, name string) {
+	out, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, funcutil.JSONCodec, http.StatusNotAcceptable, errors.New("Not acceptable"))
+		return
+	}
+	ctx, err := h.f.Authenticate(r.Context(), r.Header.Get("Authorization"))
+	if err != nil {
+		writeError(w, out, http.StatusUnauthorized, err)
+		return
+	}
+	mi, ok := h.f.Info(name)
+	if !ok {
+		writeError(w, out, http.StatusNotFound, funcutil.ErrMethodNotFound)
+		return
+	}
+	var params []interface{}
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(funcutil.MaxEnvelopeSize))
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	in, posted := funcutil.LookupCodec(contentType)
+	switch {
+	case r.Method == http.MethodPost && posted:
+		var body []byte
+		if body, err = io.ReadAll(r.Body); err == nil {
+			params, err = in.UnmarshalParams(body)
+		}
+	case r.Method == http.MethodGet || r.Method == http.MethodPost && (contentType == "" || contentType == formType):
+		if err = r.ParseForm(); err == nil {
+			params, err = FormParams(mi, r.Form)
+		}
+	case r.Method == http.MethodPost:
+		writeError(w, out, http.StatusUnsupportedMediaType, errors.New("Unsupported media type"))
+		return
+	default:
+		writeError(w, out, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, out, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if err != nil {
+		writeError(w, out, http.StatusBadRequest, err)
+		return
+	}
+	rets, err := h.f.CallContext(ctx, mi.Name, params...)
+	if err != nil {
+		writeError(w, out, statusOf(err), err)
+		return
+	}
+	write(w, out, http.StatusOK, funcutil.ResultValues(rets))
+}
+
+const formType = "application/x-www-form-urlencoded"
+
+// statusOf returns the status code of the failed call
+func statusOf(err error) int {
+	var validation *funcutil.ValidationError
+	switch {
+	case errors.Is(err, funcutil.ErrParametersMismatch), errors.As(err, &validation):
+		return http.StatusBadRequest
+	case errors.Is(err, funcutil.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, funcutil.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, funcutil.ErrMethodNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, funcutil.ErrCoalesced):
+		return http.StatusAccepted
+	case errors.Is(err, funcutil.ErrThrottled):
+		return http.StatusTooManyRequests
+	case errors.Is(err, funcutil.ErrCircuitOpen), errors.Is(err, funcutil.ErrConcurrencyLimit), errors.Is(err, funcutil.ErrFrozen):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// negotiate returns the codec of the most preferred acceptable media type
+func negotiate(accept string) (funcutil.Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return funcutil.JSONCodec, true
+	}
+	best, bestQ := funcutil.Codec(nil), 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, exists := params["q"]; exists {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		c, exists := funcutil.LookupCodec(mediaType)
+		if !exists && (mediaType == "*/*" || mediaType == "application/*") {
+			c, exists = funcutil.JSONCodec, true
+		}
+		if exists {
+			best, bestQ = c, q
+		}
+	}
+	return best, best != nil
+}
+
+// FormParams binds the form values to the parameters of the method, by name when the
+// parameters are named or by position (0, 1...). The binding stops at the first
+// missing parameter so the trailing ones may be filled by the defaults. The values
+// are decoded as JSON unless the parameter is a string, the repeated values of
+// slice parameters are decoded one by one.
+func FormParams(mi funcutil.MethodInfo, form url.Values) ([]interface{}, error) {
+	params := []interface{}{}
+	for i, t := range mi.Params {
+		values, exists := form[strconv.Itoa(i)]
+		if !exists && i < len(mi.ParamNames) {
+			values, exists = form[mi.ParamNames[i]]
+		}
+		if !exists {
+			break
+		}
+		if len(values) > 1 && t.Kind() == reflect.Slice {
+			elems := make([]interface{}, len(values))
+			for j, v := range values {
+				elems[j] = formValue(v, t.Elem())
+			}
+			params = append(params, elems)
+			continue
+		}
+		params = append(params, formValue(values[0], t))
+	}
+	if len(params) < len(mi.Params) {
+		// omitted params must not be followed by supplied ones
+		for key := range form {
+			if n, err := strconv.Atoi(key); err == nil && n > len(params) {
+				return nil, errors.New("missing parameter " + strconv.Itoa(len(params)))
+			}
+		}
+	}
+	return params, nil
+}
+
+// formValue returns the param of the value, strings are kept for the string types
+func formValue(v string, t reflect.Type) interface{} {
+	if t.Kind() == reflect.String {
+		return v
+	}
+	if json.Valid([]byte(v)) {
+		return json.RawMessage(v)
+	}
+	return v
+}
+
+// errorResponse is the body of the failed requests, the text codec writes the message only
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (e errorResponse) String() string {
+	return e.Error
+}
+
+func write(w http.ResponseWriter, c funcutil.Codec, status int, v interface{}) {
+	b, err := c.Marshal(v)
+	if err != nil {
+		c, status = funcutil.JSONCodec, http.StatusInternalServerError
+		b, _ = c.Marshal(errorResponse{err.Error()})
+	}
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func writeError(w http.ResponseWriter, c funcutil.Codec, status int, err error) {
+	write(w, c, status, errorResponse{err.Error()})
+}
@@ -0,0 +1,225 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type calculator struct {
+}
+
+func (c *calculator) Add(a, b int) int {
+	return a + b
+}
+
+func (c *calculator) Sum(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func (c *calculator) Join(a, b string) string {
+	return a + b
+}
+
+func (c *calculator) Greet(name string, times int) string {
+	return strings.Repeat(name, times)
+}
+
+func TestHandler(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	f.SetParamNames("calculator.Greet", "name", "times")
+	f.SetDefaults("calculator.Greet", 1)
+	srv := httptest.NewServer(NewHandler(f))
+	defer srv.Close()
+
+	tests := []struct {
+		method, path, contentType, body string
+		status                          int
+		expect                          string
+	}{
+		{"POST", "/calculator.Add", "application/json", `[1, 2]`, 200, `[3]`},
+		{"GET", "/calculator/Add?0=40&1=2", "", "", 200, `[42]`},
+		{"GET", "/calculator.Sum?0=1.5&0=2", "", "", 200, `[3.5]`},
+		{"GET", "/calculator.Sum?0=[1,2]", "", "", 200, `[3]`},
+		{"GET", "/calculator.Greet?name=42&times=2", "", "", 200, `["4242"]`},
+		{"GET", "/calculator.Greet?name=go", "", "", 200, `["go"]`},
+		{"POST", "/calculator.Greet", "application/x-www-form-urlencoded", url.Values{"name": {"go"}, "1": {"3"}}.Encode(), 200, `["gogogo"]`},
+		{"GET", "/calculator.Add?1=2", "", "", 400, `{"error":"missing parameter 0"}`},
+		{"GET", "/calculator.Add?0=a&1=2", "", "", 400, ""},
+		{"GET", "/calculator.NotExists", "", "", 404, `{"error":"Method not found"}`},
+		{"DELETE", "/calculator.Add", "", "", 405, ""},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, srv.URL+test.path, strings.NewReader(test.body))
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("%s %s: Should be %d got %d %s", test.method, test.path, test.status, resp.StatusCode, b)
+			continue
+		}
+		if test.expect != "" && strings.TrimSpace(string(b)) != test.expect {
+			t.Errorf("%s %s: Should be %s got %s", test.method, test.path, test.expect, b)
+		}
+	}
+}
+
+func TestNegotiation(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	h := NewHandler(f)
+
+	tests := []struct {
+		contentType, accept, body string
+		status                    int
+		expectType, expect        string
+	}{
+		{"application/json", "text/plain", `["go", 2]`, 200, "text/plain", "gogo\n"},
+		{"application/json", "text/html;q=1, text/plain;q=0.5", `["go", 1]`, 200, "text/plain", "go\n"},
+		{"application/json", "application/*", `["go", 1]`, 200, "application/json", `["go"]`},
+		{"application/msgpack", "application/msgpack", "\x92\xa2go\x01", 200, "application/msgpack", "\x91\xa2go"},
+		{"application/json", "text/html", `["go", 1]`, 406, "application/json", `{"error":"Not acceptable"}`},
+		{"text/plain", "text/plain", "go\nlang", 200, "text/plain", "golang\n"},
+		{"text/plain", "text/plain", "go", 400, "text/plain", "Parameters mismatches: expected 2 got 1"},
+		{"application/xml", "", "<go/>", 415, "application/json", `{"error":"Unsupported media type"}`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/calculator.Greet", strings.NewReader(test.body))
+		if test.contentType == "text/plain" {
+			req.URL.Path = "/calculator.Join"
+		}
+		req.Header.Set("Content-Type", test.contentType)
+		req.Header.Set("Accept", test.accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("%s %s: Should be %d got %d %s", test.contentType, test.accept, test.status, rec.Code, rec.Body)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != test.expectType {
+			t.Errorf("%s %s: Should be %s got %s", test.contentType, test.accept, test.expectType, ct)
+		}
+		if rec.Body.String() != test.expect {
+			t.Errorf("%s %s: Should be %q got %q", test.contentType, test.accept, test.expect, rec.Body)
+		}
+	}
+}
+
+func TestHandlerFor(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	mux := http.NewServeMux()
+	mux.Handle("/api/add", HandlerFor(f, "calculator.Add"))
+	mux.Handle("/api/missing", HandlerFor(f, "calculator.NotExists"))
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		expect             string
+	}{
+		{"POST", "/api/add", `[1, 2]`, 200, `[3]`},
+		{"GET", "/api/add?0=40&1=2", "", 200, `[42]`},
+		{"GET", "/api/missing", "", 404, `{"error":"Method not found"}`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		if test.method == "POST" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != test.status || strings.TrimSpace(rec.Body.String()) != test.expect {
+			t.Errorf("%s %s: Should be %d %s got %d %s", test.method, test.path, test.status, test.expect, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, credential string) (funcutil.Identity, error) {
+		if credential != "Bearer secret" {
+			return funcutil.Identity{}, errors.New("invalid token")
+		}
+		return funcutil.Identity{Name: "alice"}, nil
+	}))
+	f.SetAuthorizer(func(ctx context.Context, id funcutil.Identity, methodName string) error {
+		if methodName == "calculator.Join" {
+			return funcutil.ErrUnauthenticated
+		}
+		return nil
+	})
+	h := NewHandler(f)
+	for _, test := range []struct {
+		path, credential string
+		status           int
+	}{
+		{"/calculator.Add?0=1&1=2", "Bearer secret", 200},
+		{"/calculator.Add?0=1&1=2", "Bearer guess", 401},
+		{"/calculator.Add?0=1&1=2", "", 401},
+		{"/calculator.Join?0=a&1=b", "Bearer secret", 401},
+	} {
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("Authorization", test.credential)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("%s %s: Should be %d got %d %s", test.path, test.credential, test.status, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	defer func(size int) {
+		funcutil.MaxEnvelopeSize = size
+	}(funcutil.MaxEnvelopeSize)
+	funcutil.MaxEnvelopeSize = 16
+	h := NewHandler(f)
+	for body, status := range map[string]int{
+		`[[1, 2]]`:                      200,
+		`[[1, 2, 3, 4, 5, 6, 7, 8, 9]]`: 413,
+	} {
+		req := httptest.NewRequest("POST", "/calculator.Sum", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("%s: Should be %d got %d %s", body, status, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	for err, status := range map[error]int{
+		funcutil.ErrCoalesced:        202,
+		funcutil.ErrThrottled:        429,
+		funcutil.ErrConcurrencyLimit: 503,
+		funcutil.ErrFrozen:           503,
+		funcutil.ErrCircuitOpen:      503,
+		errors.New("failed"):         500,
+	} {
+		if s := statusOf(err); s != status {
+			t.Errorf("%v: Should be %d got %d", err, status, s)
+		}
+	}
+}
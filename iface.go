@@ -0,0 +1,54 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrNotInterface = errors.New("Type must be kind of interface")
+)
+
+// RegisterInterface registers only the methods of the interface T implemented by impl,
+// so the other exported methods of the struct are not callable.
+// The dynamic type of impl must be a pointer to struct. Go can't constrain T to the
+// interface types, a non interface T compiles and fails with ErrNotInterface.
+//
+//	type Runner interface {
+//		Run()
+//	}
+//
+//	funcutil.RegisterInterface[Runner](f, &service{})
+func RegisterInterface[T any](f *FuncUtil, impl T) error {
+	return f.RegisterInterfaceType(reflect.TypeOf((*T)(nil)).Elem(), impl)
+}
+
+// RegisterAsInterface is RegisterInterface, impl is checked against I by the compiler.
+// Like T of RegisterInterface, a non interface I fails with ErrNotInterface.
+//
+//	funcutil.RegisterAsInterface[Runner](f, &service{})
+//	funcutil.RegisterAsInterface[Runner](f, &Monitor{}) // doesn't compile
+func RegisterAsInterface[I any](f *FuncUtil, impl I) error {
+	return RegisterInterface[I](f, impl)
+}
+
+// RegisterInterfaceType is the reflect based equivalent of RegisterInterface,
+// it registers only the methods of the interface type it implemented by impl
+func (f *FuncUtil) RegisterInterfaceType(it reflect.Type, impl interface{}) error {
+	if it.Kind() != reflect.Interface {
+		return ErrNotInterface
+	}
+	if impl == nil || !reflect.TypeOf(impl).Implements(it) {
+		return fmt.Errorf("%v does not implement %v", reflect.TypeOf(impl), it)
+	}
+	f.Lock()
+	defer f.Unlock()
+	return f.register(impl, registration{
+		ns: f.ns,
+		allow: func(m reflect.Method) bool {
+			_, exists := it.MethodByName(m.Name)
+			return exists
+		},
+	})
+}
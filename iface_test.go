@@ -0,0 +1,53 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type Runner interface {
+	Run()
+	Running() bool
+}
+
+func TestRegisterInterface(t *testing.T) {
+	f := New()
+	if err := RegisterInterface[Runner](f, &service{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Dump()) != 2 {
+		t.Errorf("Registered methods should be 2 got %d", len(f.Dump()))
+	}
+	if _, err := f.Call("service.Run"); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("service.Stop", true); err != ErrMethodNotFound {
+		t.Error("service.Stop should not be registered")
+	}
+	if err := RegisterInterface[int](f, 1); err != ErrNotInterface {
+		t.Error("should failed due to non interface type")
+	}
+	if err := RegisterInterface[Runner](f, nil); err == nil {
+		t.Error("should failed due to nil implementation")
+	}
+	if err := RegisterInterface[fmt.Stringer](f, ID(1)); err != ErrNotStructPointer {
+		t.Error("should failed due to non pointer implementation")
+	}
+	if err := f.RegisterInterfaceType(reflect.TypeOf((*Runner)(nil)).Elem(), &Monitor{}); err == nil {
+		t.Error("should failed due to missing methods")
+	}
+}
+
+func TestRegisterAsInterface(t *testing.T) {
+	f := New()
+	if err := RegisterAsInterface[Runner](f, &service{}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Has("service.Running") || f.Has("service.Pause") {
+		t.Error("Should register the methods of Runner only")
+	}
+	if err := RegisterAsInterface[*service](New(), &service{}); err != ErrNotInterface {
+		t.Error("should failed due to non interface type")
+	}
+}
@@ -0,0 +1,164 @@
+package funcutil
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MethodInfo describes a registered method
+type MethodInfo struct {
+	// Name is the registered name used by Call
+	Name      string
+	Namespace string
+	// Version is the version the method is registered as, see RegisterVersion
+	Version string
+	// Receiver and Method are the struct type and method names, both are empty for functions
+	Receiver  string
+	Method    string
+	Signature string
+	Params    []reflect.Type
+	// ParamNames are the names of the parameters, see SetParamNames
+	ParamNames []string
+	Results    []reflect.Type
+	// ResultNames are the names of the results, see SetResultNames
+	ResultNames []string
+	// Doc is the method documentation, see SetDoc
+	Doc string
+	// Deprecated is set by Deprecate along with its note
+	Deprecated  bool
+	Deprecation string
+	// PromotedFrom is the embedded type declaring the method promoted to the struct
+	PromotedFrom string
+	// Roles are required from the callers, see Require
+	Roles []string
+	// Tags are attached by Tag
+	Tags []string
+}
+
+func (f *FuncUtil) methodInfo(name string) MethodInfo {
+	sig := f.signatureOf(name)
+	ci := f.calls[name]
+	_, version := splitVersion(name)
+	mi := MethodInfo{
+		Name:         name,
+		Namespace:    ci.ns,
+		Version:      version,
+		Signature:    sig,
+		Params:       ci.argTypes,
+		ParamNames:   ci.argNames,
+		Results:      ci.retTypes,
+		ResultNames:  ci.retNames,
+		Doc:          ci.doc,
+		Deprecated:   ci.deprecated,
+		Deprecation:  ci.deprecation,
+		PromotedFrom: ci.promotedFrom,
+		Roles:        ci.roles,
+		Tags:         ci.tags,
+	}
+	if ci.m != nil {
+		mi.Receiver = f.typeName(ci.v.Type().Elem())
+		mi.Method = ci.m.Name
+	}
+	return mi
+}
+
+// Info returns the description of the registered method
+func (f *FuncUtil) Info(methodName string) (MethodInfo, bool) {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return MethodInfo{}, false
+	}
+	return f.methodInfo(ci.name), true
+}
+
+// Methods returns the descriptions of all the registered methods sorted by name
+func (f *FuncUtil) Methods() []MethodInfo {
+	f.Lock()
+	defer f.Unlock()
+	names := []string{}
+	for name := range f.calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	methods := []MethodInfo{}
+	for _, name := range names {
+		methods = append(methods, f.methodInfo(name))
+	}
+	return methods
+}
+
+// SetDoc attaches the documentation to the registered method
+func (f *FuncUtil) SetDoc(methodName, doc string) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.doc = doc
+	return f.store(ci)
+}
+
+// SetParamNames names the parameters of the method, e.g. after its declaration,
+// so the transports can bind the params by name
+func (f *FuncUtil) SetParamNames(methodName string, names ...string) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	if len(names) != len(ci.argTypes) {
+		return ErrParamNamesMismatch
+	}
+	ci.argNames = names
+	return f.store(ci)
+}
+
+// Has reports whether the method is registered
+func (f *FuncUtil) Has(methodName string) bool {
+	f.Lock()
+	defer f.Unlock()
+	_, err := f.lookup(methodName)
+	return err == nil
+}
+
+// Len returns the number of registered methods
+func (f *FuncUtil) Len() int {
+	f.Lock()
+	defer f.Unlock()
+	return len(f.calls)
+}
+
+// Range calls fn for the registered methods sorted by name until it returns false.
+// It iterates a snapshot, so fn may use the registry.
+func (f *FuncUtil) Range(fn func(name string, info MethodInfo) bool) {
+	for _, mi := range f.Methods() {
+		if !fn(mi.Name, mi) {
+			return
+		}
+	}
+}
+
+// MethodsByPrefix returns the descriptions of the methods whose names start with
+// prefix, grouped by the name of their struct or namespace (the name up to the
+// method) and sorted by name
+//
+//	f.MethodsByPrefix("com.example.device.") // {"com.example.device.Light": [...], ...}
+func (f *FuncUtil) MethodsByPrefix(prefix string) map[string][]MethodInfo {
+	groups := map[string][]MethodInfo{}
+	f.Range(func(name string, mi MethodInfo) bool {
+		if strings.HasPrefix(name, prefix) {
+			group := ""
+			if i := strings.LastIndex(name, "."); i >= 0 {
+				group = name[:i]
+			}
+			groups[group] = append(groups[group], mi)
+		}
+		return true
+	})
+	return groups
+}
@@ -0,0 +1,86 @@
+package funcutil
+
+import "testing"
+
+func TestInfo(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &Monitor{})
+	mi, exists := f.Info("service.Stop")
+	if !exists {
+		t.Fatal("service.Stop should exists")
+	}
+	if mi.Receiver != "service" || mi.Method != "Stop" || mi.Signature != "service.Stop(bool) " || len(mi.Params) != 1 {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+	if _, exists := f.Info("service.NotExists"); exists {
+		t.Error("method should not exists")
+	}
+	methods := f.Methods()
+	if len(methods) != 6 || methods[0].Name != "Monitor.Display" {
+		t.Errorf("Unexpected methods %v", methods)
+	}
+	if err := f.SetDoc("service.Stop", "Stop stops the service"); err != nil {
+		t.Error(err)
+	}
+	if mi, _ := f.Info("service.Stop"); mi.Doc != "Stop stops the service" {
+		t.Errorf("Unexpected doc %q", mi.Doc)
+	}
+	if err := f.SetDoc("service.NotExists", ""); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+func TestSetParamNames(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	if err := f.SetParamNames("service.Stop", "wait"); err != nil {
+		t.Fatal(err)
+	}
+	if mi, _ := f.Info("service.Stop"); len(mi.ParamNames) != 1 || mi.ParamNames[0] != "wait" {
+		t.Errorf("Unexpected names %v", mi.ParamNames)
+	}
+	if err := f.SetParamNames("service.Stop"); err != ErrParamNamesMismatch {
+		t.Error("should failed due to missing names")
+	}
+}
+
+func TestHasLenRange(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &Monitor{})
+	if !f.Has("service.Stop") || f.Has("service.NotExists") {
+		t.Error("only service.Stop should exists")
+	}
+	if f.Len() != 6 {
+		t.Errorf("Should be 6 got %d", f.Len())
+	}
+	names := []string{}
+	f.Range(func(name string, mi MethodInfo) bool {
+		if name != mi.Name || !f.Has(name) {
+			t.Errorf("Unexpected info %+v", mi)
+		}
+		names = append(names, name)
+		return len(names) < 2
+	})
+	if len(names) != 2 || names[0] != "Monitor.Display" {
+		t.Errorf("Range should stop after 2 methods got %v", names)
+	}
+}
+
+func TestMethodsByPrefix(t *testing.T) {
+	f := New("com.example")
+	f.Register(&service{}, &Monitor{})
+	groups := f.MethodsByPrefix("com.example.")
+	if len(groups) != 2 || len(groups["com.example.service"]) != 5 || len(groups["com.example.Monitor"]) != 1 {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+	if mi := groups["com.example.service"][0]; mi.Name != "com.example.service.Info" {
+		t.Errorf("Should be com.example.service.Info got %v", mi.Name)
+	}
+	groups = f.MethodsByPrefix("com.example.Monitor.")
+	if len(groups) != 1 || len(groups["com.example.Monitor"]) != 1 {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+	if groups := f.MethodsByPrefix("org."); len(groups) != 0 {
+		t.Errorf("Should be empty got %v", groups)
+	}
+}
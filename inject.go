@@ -0,0 +1,79 @@
+package funcutil
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// Provider returns the value injected into the parameters of the type it is provided for
+type Provider func() (interface{}, error)
+
+// Provide makes the parameters of type t injected by calling the provider on every call,
+// callers omit them from their params. Defaults apply to the trailing params the callers
+// supply.
+//
+//	f.Provide(reflect.TypeOf(&DB{}), func() (interface{}, error) {
+//		return pool.Get()
+//	})
+//	// func (s *users) Find(db *DB, id int) *User
+//	f.Call("users.Find", 42)
+func (f *FuncUtil) Provide(t reflect.Type, provider Provider) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts = f.opts.clone()
+	f.opts.providers[t] = provider
+	return nil
+}
+
+// inject inserts the provided values into the params
+func (o options) inject(ci callInfo, params []interface{}) ([]interface{}, error) {
+	if len(o.providers) == 0 && !o.captureWriters {
+		return params, nil
+	}
+	injected := false
+	for _, t := range ci.argTypes {
+		if _, exists := o.provider(t); exists {
+			injected = true
+			break
+		}
+	}
+	if !injected {
+		return params, nil
+	}
+	full := make([]interface{}, 0, len(ci.argTypes))
+	next := 0
+	for _, t := range ci.argTypes {
+		if provider, exists := o.provider(t); exists {
+			v, err := provider()
+			if err != nil {
+				return nil, fmt.Errorf("provider of %v: %w", t, err)
+			}
+			full = append(full, v)
+			continue
+		}
+		// the rest may be filled by the defaults
+		if next == len(params) {
+			break
+		}
+		full = append(full, params[next])
+		next++
+	}
+	return append(full, params[next:]...), nil
+}
+
+// provider returns the provider of the parameters of type t
+func (o options) provider(t reflect.Type) (Provider, bool) {
+	if t == writerType && o.captureWriters {
+		return captureWriter, true
+	}
+	provider, exists := o.providers[t]
+	return provider, exists
+}
+
+func captureWriter() (interface{}, error) {
+	return &bytes.Buffer{}, nil
+}
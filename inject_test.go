@@ -0,0 +1,56 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type DB struct {
+	name string
+}
+
+type users struct {
+}
+
+func (u *users) Find(db *DB, id int) string {
+	return fmt.Sprintf("%s:%d", db.name, id)
+}
+
+func (u *users) Count(db *DB) int {
+	return 1
+}
+
+func TestProvide(t *testing.T) {
+	f := New()
+	f.Register(&users{})
+	provided := 0
+	f.Provide(reflect.TypeOf(&DB{}), func() (interface{}, error) {
+		provided++
+		return &DB{name: "main"}, nil
+	})
+	if rets, err := f.Call("users.Find", 42); err != nil || rets[0] != "main:42" {
+		t.Errorf("Should be main:42 got %v %v", rets, err)
+	}
+	if rets, err := f.Call("users.Count"); err != nil || rets[0] != 1 {
+		t.Errorf("Should be 1 got %v %v", rets, err)
+	}
+	if _, err := f.Call("users.Find", 42, 43); !errors.Is(err, ErrParametersMismatch) {
+		t.Error("should failed due to too many arguments")
+	}
+	f.SetDefaults("users.Find", 7)
+	if rets, err := f.Call("users.Find"); err != nil || rets[0] != "main:7" {
+		t.Errorf("Should be main:7 got %v %v", rets, err)
+	}
+	if provided != 4 {
+		t.Errorf("Should provide 4 times got %d", provided)
+	}
+	errNoConnection := errors.New("no connection")
+	f.Provide(reflect.TypeOf(&DB{}), func() (interface{}, error) {
+		return nil, errNoConnection
+	})
+	if _, err := f.Call("users.Find", 42); !errors.Is(err, errNoConnection) {
+		t.Errorf("should failed due to provider error got %v", err)
+	}
+}
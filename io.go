@@ -0,0 +1,79 @@
+package funcutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	writerType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+)
+
+// readerConverter reads the io.Reader parameters from strings and []byte,
+// including the JSON strings of the JSON transports
+func readerConverter(v interface{}) (interface{}, error) {
+	switch r := v.(type) {
+	case string:
+		return strings.NewReader(r), nil
+	case []byte:
+		return bytes.NewReader(r), nil
+	case json.RawMessage:
+		var s string
+		if err := json.Unmarshal(r, &s); err != nil {
+			return nil, err
+		}
+		return strings.NewReader(s), nil
+	}
+	return nil, SkipConversion
+}
+
+// CaptureWriters makes the io.Writer parameters injected with a buffer, callers
+// omit them from their params, and the written output is appended to the results
+// as string in the parameter order
+//
+//	// func (r *reports) Render(w io.Writer, month int) error
+//	f.CaptureWriters(true)
+//	rets, _ := f.Call("reports.Render", 5) // [<nil> "report of May"]
+func (f *FuncUtil) CaptureWriters(enabled bool) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.captureWriters = enabled
+	return nil
+}
+
+// resultCount returns the number of results including the captured output
+func (o options) resultCount(ci callInfo) int {
+	n := len(ci.retTypes)
+	if o.captureWriters {
+		for _, t := range ci.argTypes {
+			if t == writerType {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// storeCaptured stores the output of the injected writers after the method results
+func (o options) storeCaptured(ci callInfo, params []interface{}, results []interface{}) {
+	if !o.captureWriters {
+		return
+	}
+	next := len(ci.retTypes)
+	for i, t := range ci.argTypes {
+		if t != writerType || i >= len(params) {
+			continue
+		}
+		if buf, ok := params[i].(*bytes.Buffer); ok && next < len(results) {
+			results[next] = buf.String()
+			next++
+		}
+	}
+}
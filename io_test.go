@@ -0,0 +1,68 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type textTool struct {
+}
+
+func (t *textTool) Upper(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	return strings.ToUpper(string(b)), err
+}
+
+func (t *textTool) Render(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "Hello %s", name)
+	return err
+}
+
+func TestReaderParams(t *testing.T) {
+	f := New()
+	f.Register(&textTool{})
+	for _, p := range []interface{}{"gopher", []byte("gopher"), json.RawMessage(`"gopher"`), strings.NewReader("gopher")} {
+		rets, err := f.Call("textTool.Upper", p)
+		if err != nil {
+			t.Errorf("%T: %v", p, err)
+			continue
+		}
+		if rets[0] != "GOPHER" {
+			t.Errorf("%T: Should be GOPHER got %v", p, rets[0])
+		}
+	}
+	if _, err := f.Call("textTool.Upper", 42); err == nil {
+		t.Error("should failed due to non readable param")
+	}
+}
+
+func TestCaptureWriters(t *testing.T) {
+	f := New()
+	f.Register(&textTool{})
+	if _, err := f.Call("textTool.Render", "gopher"); !errors.Is(err, ErrParametersMismatch) {
+		t.Error("should failed due to missing writer")
+	}
+	f.CaptureWriters(true)
+	rets, err := f.Call("textTool.Render", "gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rets) != 2 || rets[0] != nil || rets[1] != "Hello gopher" {
+		t.Errorf("Unexpected results %v", rets)
+	}
+	out, err := f.CallJSON("textTool.Render", []byte(`["gopher"]`))
+	if err != nil || string(out) != `[null,"Hello gopher"]` {
+		t.Errorf("Unexpected results %s %v", out, err)
+	}
+	if err := f.CallInto("textTool.Render", make([]interface{}, 1), "gopher"); err != ErrResultsMismatch {
+		t.Error("should failed due to missing room for the output")
+	}
+	p, _ := f.Compile("textTool.Render")
+	if rets, _ := p.Invoke("plan"); len(rets) != 2 || rets[1] != "Hello plan" {
+		t.Errorf("Unexpected results %v", rets)
+	}
+}
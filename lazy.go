@@ -0,0 +1,114 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// lazyStruct is a pending lazy registration, constructed once by the first call
+type lazyStruct struct {
+	once sync.Once
+	ctor func() interface{}
+	s    interface{}
+}
+
+func (l *lazyStruct) construct() interface{} {
+	l.once.Do(func() {
+		l.s = l.ctor()
+	})
+	return l.s
+}
+
+// RegisterLazy defers the construction and registration of the struct named name
+// until the first call to any of its methods, cutting the startup time of
+// registries with many rarely used services. The constructor must return a
+// *struct of that name, it is called without holding the lock of the registry and
+// again by the next call when the struct can't be registered. The methods are not
+// listed by Dump until constructed.
+//
+//	f.RegisterLazy("reports", func() interface{} {
+//		return newReports(loadTemplates())
+//	})
+//	f.Call("reports.Monthly") // constructs and registers reports
+func (f *FuncUtil) RegisterLazy(name string, ctor func() interface{}) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.lazy[joinNamespace(f.ns, name)] = &lazyStruct{ctor: ctor}
+	return nil
+}
+
+// lookup returns the registered method, constructing its lazy struct when needed.
+// The caller must hold the lock, it is released while the struct is constructed.
+func (f *FuncUtil) lookup(methodName string) (callInfo, error) {
+	ci, err := f.find(methodName)
+	if err == ErrMethodNotFound {
+		if name, aliased := f.unalias(methodName); aliased {
+			return f.find(name)
+		}
+	}
+	return ci, err
+}
+
+// find is lookup without the namespace aliases
+func (f *FuncUtil) find(methodName string) (callInfo, error) {
+	if ci, exists := f.calls[methodName]; exists {
+		return ci, nil
+	}
+	if !strings.Contains(methodName, "@") {
+		if name, exists := f.resolveVersion(methodName); exists {
+			return f.calls[name], nil
+		}
+	}
+	i := strings.LastIndex(methodName, ".")
+	if i < 0 {
+		return callInfo{}, ErrMethodNotFound
+	}
+	prefix := methodName[:i]
+	l, exists := f.lazy[prefix]
+	if !exists {
+		return callInfo{}, ErrMethodNotFound
+	}
+	// the constructor may use the registry, the concurrent calls wait for it
+	f.Unlock()
+	s := l.construct()
+	f.Lock()
+	if f.lazy[prefix] != l {
+		// registered by a concurrent call meanwhile
+		if ci, exists := f.calls[methodName]; exists {
+			return ci, nil
+		}
+		return callInfo{}, ErrMethodNotFound
+	}
+	if err := f.registerLazy(prefix, s); err != nil {
+		// the next call constructs it again
+		f.lazy[prefix] = &lazyStruct{ctor: l.ctor}
+		return callInfo{}, err
+	}
+	delete(f.lazy, prefix)
+	if ci, exists := f.calls[methodName]; exists {
+		return ci, nil
+	}
+	return callInfo{}, ErrMethodNotFound
+}
+
+// registerLazy registers the constructed struct named prefix
+func (f *FuncUtil) registerLazy(prefix string, s interface{}) error {
+	t := reflect.TypeOf(s)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return ErrNotStructPointer
+	}
+	// the struct name is the last part of the prefix
+	ns, name := "", prefix
+	if j := strings.LastIndex(prefix, "."); j >= 0 {
+		ns, name = prefix[:j], prefix[j+1:]
+	}
+	if f.typeName(t.Elem()) != name {
+		return fmt.Errorf("lazy %s constructed %v", prefix, t)
+	}
+	return f.register(s, registration{ns: ns})
+}
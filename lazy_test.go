@@ -0,0 +1,89 @@
+package funcutil
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRegisterLazy(t *testing.T) {
+	f := New("app")
+	constructed := 0
+	f.RegisterLazy("service", func() interface{} {
+		constructed++
+		return &service{}
+	})
+	if len(f.Dump()) != 0 || constructed != 0 {
+		t.Error("service should not be constructed yet")
+	}
+	if _, err := f.Call("app.service.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+	if _, err := f.Call("app.service.Run"); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Call("app.service.Running"); err != nil || !rets[0].(bool) {
+		t.Errorf("service should be running %v %v", rets, err)
+	}
+	if constructed != 1 || len(f.Dump()) != 5 {
+		t.Errorf("service should be constructed once got %d", constructed)
+	}
+	f.RegisterLazy("monitor", func() interface{} {
+		return &Monitor{}
+	})
+	if _, err := f.Call("app.monitor.Display"); err == nil {
+		t.Error("should failed due to different struct name")
+	}
+}
+
+func TestRegisterLazyRetry(t *testing.T) {
+	f := New()
+	attempts := 0
+	f.RegisterLazy("service", func() interface{} {
+		attempts++
+		if attempts == 1 {
+			return &Monitor{}
+		}
+		return &service{}
+	})
+	if _, err := f.Call("service.Run"); err == nil {
+		t.Error("should failed due to different struct name")
+	}
+	if _, err := f.Call("service.Run"); err != nil {
+		t.Errorf("Should construct it again got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Should be 2 attempts got %d", attempts)
+	}
+}
+
+func TestRegisterLazyUsingRegistry(t *testing.T) {
+	f := New()
+	f.Register(&Monitor{})
+	f.RegisterLazy("service", func() interface{} {
+		// the constructor may call the registry
+		f.Call("Monitor.Display")
+		return &service{}
+	})
+	constructed := 0
+	f.RegisterLazy("calculator", func() interface{} {
+		constructed++
+		return &calculator{}
+	})
+	if _, err := f.Call("service.Run"); err != nil {
+		t.Error(err)
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rets, err := f.Call("calculator.Add", 1, 2); err != nil || rets[0] != int64(3) {
+				t.Errorf("Should be 3 got %v %v", rets, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if constructed != 1 {
+		t.Errorf("Should be constructed once got %d", constructed)
+	}
+}
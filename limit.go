@@ -0,0 +1,85 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrConcurrencyLimit = errors.New("Concurrency limit reached")
+)
+
+// LimitPolicy tells what happens to the calls exceeding the concurrency limit
+type LimitPolicy int
+
+const (
+	// LimitBlock makes the excess calls wait for a running call to finish or for
+	// their context to be done, the default
+	LimitBlock LimitPolicy = iota
+	// LimitFailFast makes the excess calls fail with ErrConcurrencyLimit
+	LimitFailFast
+)
+
+type limiter struct {
+	slots  chan struct{}
+	policy LimitPolicy
+}
+
+// SetConcurrency limits the number of simultaneous invocations of the method to n,
+// the excess calls block unless the LimitFailFast policy is given. Zero removes the limit.
+//
+//	f.SetConcurrency("service.Heavy", 4)
+//	f.SetConcurrency("service.Export", 1, funcutil.LimitFailFast)
+func (f *FuncUtil) SetConcurrency(methodName string, n int, policy ...LimitPolicy) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.limit = nil
+	if n > 0 {
+		ci.limit = &limiter{slots: make(chan struct{}, n)}
+		if len(policy) > 0 {
+			ci.limit.policy = policy[0]
+		}
+	}
+	return f.store(ci)
+}
+
+// clone returns a limiter with the same limit and no call running
+func (l *limiter) clone() *limiter {
+	if l == nil {
+		return nil
+	}
+	return &limiter{slots: make(chan struct{}, cap(l.slots)), policy: l.policy}
+}
+
+// acquire takes a slot for the call, release must be called once it returns
+func (l *limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if l.policy == LimitFailFast {
+		select {
+		case l.slots <- struct{}{}:
+			return nil
+		default:
+			return ErrConcurrencyLimit
+		}
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of an acquired call
+func (l *limiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
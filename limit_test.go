@@ -0,0 +1,76 @@
+package funcutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type gate struct {
+	entered chan struct{}
+	open    chan struct{}
+}
+
+func (g *gate) Pass() {
+	g.entered <- struct{}{}
+	<-g.open
+}
+
+func TestSetConcurrency(t *testing.T) {
+	f := New()
+	g := &gate{entered: make(chan struct{}, 2), open: make(chan struct{})}
+	f.Register(g)
+	if err := f.SetConcurrency("gate.Pass", 1); err != nil {
+		t.Fatal(err)
+	}
+	// the plans of the method share its limit
+	first, _ := f.Bind("gate.Pass")
+	second, _ := f.Bind("gate.Pass")
+	done := make(chan error)
+	go func() {
+		_, err := first.Invoke()
+		done <- err
+	}()
+	<-g.entered
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := second.InvokeContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Should be deadline exceeded got %v", err)
+	}
+	go func() {
+		_, err := second.Invoke()
+		done <- err
+	}()
+	select {
+	case <-g.entered:
+		t.Error("second call should wait for the first")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(g.open)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}
+
+	f.SetConcurrency("gate.Pass", 1, LimitFailFast)
+	g.open = make(chan struct{})
+	first, _ = f.Bind("gate.Pass")
+	second, _ = f.Bind("gate.Pass")
+	go func() {
+		_, err := first.Invoke()
+		done <- err
+	}()
+	// the waiting call of the blocking policy has entered too
+	<-g.entered
+	<-g.entered
+	if _, err := second.Invoke(); err != ErrConcurrencyLimit {
+		t.Errorf("Should be concurrency limit got %v", err)
+	}
+	close(g.open)
+	<-done
+
+	if err := f.SetConcurrency("gate.NotExists", 1); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,46 @@
+package funcutil
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// WithLogger logs the registrations, the calls, the conversions of the params and the
+// failures to l, nil keeps the registry silent. The registrations and the failed calls
+// are logged at info level, the calls and the conversions at debug level and the
+// calls of the deprecated methods at warn level.
+//
+//	f.WithLogger(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+func (f *FuncUtil) WithLogger(l *slog.Logger) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.logger = l
+	return nil
+}
+
+// logCall logs the start of the call and returns the func logging its end
+func (o options) logCall(methodName string, params []interface{}) func(results []interface{}, err error) {
+	o.logger.Debug("call", "method", methodName, "params", len(params))
+	start := time.Now()
+	return func(results []interface{}, err error) {
+		if err == nil {
+			err = resultError(results)
+		}
+		if err != nil {
+			o.logger.Info("call failed", "method", methodName, "duration", time.Since(start), "error", err)
+			return
+		}
+		o.logger.Debug("call done", "method", methodName, "duration", time.Since(start))
+	}
+}
+
+// logConversion logs the param converted to the type of the argument
+func (o options) logConversion(methodName string, i int, p interface{}, v reflect.Value) {
+	if pt := reflect.TypeOf(p); pt != nil && pt != v.Type() {
+		o.logger.Debug("param converted", "method", methodName, "param", i, "from", pt, "to", v.Type())
+	}
+}
@@ -0,0 +1,45 @@
+package funcutil
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	f := New()
+	buf := &bytes.Buffer{}
+	f.WithLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if err := f.Register(&calculator{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "msg=registered") || !strings.Contains(buf.String(), "methods=3") {
+		t.Errorf("Should log the registration got %s", buf)
+	}
+	buf.Reset()
+	f.Call("calculator.Add", 1, 2)
+	for _, record := range []string{"msg=call ", "msg=\"param converted\" method=calculator.Add param=0 from=int to=int64", "msg=\"call done\""} {
+		if !strings.Contains(buf.String(), record) {
+			t.Errorf("Should log %s got %s", record, buf)
+		}
+	}
+	buf.Reset()
+	f.Call("calculator.Sum", []float64{}, 2.0)
+	if !strings.Contains(buf.String(), "msg=\"call failed\" method=calculator.Sum") {
+		t.Errorf("Should log the failure got %s", buf)
+	}
+	buf.Reset()
+	if err := f.Register(calculator{}); err != ErrNotStructPointer {
+		t.Errorf("should failed due to not a struct pointer got %v", err)
+	}
+	if !strings.Contains(buf.String(), "level=ERROR msg=\"register failed\"") {
+		t.Errorf("Should log the failure got %s", buf)
+	}
+	f.WithLogger(nil)
+	buf.Reset()
+	f.Call("calculator.Add", 1, 2)
+	if buf.Len() != 0 {
+		t.Errorf("Should be silent got %s", buf)
+	}
+}
@@ -0,0 +1,148 @@
+package funcutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoKeyFunc returns the cache key of the params of a memoized method
+type MemoKeyFunc func(params []interface{}) string
+
+type memoEntry struct {
+	results []interface{}
+	expires time.Time
+}
+
+type memoCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	key     MemoKeyFunc
+	max     int
+	entries map[string]memoEntry
+}
+
+// MaxMemoEntries is the number of results cached per memoized method, the expired
+// results are dropped once it is reached then the oldest ones
+var MaxMemoEntries = 1024
+
+// Memoize caches the results of the method for ttl, keyed by the params, e.g. for the
+// expensive read-only methods. The failed calls are not cached, at most MaxMemoEntries
+// results are. The params are keyed by their type and value unless the key function
+// is given, the pointers by the value they point to. The cached calls still run the
+// before hooks and the validation, not the limit and the breaker. Zero removes the cache.
+//
+//	f.Memoize("report.Monthly", time.Hour)
+//	f.Memoize("users.Find", time.Minute, func(params []interface{}) string {
+//		return params[0].(*Query).ID
+//	})
+func (f *FuncUtil) Memoize(methodName string, ttl time.Duration, key ...MemoKeyFunc) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.memo = nil
+	if ttl > 0 {
+		ci.memo = &memoCache{ttl: ttl, key: memoKey, max: MaxMemoEntries, entries: map[string]memoEntry{}}
+		if len(key) > 0 && key[0] != nil {
+			ci.memo.key = key[0]
+		}
+	}
+	return f.store(ci)
+}
+
+// InvalidateCache drops the cached results of the memoized method
+func (f *FuncUtil) InvalidateCache(methodName string) error {
+	ci, _, err := f.resolve(methodName)
+	if err != nil {
+		return err
+	}
+	if m := ci.memo; m != nil {
+		m.Lock()
+		m.entries = map[string]memoEntry{}
+		m.Unlock()
+	}
+	return nil
+}
+
+// memoKey keys the params by their type and value, the context params are left out
+func memoKey(params []interface{}) string {
+	b := &strings.Builder{}
+	for _, p := range params {
+		if _, ok := p.(context.Context); ok {
+			continue
+		}
+		if v := reflect.ValueOf(p); v.Kind() == reflect.Ptr && !v.IsNil() {
+			fmt.Fprintf(b, "%T:&%#v;", p, v.Elem().Interface())
+			continue
+		}
+		fmt.Fprintf(b, "%T:%#v;", p, p)
+	}
+	return b.String()
+}
+
+// clone returns an empty cache with the same settings
+func (m *memoCache) clone() *memoCache {
+	if m == nil {
+		return nil
+	}
+	return &memoCache{ttl: m.ttl, key: m.key, max: m.max, entries: map[string]memoEntry{}}
+}
+
+// load copies the cached results of the key into results
+func (m *memoCache) load(key string, results []interface{}) bool {
+	if m == nil {
+		return false
+	}
+	m.Lock()
+	defer m.Unlock()
+	e, exists := m.entries[key]
+	if !exists {
+		return false
+	}
+	if time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return false
+	}
+	copy(results, e.results)
+	return true
+}
+
+// save caches the results of the successful call
+func (m *memoCache) save(key string, results []interface{}) {
+	if m == nil || resultError(results) != nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, exists := m.entries[key]; !exists && m.max > 0 && len(m.entries) >= m.max {
+		m.evict()
+	}
+	m.entries[key] = memoEntry{
+		results: append([]interface{}{}, results...),
+		expires: time.Now().Add(m.ttl),
+	}
+}
+
+// evict drops the expired entries, or the oldest one when none expired
+func (m *memoCache) evict() {
+	now := time.Now()
+	oldest, found := "", false
+	for key, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, key)
+			continue
+		}
+		if !found || e.expires.Before(m.entries[oldest].expires) {
+			oldest, found = key, true
+		}
+	}
+	if len(m.entries) >= m.max {
+		delete(m.entries, oldest)
+	}
+}
@@ -0,0 +1,149 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type phonebook struct {
+	lookups int
+}
+
+func (d *phonebook) Find(id int) (string, error) {
+	d.lookups++
+	if id < 0 {
+		return "", errors.New("invalid id")
+	}
+	return fmt.Sprintf("user%d", id), nil
+}
+
+func TestMemoize(t *testing.T) {
+	f := New()
+	d := &phonebook{}
+	f.Register(d)
+	if err := f.Memoize("phonebook.Find", 30*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if rets, err := f.Call("phonebook.Find", 1); err != nil || rets[0] != "user1" {
+			t.Errorf("Should be user1 got %v %v", rets, err)
+		}
+	}
+	f.Call("phonebook.Find", 2)
+	if d.lookups != 2 {
+		t.Errorf("Should be 2 lookups got %d", d.lookups)
+	}
+	plan, _ := f.Compile("phonebook.Find")
+	if rets, _ := plan.Invoke(2); rets[0] != "user2" || d.lookups != 2 {
+		t.Errorf("Should be cached user2 got %v %d", rets, d.lookups)
+	}
+	// the failures are not cached
+	f.Call("phonebook.Find", -1)
+	f.Call("phonebook.Find", -1)
+	if d.lookups != 4 {
+		t.Errorf("Should be 4 lookups got %d", d.lookups)
+	}
+	time.Sleep(40 * time.Millisecond)
+	f.Call("phonebook.Find", 1)
+	if d.lookups != 5 {
+		t.Errorf("Should expire the cache got %d lookups", d.lookups)
+	}
+	if err := f.InvalidateCache("phonebook.Find"); err != nil {
+		t.Error(err)
+	}
+	f.Call("phonebook.Find", 1)
+	if d.lookups != 6 {
+		t.Errorf("Should invalidate the cache got %d lookups", d.lookups)
+	}
+
+	// every id below 10 shares the result
+	f.Memoize("phonebook.Find", time.Minute, func(params []interface{}) string {
+		return fmt.Sprint(params[0].(int) < 10)
+	})
+	f.Call("phonebook.Find", 1)
+	if rets, _ := f.Call("phonebook.Find", 2); rets[0] != "user1" || d.lookups != 7 {
+		t.Errorf("Should be cached user1 got %v %d", rets, d.lookups)
+	}
+	f.Memoize("phonebook.Find", 0)
+	f.Call("phonebook.Find", 2)
+	if d.lookups != 8 {
+		t.Errorf("Should not cache got %d lookups", d.lookups)
+	}
+	if err := f.Memoize("phonebook.NotExists", time.Minute); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+func TestMemoizeHooks(t *testing.T) {
+	f := New()
+	d := &phonebook{}
+	f.Register(d)
+	f.Memoize("phonebook.Find", time.Minute)
+	f.Call("phonebook.Find", 1)
+	denied := errors.New("denied")
+	f.OnBeforeCall(func(name string, params []interface{}) error {
+		return denied
+	})
+	if _, err := f.Call("phonebook.Find", 1); err != denied {
+		t.Errorf("Should be denied got %v", err)
+	}
+	if d.lookups != 1 {
+		t.Errorf("Should be 1 lookup got %d", d.lookups)
+	}
+}
+
+func TestMemoizeBounded(t *testing.T) {
+	defer func(max int) {
+		MaxMemoEntries = max
+	}(MaxMemoEntries)
+	MaxMemoEntries = 2
+	f := New()
+	d := &phonebook{}
+	f.Register(d)
+	f.Memoize("phonebook.Find", time.Minute)
+	for id := 1; id <= 3; id++ {
+		f.Call("phonebook.Find", id)
+	}
+	ci, _, _ := f.resolve("phonebook.Find")
+	if len(ci.memo.entries) != 2 {
+		t.Errorf("Should be 2 entries got %d", len(ci.memo.entries))
+	}
+	// the oldest one is dropped
+	f.Call("phonebook.Find", 3)
+	f.Call("phonebook.Find", 1)
+	if d.lookups != 4 {
+		t.Errorf("Should be 4 lookups got %d", d.lookups)
+	}
+}
+
+func TestMemoKeyPointer(t *testing.T) {
+	a, b := 1, 1
+	if memoKey([]interface{}{&a}) != memoKey([]interface{}{&b}) {
+		t.Error("pointers should be keyed by their values")
+	}
+	b = 2
+	if memoKey([]interface{}{&a}) == memoKey([]interface{}{&b}) {
+		t.Error("pointers to different values should not share the key")
+	}
+}
+
+func TestMemoizeClone(t *testing.T) {
+	f := New()
+	d := &phonebook{}
+	f.Register(d)
+	f.Memoize("phonebook.Find", time.Minute)
+	f.Call("phonebook.Find", 1)
+	c := f.Clone()
+	c.InvalidateCache("phonebook.Find")
+	f.Call("phonebook.Find", 1)
+	if d.lookups != 1 {
+		t.Errorf("Should be cached got %d lookups", d.lookups)
+	}
+	c.Call("phonebook.Find", 2)
+	f.Call("phonebook.Find", 2)
+	if d.lookups != 3 {
+		t.Errorf("Should be 3 lookups got %d", d.lookups)
+	}
+}
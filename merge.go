@@ -0,0 +1,165 @@
+package funcutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMethodExists = errors.New("Method already exists")
+)
+
+// MergePolicy returns the name of a merged method colliding with an existing one,
+// or of a lazy struct, a default version or an alias
+type MergePolicy func(name string) (string, error)
+
+var (
+	// MergeError fails the merge at the first collision
+	MergeError MergePolicy = func(name string) (string, error) {
+		return "", fmt.Errorf("%s: %w", name, ErrMethodExists)
+	}
+	// MergeOverwrite replaces the existing method
+	MergeOverwrite MergePolicy = func(name string) (string, error) {
+		return name, nil
+	}
+)
+
+// MergePrefix registers the colliding method under <prefix>.<name>
+func MergePrefix(prefix string) MergePolicy {
+	return func(name string) (string, error) {
+		return prefix + "." + name, nil
+	}
+}
+
+// Merge registers all the methods of other into f sharing their receivers, along with
+// its lazy registrations, default versions and namespace aliases. Collisions are
+// resolved by the policy, the lazy structs, default versions and aliases by their
+// names. Like Clone, the merged methods get their own breakers, limits, paced calls
+// and caches. Nothing is merged when the policy fails.
+func (f *FuncUtil) Merge(other *FuncUtil, policy MergePolicy) error {
+	other.Lock()
+	calls := cloneCalls(other.calls)
+	lazy := map[string]*lazyStruct{}
+	for prefix, l := range other.lazy {
+		lazy[prefix] = &lazyStruct{ctor: l.ctor}
+	}
+	defaultVersions := copySettings(other.defaultVersions)
+	aliases := copySettings(other.aliases)
+	other.Unlock()
+
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	merged := map[string]callInfo{}
+	for name, ci := range calls {
+		newName := name
+		if _, exists := f.calls[name]; exists {
+			var err error
+			if newName, err = policy(name); err != nil {
+				return err
+			}
+			if _, exists := f.calls[newName]; exists && newName != name {
+				return fmt.Errorf("%s: %w", newName, ErrMethodExists)
+			}
+		}
+		if newName != name {
+			ci.name = newName
+			ci.signature = ""
+		}
+		merged[newName] = ci
+	}
+	mergedLazy := map[string]*lazyStruct{}
+	for prefix, l := range lazy {
+		newPrefix := prefix
+		if f.hasStruct(prefix) {
+			var err error
+			if newPrefix, err = policy(prefix); err != nil {
+				return err
+			}
+			if f.hasStruct(newPrefix) && newPrefix != prefix {
+				return fmt.Errorf("%s: %w", newPrefix, ErrMethodExists)
+			}
+		}
+		mergedLazy[newPrefix] = l
+	}
+	mergedVersions, err := mergeSettings(f.defaultVersions, defaultVersions, policy)
+	if err != nil {
+		return err
+	}
+	mergedAliases, err := mergeSettings(f.aliases, aliases, policy)
+	if err != nil {
+		return err
+	}
+	for name, ci := range merged {
+		f.calls[name] = ci
+	}
+	for prefix, l := range mergedLazy {
+		// the overwritten struct is constructed by the merged registration
+		for name := range f.calls {
+			if _, isMerged := merged[name]; !isMerged && structOf(name) == prefix {
+				delete(f.calls, name)
+			}
+		}
+		f.lazy[prefix] = l
+	}
+	for name, version := range mergedVersions {
+		f.defaultVersions[name] = version
+	}
+	for alias, ns := range mergedAliases {
+		f.aliases[alias] = ns
+	}
+	return nil
+}
+
+// hasStruct tells whether the struct named prefix is registered, lazily or not.
+// The caller must hold the lock.
+func (f *FuncUtil) hasStruct(prefix string) bool {
+	if _, exists := f.lazy[prefix]; exists {
+		return true
+	}
+	for name := range f.calls {
+		if structOf(name) == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// structOf returns the name of the struct of the method
+func structOf(methodName string) string {
+	if i := strings.LastIndex(methodName, "."); i >= 0 {
+		return methodName[:i]
+	}
+	return ""
+}
+
+func copySettings(settings map[string]string) map[string]string {
+	c := make(map[string]string, len(settings))
+	for name, v := range settings {
+		c[name] = v
+	}
+	return c
+}
+
+// mergeSettings returns the settings of src to be merged into dst, e.g. the default
+// versions, the differing ones colliding are resolved by the policy like the methods
+func mergeSettings(dst, src map[string]string, policy MergePolicy) (map[string]string, error) {
+	merged := make(map[string]string, len(src))
+	for name, v := range src {
+		newName := name
+		if cur, exists := dst[name]; exists && cur != v {
+			var err error
+			if newName, err = policy(name); err != nil {
+				return nil, err
+			}
+			if _, exists := dst[newName]; exists && newName != name {
+				return nil, fmt.Errorf("%s: %w", newName, ErrMethodExists)
+			}
+		}
+		merged[newName] = v
+	}
+	return merged, nil
+}
@@ -0,0 +1,117 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	lib := New()
+	s := &service{}
+	lib.Register(s, &Monitor{})
+
+	app := New()
+	app.Register(&counters{})
+	if err := app.Merge(lib, MergeError); err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Dump()) != 8 {
+		t.Errorf("Should be 8 methods got %d", len(app.Dump()))
+	}
+	// receivers are shared
+	app.Call("service.Run")
+	if !s.running {
+		t.Error("service should be running")
+	}
+
+	if err := app.Merge(lib, MergeError); !errors.Is(err, ErrMethodExists) {
+		t.Errorf("should failed due to collision got %v", err)
+	}
+	if err := app.Merge(lib, MergePrefix("lib")); err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Dump()) != 14 {
+		t.Errorf("Should be 14 methods got %d", len(app.Dump()))
+	}
+	if rets, err := app.Call("lib.Monitor.Display"); err != nil || rets[0] != "Display()" {
+		t.Errorf("Should be Display() got %v %v", rets, err)
+	}
+	if mi, _ := app.Info("lib.Monitor.Display"); mi.Signature != "lib.Monitor.Display() string" {
+		t.Errorf("Unexpected signature %s", mi.Signature)
+	}
+	if err := app.Merge(lib, MergePrefix("lib")); !errors.Is(err, ErrMethodExists) {
+		t.Errorf("should failed due to prefixed collision got %v", err)
+	}
+
+	other := New()
+	other.Register(&service{running: true})
+	if err := app.Merge(other, MergeOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	s.running = false
+	if rets, _ := app.Call("service.Running"); !rets[0].(bool) {
+		t.Error("service.Running should be overwritten")
+	}
+}
+
+func TestMergeOwnState(t *testing.T) {
+	lib := New()
+	lib.Register(&flaky{failures: 10})
+	lib.SetBreaker("flaky.Fetch", BreakerPolicy{Threshold: 1, OpenFor: time.Minute})
+	app := New()
+	if err := app.Merge(lib, MergeError); err != nil {
+		t.Fatal(err)
+	}
+	app.Call("flaky.Fetch", 1)
+	if _, err := app.Call("flaky.Fetch", 1); err != ErrCircuitOpen {
+		t.Errorf("Should be circuit open got %v", err)
+	}
+	if _, err := lib.Call("flaky.Fetch", 1); err == ErrCircuitOpen {
+		t.Error("Should not share the breaker")
+	}
+}
+
+func TestMergeRegistrySettings(t *testing.T) {
+	lib := New()
+	lib.RegisterLazy("service", func() interface{} {
+		return &service{}
+	})
+	lib.RegisterVersion("v1", &reporter{"v1"})
+	lib.RegisterVersion("v2", &reporter{"v2"})
+	lib.SetDefaultVersion("reporter.Format", "v1")
+	lib.AliasNamespace("svc", "service")
+	app := New()
+	if err := app.Merge(lib, MergeError); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Call("service.Run"); err != nil {
+		t.Error(err)
+	}
+	if rets, err := app.Call("reporter.Format"); err != nil || rets[0] != "v1" {
+		t.Errorf("Should be v1 got %v %v", rets, err)
+	}
+	if _, err := app.Call("svc.Run"); err != nil {
+		t.Error(err)
+	}
+
+	lazy := New()
+	lazy.RegisterLazy("service", func() interface{} {
+		return &service{running: true}
+	})
+	if err := app.Merge(lazy, MergeError); !errors.Is(err, ErrMethodExists) {
+		t.Errorf("should failed due to collision got %v", err)
+	}
+	if err := app.Merge(lazy, MergePrefix("lib")); err != nil {
+		t.Fatal(err)
+	}
+	if rets, err := app.Call("lib.service.Running"); err != nil || !rets[0].(bool) {
+		t.Errorf("Should be running got %v %v", rets, err)
+	}
+	if err := app.Merge(lazy, MergeOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	if rets, err := app.Call("service.Running"); err != nil || !rets[0].(bool) {
+		t.Errorf("Should be overwritten got %v %v", rets, err)
+	}
+}
@@ -0,0 +1,178 @@
+// Package mock provides a recording funcutil.Caller for the tests of the code calling
+// the registered methods, without the real services behind them.
+//
+//	m := mock.New()
+//	m.On("service.Stop").Return(nil)
+//	m.On("service.Info").Return("Running: false").Times(1)
+//	shutdown(m) // takes a funcutil.Caller
+//	m.AssertCalled(t, "service.Stop", true)
+//	m.AssertExpectations(t)
+package mock
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// TestingT is the part of testing.T used by the assertions
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Call is a recorded call
+type Call struct {
+	Method string
+	Params []interface{}
+}
+
+// Stub is the behavior of a stubbed method, it returns no results by default
+type Stub struct {
+	results []interface{}
+	err     error
+	fn      func(params []interface{}) ([]interface{}, error)
+	// times is the expected number of calls, negative for at least one
+	times int
+}
+
+// Return sets the results of the method
+func (s *Stub) Return(results ...interface{}) *Stub {
+	s.results = results
+	return s
+}
+
+// ReturnError makes the calls fail with err, like a failed call of the registry
+func (s *Stub) ReturnError(err error) *Stub {
+	s.err = err
+	return s
+}
+
+// Do computes the results of the method from the params
+func (s *Stub) Do(fn func(params []interface{}) ([]interface{}, error)) *Stub {
+	s.fn = fn
+	return s
+}
+
+// Times expects the method to be called exactly n times
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+// Registry records the calls and returns the results of the stubs,
+// the calls of the methods not stubbed fail with funcutil.ErrMethodNotFound
+type Registry struct {
+	sync.Mutex
+	stubs map[string]*Stub
+	calls []Call
+}
+
+var _ funcutil.Caller = (*Registry)(nil)
+
+// New returns an empty mock registry
+func New() *Registry {
+	return &Registry{stubs: map[string]*Stub{}}
+}
+
+// On stubs the method, replacing its previous stub
+func (r *Registry) On(methodName string) *Stub {
+	r.Lock()
+	defer r.Unlock()
+	s := &Stub{times: -1}
+	r.stubs[methodName] = s
+	return s
+}
+
+// Call records the call and returns the stubbed results
+func (r *Registry) Call(methodName string, params ...interface{}) ([]interface{}, error) {
+	return r.CallContext(context.Background(), methodName, params...)
+}
+
+// CallContext records the call and returns the stubbed results
+func (r *Registry) CallContext(ctx context.Context, methodName string, params ...interface{}) ([]interface{}, error) {
+	r.Lock()
+	r.calls = append(r.calls, Call{Method: methodName, Params: params})
+	s, exists := r.stubs[methodName]
+	r.Unlock()
+	if !exists {
+		return nil, funcutil.ErrMethodNotFound
+	}
+	if s.fn != nil {
+		return s.fn(params)
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.results, nil
+}
+
+// Calls returns the recorded calls in order
+func (r *Registry) Calls() []Call {
+	r.Lock()
+	defer r.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+// CallsTo returns the recorded calls of the method in order
+func (r *Registry) CallsTo(methodName string) []Call {
+	calls := []Call{}
+	for _, c := range r.Calls() {
+		if c.Method == methodName {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls, the stubs are kept
+func (r *Registry) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled fails t unless the method was called with the params
+func (r *Registry) AssertCalled(t TestingT, methodName string, params ...interface{}) bool {
+	for _, c := range r.CallsTo(methodName) {
+		if reflect.DeepEqual(c.Params, params) || (len(c.Params) == 0 && len(params) == 0) {
+			return true
+		}
+	}
+	t.Errorf("%s should be called with %v, got %v", methodName, params, r.CallsTo(methodName))
+	return false
+}
+
+// AssertNotCalled fails t if the method was called
+func (r *Registry) AssertNotCalled(t TestingT, methodName string) bool {
+	if calls := r.CallsTo(methodName); len(calls) > 0 {
+		t.Errorf("%s should not be called, got %v", methodName, calls)
+		return false
+	}
+	return true
+}
+
+// AssertExpectations fails t unless every stubbed method was called the expected
+// number of times, at least once unless set by Times
+func (r *Registry) AssertExpectations(t TestingT) bool {
+	r.Lock()
+	stubs := map[string]*Stub{}
+	for name, s := range r.stubs {
+		stubs[name] = s
+	}
+	r.Unlock()
+	ok := true
+	for name, s := range stubs {
+		n := len(r.CallsTo(name))
+		switch {
+		case s.times < 0 && n == 0:
+			t.Errorf("%s should be called", name)
+			ok = false
+		case s.times >= 0 && n != s.times:
+			t.Errorf("%s should be called %d times, got %d", name, s.times, n)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// shutdown is the code under test
+func shutdown(c funcutil.Caller) (string, error) {
+	if _, err := c.Call("service.Stop", true); err != nil {
+		return "", err
+	}
+	rets, err := c.Call("service.Info")
+	if err != nil {
+		return "", err
+	}
+	return rets[0].(string), nil
+}
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRegistry(t *testing.T) {
+	m := New()
+	m.On("service.Stop")
+	m.On("service.Info").Return("Running: false").Times(1)
+	info, err := shutdown(m)
+	if err != nil || info != "Running: false" {
+		t.Errorf("Should be Running: false got %v %v", info, err)
+	}
+	m.AssertCalled(t, "service.Stop", true)
+	m.AssertNotCalled(t, "service.Run")
+	m.AssertExpectations(t)
+	if calls := m.Calls(); len(calls) != 2 || calls[1].Method != "service.Info" {
+		t.Errorf("Should be 2 calls got %v", calls)
+	}
+
+	stopped := errors.New("Already stopped")
+	m.On("service.Stop").ReturnError(stopped)
+	if _, err := shutdown(m); err != stopped {
+		t.Errorf("should failed due to already stopped got %v", err)
+	}
+	m.On("service.Stop").Do(func(params []interface{}) ([]interface{}, error) {
+		if params[0] != true {
+			return nil, errors.New("Should wait")
+		}
+		return nil, nil
+	})
+	if _, err := m.Call("service.Stop", false); err == nil {
+		t.Error("should failed due to not waiting")
+	}
+	if _, err := m.Call("service.Run"); err != funcutil.ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+
+	r := &recorder{}
+	m.Reset()
+	if m.AssertCalled(r, "service.Stop", true) || m.AssertExpectations(r) || len(r.errors) != 3 {
+		t.Errorf("Should be 3 failures got %v", r.errors)
+	}
+}
@@ -0,0 +1,83 @@
+package funcutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ConversionMode tells how the params are converted into the parameter types
+type ConversionMode int
+
+const (
+	// Lenient applies the registered converters and the built-in coercions, the default
+	Lenient ConversionMode = iota
+	// Strict requires the params to be of the parameter types (or assignable to the
+	// interface parameters), only the raw JSON and MessagePack params are decoded
+	Strict
+)
+
+type conversionModeKey struct{}
+
+// SetConversionMode sets the conversion mode of the registry
+func (f *FuncUtil) SetConversionMode(mode ConversionMode) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.mode = mode
+	return nil
+}
+
+// ContextWithConversionMode returns a copy of ctx overriding the conversion mode of
+// the registry for the calls made with it, see CallContext
+//
+//	f.CallContext(funcutil.ContextWithConversionMode(ctx, funcutil.Strict), "service.Stop", true)
+func ContextWithConversionMode(ctx context.Context, mode ConversionMode) context.Context {
+	return context.WithValue(ctx, conversionModeKey{}, mode)
+}
+
+// withContext returns the options applying the overrides of ctx
+func (o options) withContext(ctx context.Context) options {
+	if mode, ok := ctx.Value(conversionModeKey{}).(ConversionMode); ok {
+		o.mode = mode
+	}
+	return o
+}
+
+// strictValue returns the value of p as type t without coercion
+func (o options) strictValue(p interface{}, t reflect.Type) (reflect.Value, error) {
+	pt := reflect.TypeOf(p)
+	v := reflect.ValueOf(p)
+	if pt == t {
+		return v, nil
+	}
+	if t.Kind() == reflect.Interface && pt.AssignableTo(t) {
+		return v.Convert(t), nil
+	}
+	switch p.(type) {
+	case json.RawMessage, MsgpackRaw:
+		if uv, handled, err := unmarshalArg(p, t); handled {
+			if err != nil {
+				return v, fmt.Errorf("arguments: %w", err)
+			}
+			return uv, nil
+		}
+	}
+	return v, fmt.Errorf("arguments: %v is not %v", pt, t)
+}
+
+// WithNoImplicitConversion disables the built-in Go conversions between the param and
+// parameter types, e.g. float64 to int or int to string, so any mismatch is an error.
+// The registered converters and the decoding of the params still apply.
+func (f *FuncUtil) WithNoImplicitConversion() error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.noImplicit = true
+	return nil
+}
@@ -0,0 +1,54 @@
+package funcutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestConversionMode(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	if rets, err := f.Call("calculator.Add", 1.9, 1); err != nil || rets[0] != int64(2) {
+		t.Errorf("Lenient should truncate got %v %v", rets, err)
+	}
+	f.SetConversionMode(Strict)
+	if _, err := f.Call("calculator.Add", 1.9, 1); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to strict mode got %v", err)
+	}
+	if rets, err := f.Call("calculator.Add", int64(1), json.RawMessage("2")); err != nil || rets[0] != int64(3) {
+		t.Errorf("Should be 3 got %v %v", rets, err)
+	}
+	if rets, err := f.Call("calculator.Describe", 1.5); err != nil || rets[0] != "float64" {
+		t.Errorf("Should be float64 got %v %v", rets, err)
+	}
+	ctx := ContextWithConversionMode(context.Background(), Lenient)
+	if rets, err := f.CallContext(ctx, "calculator.Add", 1.9, 1); err != nil || rets[0] != int64(2) {
+		t.Errorf("Lenient override should truncate got %v %v", rets, err)
+	}
+	f.SetConversionMode(Lenient)
+	plan, _ := f.Compile("calculator.Add")
+	ctx = ContextWithConversionMode(context.Background(), Strict)
+	if _, err := plan.InvokeContext(ctx, 1, 2); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to strict override got %v", err)
+	}
+}
+
+func TestWithNoImplicitConversion(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	f.WithNoImplicitConversion()
+	if _, err := f.Call("calculator.Add", 1.9, 1); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to implicit conversion got %v", err)
+	}
+	if _, err := f.Call("calculator.Add", int64(1), int32(2)); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to implicit conversion got %v", err)
+	}
+	if rets, err := f.Call("calculator.Add", int64(1), json.RawMessage("2")); err != nil || rets[0] != int64(3) {
+		t.Errorf("Should be 3 got %v %v", rets, err)
+	}
+	if rets, err := f.Call("calculator.Sum", []interface{}{1.0, 2.0}, 2.0); err != nil || rets[0] != 6.0 {
+		t.Errorf("Should be 6 got %v %v", rets, err)
+	}
+}
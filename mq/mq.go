@@ -0,0 +1,127 @@
+// Package mq drives a funcutil registry from a message queue.
+//
+// A Transport receives the messages carrying the method name and its params encoded
+// by the consumer codec, the Consumer calls the method then acknowledges the message
+// and sends back the results according to its Policy. The credential of the message is
+// authenticated by the registry before the call, see funcutil.FuncUtil.Authenticate.
+package mq
+
+import (
+	"context"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Message is a call received from the queue
+type Message struct {
+	// ID identifies the message within the queue, it is used for acknowledgement
+	ID     string
+	Method string
+	Params []byte
+	// ReplyTo is where the response goes, no response is sent when empty
+	ReplyTo string
+	// Credential is given to the authenticator of the registry
+	Credential string
+}
+
+// Response is the outcome of a call
+type Response struct {
+	// Results are encoded by the consumer codec, see funcutil.ResultValues
+	Results []byte
+	Error   string
+}
+
+// Transport is the queue consumed by a Consumer
+type Transport interface {
+	// Receive blocks until a message is available or the context is done
+	Receive(ctx context.Context) (Message, error)
+	// Reply sends the response of the message to its ReplyTo
+	Reply(ctx context.Context, m Message, r Response) error
+	// Ack removes the message from the queue
+	Ack(ctx context.Context, m Message) error
+}
+
+// Policy decides which messages are acknowledged
+type Policy int
+
+const (
+	// AckAlways acknowledges every handled message
+	AckAlways Policy = iota
+	// AckOnSuccess leaves the failed calls in the queue to be delivered again
+	AckOnSuccess
+)
+
+// Consumer calls the methods requested by the transport messages
+type Consumer struct {
+	f      *funcutil.FuncUtil
+	t      Transport
+	codec  funcutil.Codec
+	policy Policy
+}
+
+// NewConsumer creates a JSON consumer acknowledging every message
+func NewConsumer(f *funcutil.FuncUtil, t Transport) *Consumer {
+	return &Consumer{
+		f:      f,
+		t:      t,
+		codec:  funcutil.JSONCodec,
+		policy: AckAlways,
+	}
+}
+
+// SetCodec sets the encoding of the params and results
+func (c *Consumer) SetCodec(codec funcutil.Codec) {
+	c.codec = codec
+}
+
+// SetPolicy sets the acknowledgement policy
+func (c *Consumer) SetPolicy(p Policy) {
+	c.policy = p
+}
+
+// Run handles the messages until the context is done or the transport fails
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		m, err := c.t.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if err := c.Handle(ctx, m); err != nil {
+			return err
+		}
+	}
+}
+
+// Handle calls the method of a single message, only the transport errors are returned
+func (c *Consumer) Handle(ctx context.Context, m Message) error {
+	resp := Response{}
+	out, err := c.call(ctx, m)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	if out == nil {
+		out, _ = c.codec.Marshal([]interface{}{})
+	}
+	resp.Results = out
+	if m.ReplyTo != "" {
+		if err := c.t.Reply(ctx, m, resp); err != nil {
+			return err
+		}
+	}
+	if resp.Error == "" || c.policy == AckAlways {
+		return c.t.Ack(ctx, m)
+	}
+	return nil
+}
+
+// call authenticates the credential of the message and invokes the method
+func (c *Consumer) call(ctx context.Context, m Message) ([]byte, error) {
+	ctx, err := c.f.Authenticate(ctx, m.Credential)
+	if err != nil {
+		return nil, err
+	}
+	return c.f.CallCodecContext(ctx, c.codec, m.Method, m.Params)
+}
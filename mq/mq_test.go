@@ -0,0 +1,100 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type greeter struct {
+}
+
+func (g *greeter) Hello(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("empty name")
+	}
+	return "Hello " + name, nil
+}
+
+// queue is an in memory Transport
+type queue struct {
+	pending []Message
+	acked   []string
+	replies map[string]Response
+}
+
+func (q *queue) Receive(ctx context.Context) (Message, error) {
+	if len(q.pending) == 0 {
+		return Message{}, errors.New("queue is empty")
+	}
+	m := q.pending[0]
+	q.pending = q.pending[1:]
+	return m, nil
+}
+
+func (q *queue) Reply(ctx context.Context, m Message, r Response) error {
+	q.replies[m.ReplyTo] = r
+	return nil
+}
+
+func (q *queue) Ack(ctx context.Context, m Message) error {
+	q.acked = append(q.acked, m.ID)
+	return nil
+}
+
+func TestConsumer(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&greeter{})
+	q := &queue{
+		pending: []Message{
+			{ID: "1", Method: "greeter.Hello", Params: []byte(`["gopher"]`), ReplyTo: "r1"},
+			{ID: "2", Method: "greeter.Hello", Params: []byte(`[""]`), ReplyTo: "r2"},
+			{ID: "3", Method: "greeter.NotExists", ReplyTo: "r3"},
+		},
+		replies: map[string]Response{},
+	}
+	c := NewConsumer(f, q)
+	c.SetPolicy(AckOnSuccess)
+	if err := c.Run(context.Background()); err == nil || err.Error() != "queue is empty" {
+		t.Errorf("Should fail with queue is empty got %v", err)
+	}
+	if r := q.replies["r1"]; string(r.Results) != `["Hello gopher",null]` || r.Error != "" {
+		t.Errorf("Unexpected reply %s %s", r.Results, r.Error)
+	}
+	if r := q.replies["r2"]; string(r.Results) != `["","empty name"]` || r.Error != "empty name" {
+		t.Errorf("Unexpected reply %s %s", r.Results, r.Error)
+	}
+	if r := q.replies["r3"]; string(r.Results) != `[]` || r.Error != "Method not found" {
+		t.Errorf("Unexpected reply %s %s", r.Results, r.Error)
+	}
+	if len(q.acked) != 1 || q.acked[0] != "1" {
+		t.Errorf("Should be [1] got %v", q.acked)
+	}
+}
+
+func TestConsumerAuthenticate(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&greeter{})
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, credential string) (funcutil.Identity, error) {
+		if credential != "s3cret" {
+			return funcutil.Identity{}, funcutil.ErrUnauthenticated
+		}
+		return funcutil.Identity{Name: "worker"}, nil
+	}))
+	q := &queue{
+		pending: []Message{
+			{ID: "1", Method: "greeter.Hello", Params: []byte(`["gopher"]`), ReplyTo: "r1"},
+			{ID: "2", Method: "greeter.Hello", Params: []byte(`["gopher"]`), ReplyTo: "r2", Credential: "s3cret"},
+		},
+		replies: map[string]Response{},
+	}
+	NewConsumer(f, q).Run(context.Background())
+	if r := q.replies["r1"]; r.Error != funcutil.ErrUnauthenticated.Error() {
+		t.Errorf("should failed due to missing credential got %s %s", r.Results, r.Error)
+	}
+	if r := q.replies["r2"]; string(r.Results) != `["Hello gopher",null]` || r.Error != "" {
+		t.Errorf("Unexpected reply %s %s", r.Results, r.Error)
+	}
+}
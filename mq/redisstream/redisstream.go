@@ -0,0 +1,135 @@
+// Package redisstream is a mq.Transport reading the calls from a Redis stream
+// through a consumer group.
+//
+// The stream entries have the fields method, params and optionally reply_to and
+// credential, the responses are added to the reply_to stream with the fields id,
+// results and error:
+//
+//	XADD calls * method service.Stop params [true] reply_to replies
+package redisstream
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kadekcipta/funcutil/mq"
+)
+
+// Transport consumes a Redis stream over a connection speaking RESP
+type Transport struct {
+	sync.Mutex
+	r        *bufio.Reader
+	w        io.Writer
+	stream   string
+	group    string
+	consumer string
+	block    time.Duration
+}
+
+// New creates the transport reading the stream as the consumer of the group,
+// the group is created when missing
+//
+//	conn, _ := net.Dial("tcp", "localhost:6379")
+//	t, err := redisstream.New(conn, "calls", "workers", hostname)
+func New(conn io.ReadWriter, stream, group, consumer string) (*Transport, error) {
+	t := &Transport{
+		r:        bufio.NewReader(conn),
+		w:        conn,
+		stream:   stream,
+		group:    group,
+		consumer: consumer,
+		block:    time.Second,
+	}
+	_, err := t.do("XGROUP", "CREATE", stream, group, "$", "MKSTREAM")
+	if err, ok := err.(redisError); ok && len(err) >= 9 && err[:9] == "BUSYGROUP" {
+		return t, nil
+	}
+	return t, err
+}
+
+func (t *Transport) do(args ...string) (interface{}, error) {
+	t.Lock()
+	defer t.Unlock()
+	if err := writeCommand(t.w, args...); err != nil {
+		return nil, err
+	}
+	reply, err := readReply(t.r)
+	if err != nil {
+		return nil, err
+	}
+	if err, ok := reply.(redisError); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Receive waits for the next entry delivered to the consumer
+func (t *Transport) Receive(ctx context.Context) (mq.Message, error) {
+	block := strconv.FormatInt(int64(t.block/time.Millisecond), 10)
+	for {
+		if err := ctx.Err(); err != nil {
+			return mq.Message{}, err
+		}
+		reply, err := t.do("XREADGROUP", "GROUP", t.group, t.consumer, "COUNT", "1", "BLOCK", block, "STREAMS", t.stream, ">")
+		if err != nil {
+			return mq.Message{}, err
+		}
+		if m, ok := parseEntry(reply); ok {
+			return m, nil
+		}
+	}
+}
+
+// parseEntry extracts the message from [[stream, [[id, [field, value...]]]]]
+func parseEntry(reply interface{}) (mq.Message, bool) {
+	m := mq.Message{}
+	streams, _ := reply.([]interface{})
+	if len(streams) == 0 {
+		return m, false
+	}
+	stream, _ := streams[0].([]interface{})
+	if len(stream) < 2 {
+		return m, false
+	}
+	entries, _ := stream[1].([]interface{})
+	if len(entries) == 0 {
+		return m, false
+	}
+	entry, _ := entries[0].([]interface{})
+	if len(entry) < 2 {
+		return m, false
+	}
+	m.ID, _ = entry[0].(string)
+	fields, _ := entry[1].([]interface{})
+	for i := 0; i+1 < len(fields); i += 2 {
+		name, _ := fields[i].(string)
+		value, _ := fields[i+1].(string)
+		switch name {
+		case "method":
+			m.Method = value
+		case "params":
+			m.Params = []byte(value)
+		case "reply_to":
+			m.ReplyTo = value
+		case "credential":
+			m.Credential = value
+		}
+	}
+	return m, true
+}
+
+// Reply adds the response to the reply_to stream of the message
+func (t *Transport) Reply(ctx context.Context, m mq.Message, r mq.Response) error {
+	_, err := t.do("XADD", m.ReplyTo, "*", "id", m.ID, "results", string(r.Results), "error", r.Error)
+	return err
+}
+
+// Ack acknowledges the entry within the consumer group
+func (t *Transport) Ack(ctx context.Context, m mq.Message) error {
+	_, err := t.do("XACK", t.stream, t.group, m.ID)
+	return err
+}
@@ -0,0 +1,61 @@
+package redisstream
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kadekcipta/funcutil/mq"
+)
+
+// conn replays the canned replies and records the commands
+type conn struct {
+	replies *strings.Reader
+	sent    bytes.Buffer
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	return c.replies.Read(p)
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	return c.sent.Write(p)
+}
+
+func TestTransport(t *testing.T) {
+	c := &conn{replies: strings.NewReader(strings.Join([]string{
+		"-BUSYGROUP Consumer Group name already exists\r\n",
+		"*-1\r\n",
+		"*1\r\n*2\r\n$5\r\ncalls\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*6\r\n$6\r\nmethod\r\n$12\r\nservice.Stop\r\n$6\r\nparams\r\n$6\r\n[true]\r\n$8\r\nreply_to\r\n$7\r\nreplies\r\n",
+		"$3\r\n2-0\r\n",
+		":1\r\n",
+	}, ""))}
+	tr, err := New(c, "calls", "workers", "w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := tr.Receive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != "1-0" || m.Method != "service.Stop" || string(m.Params) != "[true]" || m.ReplyTo != "replies" {
+		t.Errorf("Unexpected message %+v", m)
+	}
+	if err := tr.Reply(context.Background(), m, mq.Response{Results: []byte("[]")}); err != nil {
+		t.Error(err)
+	}
+	if err := tr.Ack(context.Background(), m); err != nil {
+		t.Error(err)
+	}
+	expect := "*6\r\n$6\r\nXGROUP\r\n$6\r\nCREATE\r\n$5\r\ncalls\r\n$7\r\nworkers\r\n$1\r\n$\r\n$8\r\nMKSTREAM\r\n"
+	if !strings.HasPrefix(c.sent.String(), expect) {
+		t.Errorf("Unexpected command %q", c.sent.String())
+	}
+	if !strings.HasSuffix(c.sent.String(), "*4\r\n$4\r\nXACK\r\n$5\r\ncalls\r\n$7\r\nworkers\r\n$3\r\n1-0\r\n") {
+		t.Errorf("Unexpected command %q", c.sent.String())
+	}
+	if _, err := tr.Receive(context.Background()); err == nil {
+		t.Error("should failed due to closed connection")
+	}
+}
@@ -0,0 +1,119 @@
+// Package mqtt exposes a funcutil registry over an MQTT broker.
+//
+// Requests are published to <topic>/<method name>, where the slashes of the topic
+// suffix may be used instead of the dots of the method name. The payload is either
+// the JSON array of the params or a request object:
+//
+//	{"id": 1, "params": [true], "reply_to": "clients/42/replies"}
+//
+// The response is published to the reply_to topic of the request, or to
+// <reply topic>/<method name> when the server has a reply topic:
+//
+//	{"id": 1, "method": "service.Stop", "results": [], "error": ""}
+//
+// The package doesn't depend on a specific MQTT library, Client is easily
+// implemented over e.g. github.com/eclipse/paho.mqtt.golang.
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Client is the subset of an MQTT client used by the server
+type Client interface {
+	// Subscribe calls handler for every message published to the topic filter
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+	Publish(topic string, payload []byte) error
+}
+
+type request struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ReplyTo string          `json:"reply_to,omitempty"`
+	// Credential is given to the authenticator of the registry
+	Credential string `json:"credential,omitempty"`
+}
+
+type response struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Results json.RawMessage `json:"results"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// Server maps the MQTT requests to the registry calls
+type Server struct {
+	f          *funcutil.FuncUtil
+	topic      string
+	replyTopic string
+}
+
+// NewServer creates a server taking the requests from <topic>/<method name> and
+// publishing the responses to <replyTopic>/<method name>, replyTopic may be empty
+// when all the requests carry their reply_to topic
+func NewServer(f *funcutil.FuncUtil, topic, replyTopic string) *Server {
+	return &Server{
+		f:          f,
+		topic:      strings.TrimSuffix(topic, "/"),
+		replyTopic: strings.TrimSuffix(replyTopic, "/"),
+	}
+}
+
+// Serve subscribes to the request topics of the server
+func (s *Server) Serve(c Client) error {
+	return c.Subscribe(s.topic+"/#", func(topic string, payload []byte) {
+		s.handle(c, topic, payload)
+	})
+}
+
+func (s *Server) handle(c Client, topic string, payload []byte) {
+	suffix := strings.TrimPrefix(topic, s.topic+"/")
+	method := strings.Replace(suffix, "/", ".", -1)
+	req := request{}
+	payload = bytes.TrimSpace(payload)
+	resp := response{Method: method}
+	if len(payload) > 0 && payload[0] == '{' {
+		if err := json.Unmarshal(payload, &req); err != nil {
+			resp.Error = err.Error()
+		}
+	} else {
+		req.Params = payload
+	}
+	resp.ID = req.ID
+	if resp.Error == "" {
+		resp.Results, resp.Error = s.call(method, req)
+	}
+	if resp.Results == nil {
+		resp.Results = json.RawMessage("[]")
+	}
+	replyTo := req.ReplyTo
+	if replyTo == "" {
+		if s.replyTopic == "" {
+			return
+		}
+		replyTo = s.replyTopic + "/" + suffix
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	c.Publish(replyTo, out)
+}
+
+// call authenticates the credential of the request and invokes the method
+func (s *Server) call(method string, req request) (json.RawMessage, string) {
+	ctx, err := s.f.Authenticate(context.Background(), req.Credential)
+	if err != nil {
+		return nil, err.Error()
+	}
+	results, err := s.f.CallCodecContext(ctx, funcutil.JSONCodec, method, req.Params)
+	if err != nil {
+		return results, err.Error()
+	}
+	return results, ""
+}
@@ -0,0 +1,98 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type service struct {
+	running bool
+}
+
+func (s *service) Stop(wait bool) {
+	s.running = false
+}
+
+func (s *service) Running() bool {
+	return s.running
+}
+
+// broker is an in memory Client supporting the # wildcard only
+type broker struct {
+	subs      map[string]func(topic string, payload []byte)
+	published map[string][]byte
+}
+
+func (b *broker) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	b.subs[topic] = handler
+	return nil
+}
+
+func (b *broker) Publish(topic string, payload []byte) error {
+	b.published[topic] = payload
+	for filter, handler := range b.subs {
+		if strings.HasPrefix(topic, strings.TrimSuffix(filter, "#")) {
+			handler(topic, payload)
+		}
+	}
+	return nil
+}
+
+func TestServer(t *testing.T) {
+	f := funcutil.New()
+	s := &service{running: true}
+	f.Register(s)
+	b := &broker{subs: map[string]func(string, []byte){}, published: map[string][]byte{}}
+	if err := NewServer(f, "devices/call", "devices/reply").Serve(b); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Publish("devices/call/service/Stop", []byte(`[true]`))
+	if s.running {
+		t.Error("service should be stopped")
+	}
+	if string(b.published["devices/reply/service/Stop"]) != `{"method":"service.Stop","results":[]}` {
+		t.Errorf("Unexpected reply %s", b.published["devices/reply/service/Stop"])
+	}
+
+	b.Publish("devices/call/service.Running", []byte(`{"id": 7, "reply_to": "clients/1"}`))
+	resp := response{}
+	if err := json.Unmarshal(b.published["clients/1"], &resp); err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.ID) != "7" || string(resp.Results) != "[false]" || resp.Error != "" {
+		t.Errorf("Unexpected reply %s", b.published["clients/1"])
+	}
+
+	b.Publish("devices/call/service.NotExists", nil)
+	if !strings.Contains(string(b.published["devices/reply/service.NotExists"]), `"error":"Method not found"`) {
+		t.Errorf("Unexpected reply %s", b.published["devices/reply/service.NotExists"])
+	}
+}
+
+func TestServerAuthenticate(t *testing.T) {
+	f := funcutil.New()
+	s := &service{running: true}
+	f.Register(s)
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, credential string) (funcutil.Identity, error) {
+		if credential != "Bearer t0k3n" {
+			return funcutil.Identity{}, funcutil.ErrUnauthenticated
+		}
+		return funcutil.Identity{Name: "device"}, nil
+	}))
+	b := &broker{subs: map[string]func(string, []byte){}, published: map[string][]byte{}}
+	NewServer(f, "devices/call", "devices/reply").Serve(b)
+
+	b.Publish("devices/call/service/Stop", []byte(`[true]`))
+	if !s.running || !strings.Contains(string(b.published["devices/reply/service/Stop"]), funcutil.ErrUnauthenticated.Error()) {
+		t.Errorf("should failed due to missing credential got %s", b.published["devices/reply/service/Stop"])
+	}
+	b.Publish("devices/call/service/Stop", []byte(`{"params": [true], "credential": "Bearer t0k3n"}`))
+	if s.running {
+		t.Errorf("service should be stopped got %s", b.published["devices/reply/service/Stop"])
+	}
+}
@@ -0,0 +1,722 @@
+package funcutil
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// MsgpackRaw is an encoded MessagePack value, the params decoded by MsgpackCodec
+// are decoded straight into their parameter types by Call
+type MsgpackRaw []byte
+
+type msgpackCodec struct {
+}
+
+func (msgpackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return MarshalMsgpack(v)
+}
+
+// UnmarshalParams splits a MessagePack array into raw params
+func (msgpackCodec) UnmarshalParams(data []byte) ([]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	d := &msgpackDecoder{data: data}
+	n, err := d.arrayLen()
+	if err != nil {
+		return nil, fmt.Errorf("arguments: %v", err)
+	}
+	params := make([]interface{}, n)
+	for i := range params {
+		start := d.pos
+		if err := d.skip(); err != nil {
+			return nil, fmt.Errorf("arguments: %v", err)
+		}
+		params[i] = MsgpackRaw(data[start:d.pos])
+	}
+	return params, nil
+}
+
+// MsgpackCodec encodes the calls as MessagePack arrays, the structs are encoded
+// as maps keyed by their field names honoring the json tags
+var MsgpackCodec Codec = msgpackCodec{}
+
+func init() {
+	RegisterCodec(MsgpackCodec)
+}
+
+// CallMsgpack invokes the method with the params decoded from a MessagePack array
+// and returns the results encoded the same way, like CallJSON
+func (f *FuncUtil) CallMsgpack(methodName string, payload []byte) ([]byte, error) {
+	return f.CallCodec(MsgpackCodec, methodName, payload)
+}
+
+// MarshalMsgpack encodes v as MessagePack
+func MarshalMsgpack(v interface{}) ([]byte, error) {
+	e := &msgpackEncoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// UnmarshalMsgpack decodes the MessagePack data into the value pointed by v
+func UnmarshalMsgpack(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrNotPointer
+	}
+	d := &msgpackDecoder{data: data}
+	return d.decode(rv.Elem())
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) header(fix, max byte, b8, b16, b32 byte, n int) {
+	switch {
+	case n <= int(max) && fix != 0:
+		e.buf = append(e.buf, fix|byte(n))
+	case n <= math.MaxUint8 && b8 != 0:
+		e.buf = append(e.buf, b8, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, b16)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, b32)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) str(s string) {
+	e.header(0xa0, 31, 0xd9, 0xda, 0xdb, len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *msgpackEncoder) int(i int64) {
+	switch {
+	case i >= 0:
+		e.uint(uint64(i))
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(i))
+	case i >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(i))
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(i))
+	}
+}
+
+func (e *msgpackEncoder) uint(u uint64) {
+	switch {
+	case u <= 127:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(u))
+	case u <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(u))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, u)
+	}
+}
+
+func (e *msgpackEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, 0xc0)
+		return nil
+	}
+	if raw, ok := v.Interface().(MsgpackRaw); ok {
+		e.buf = append(e.buf, raw...)
+		return nil
+	}
+	if v.Type().Implements(textMarshalerType) && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		e.str(string(text))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.uint(v.Uint())
+	case reflect.Float32:
+		e.buf = append(e.buf, 0xca)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.str(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.header(0, 0, 0xc4, 0xc5, 0xc6, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				e.buf = append(e.buf, byte(v.Index(i).Uint()))
+			}
+			return nil
+		}
+		e.header(0x90, 15, 0, 0xdc, 0xdd, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0xc0)
+			return nil
+		}
+		e.header(0x80, 15, 0, 0xde, 0xdf, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := e.encode(iter.Key()); err != nil {
+				return err
+			}
+			if err := e.encode(iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		names, fields := []string{}, []int{}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			name := sf.Name
+			if tag := sf.Tag.Get("json"); tag != "" {
+				if tag == "-" {
+					continue
+				}
+				parts := strings.Split(tag, ",")
+				if parts[0] != "" {
+					name = parts[0]
+				}
+				if len(parts) > 1 && parts[1] == "omitempty" && v.Field(i).IsZero() {
+					continue
+				}
+			}
+			names = append(names, name)
+			fields = append(fields, i)
+		}
+		e.header(0x80, 15, 0, 0xde, 0xdf, len(fields))
+		for i, field := range fields {
+			e.str(names[i])
+			if err := e.encode(v.Field(field)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+var (
+	errMsgpackShort = errors.New("msgpack: unexpected end of data")
+	errMsgpackDepth = errors.New("msgpack: exceeded max depth")
+)
+
+// maxMsgpackDepth bounds the nesting of the arrays and maps like encoding/json does
+const maxMsgpackDepth = 10000
+
+type msgpackDecoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+// enter steps into an array or a map, the caller leaves it when done
+func (d *msgpackDecoder) enter() error {
+	d.depth++
+	if d.depth > maxMsgpackDepth {
+		return errMsgpackDepth
+	}
+	return nil
+}
+
+func (d *msgpackDecoder) leave() {
+	d.depth--
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errMsgpackShort
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *msgpackDecoder) peek() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errMsgpackShort
+	}
+	return d.data[d.pos], nil
+}
+
+// length reads the length following the type byte stored in n bytes
+func (d *msgpackDecoder) length(n int) (int, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+// count reads the number of items of an array or a map, rejecting the counts the
+// remaining data can't hold before anything is allocated for them
+func (d *msgpackDecoder) count(n, itemSize int) (int, error) {
+	items, err := d.length(n)
+	if err != nil {
+		return 0, err
+	}
+	if items > (len(d.data)-d.pos)/itemSize {
+		return 0, errMsgpackShort
+	}
+	return items, nil
+}
+
+func (d *msgpackDecoder) arrayLen() (int, error) {
+	c, err := d.peek()
+	if err != nil {
+		return 0, err
+	}
+	d.pos++
+	switch {
+	case c >= 0x90 && c <= 0x9f:
+		return int(c & 0x0f), nil
+	case c == 0xdc:
+		return d.count(2, 1)
+	case c == 0xdd:
+		return d.count(4, 1)
+	}
+	return 0, fmt.Errorf("msgpack: expected array got 0x%02x", c)
+}
+
+func (d *msgpackDecoder) mapLen() (int, error) {
+	c, err := d.peek()
+	if err != nil {
+		return 0, err
+	}
+	d.pos++
+	switch {
+	case c >= 0x80 && c <= 0x8f:
+		return int(c & 0x0f), nil
+	case c == 0xde:
+		return d.count(2, 2)
+	case c == 0xdf:
+		return d.count(4, 2)
+	}
+	return 0, fmt.Errorf("msgpack: expected map got 0x%02x", c)
+}
+
+// skip moves over the next value
+func (d *msgpackDecoder) skip() error {
+	_, err := d.value()
+	return err
+}
+
+// value decodes the next value as nil, bool, int64, uint64, float64, string,
+// []byte, []interface{} or map[string]interface{} (map[interface{}]interface{}
+// when a key isn't a string)
+func (d *msgpackDecoder) value() (interface{}, error) {
+	c, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		d.pos++
+		return int64(c), nil
+	case c >= 0xe0:
+		d.pos++
+		return int64(int8(c)), nil
+	case c >= 0xa0 && c <= 0xbf, c == 0xd9, c == 0xda, c == 0xdb:
+		return d.str()
+	case c >= 0x90 && c <= 0x9f, c == 0xdc, c == 0xdd:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		n, err := d.arrayLen()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case c >= 0x80 && c <= 0x8f, c == 0xde, c == 0xdf:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		n, err := d.mapLen()
+		if err != nil {
+			return nil, err
+		}
+		keys, values := make([]interface{}, n), make([]interface{}, n)
+		strKeys := true
+		for i := 0; i < n; i++ {
+			if keys[i], err = d.value(); err != nil {
+				return nil, err
+			}
+			if values[i], err = d.value(); err != nil {
+				return nil, err
+			}
+			if _, ok := keys[i].(string); !ok {
+				strKeys = false
+			}
+		}
+		if strKeys {
+			m := make(map[string]interface{}, n)
+			for i, k := range keys {
+				m[k.(string)] = values[i]
+			}
+			return m, nil
+		}
+		m := make(map[interface{}]interface{}, n)
+		for i, k := range keys {
+			switch k.(type) {
+			case []interface{}, map[string]interface{}, map[interface{}]interface{}, []byte:
+				return nil, errors.New("msgpack: unsupported map key")
+			}
+			m[k] = values[i]
+		}
+		return m, nil
+	}
+	d.pos++
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return append([]byte{}, b...), err
+	case 0xca:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.next(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return uintFrom(b), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		b, err := d.next(1 << (c - 0xd0))
+		if err != nil {
+			return nil, err
+		}
+		u := uintFrom(b)
+		// sign extend
+		shift := 64 - 8*uint(len(b))
+		return int64(u<<shift) >> shift, nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type 0x%02x", c)
+}
+
+func uintFrom(b []byte) uint64 {
+	u := uint64(0)
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
+
+func (d *msgpackDecoder) str() (string, error) {
+	c, _ := d.peek()
+	d.pos++
+	n := int(c & 0x1f)
+	var err error
+	switch c {
+	case 0xd9:
+		n, err = d.length(1)
+	case 0xda:
+		n, err = d.length(2)
+	case 0xdb:
+		n, err = d.length(4)
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := d.next(n)
+	return string(b), err
+}
+
+// decode decodes the next value straight into v
+func (d *msgpackDecoder) decode(v reflect.Value) error {
+	c, err := d.peek()
+	if err != nil {
+		return err
+	}
+	if c == 0xc0 {
+		d.pos++
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) && isMsgpackStr(c) {
+		s, err := d.str()
+		if err != nil {
+			return err
+		}
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(t.Elem())
+		if err := d.decode(ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 || !(c >= 0x90 && c <= 0x9f || c == 0xdc || c == 0xdd) {
+			break
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		n, err := d.arrayLen()
+		if err != nil {
+			return err
+		}
+		if t.Kind() == reflect.Array {
+			if n != t.Len() {
+				return fmt.Errorf("msgpack: array of length %d is not decodable into %v", n, t)
+			}
+		} else {
+			v.Set(reflect.MakeSlice(t, n, n))
+		}
+		for i := 0; i < n; i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %v", i, err)
+			}
+		}
+		return nil
+	case reflect.Map:
+		if !(c >= 0x80 && c <= 0x8f || c == 0xde || c == 0xdf) {
+			break
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		n, err := d.mapLen()
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, n)
+		for i := 0; i < n; i++ {
+			k := reflect.New(t.Key()).Elem()
+			if err := d.decode(k); err != nil {
+				return fmt.Errorf("key: %v", err)
+			}
+			e := reflect.New(t.Elem()).Elem()
+			if err := d.decode(e); err != nil {
+				return fmt.Errorf("[%v]: %v", k, err)
+			}
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+		return nil
+	case reflect.Struct:
+		if !(c >= 0x80 && c <= 0x8f || c == 0xde || c == 0xdf) {
+			break
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		n, err := d.mapLen()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			key, err := d.value()
+			if err != nil {
+				return err
+			}
+			name, _ := key.(string)
+			field, found := fieldByName(t, name)
+			if !found {
+				if err := d.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decode(v.Field(field)); err != nil {
+				return fmt.Errorf("field %s: %v", t.Field(field).Name, err)
+			}
+		}
+		return nil
+	}
+	// scalars and the values decoded as is
+	x, err := d.value()
+	if err != nil {
+		return err
+	}
+	return setMsgpackValue(v, x)
+}
+
+func isMsgpackStr(c byte) bool {
+	return c >= 0xa0 && c <= 0xbf || c == 0xd9 || c == 0xda || c == 0xdb
+}
+
+// setMsgpackValue stores the decoded scalar x into v
+func setMsgpackValue(v reflect.Value, x interface{}) error {
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := x.(type) {
+		case int64:
+			i = n
+		case uint64:
+			if n > math.MaxInt64 {
+				return fmt.Errorf("msgpack: %d overflows %v", n, t)
+			}
+			i = int64(n)
+		default:
+			return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("msgpack: %d overflows %v", i, t)
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch n := x.(type) {
+		case uint64:
+			u = n
+		case int64:
+			if n < 0 {
+				return fmt.Errorf("msgpack: %d overflows %v", n, t)
+			}
+			u = uint64(n)
+		default:
+			return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("msgpack: %d overflows %v", u, t)
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch n := x.(type) {
+		case float64:
+			v.SetFloat(n)
+		case int64:
+			v.SetFloat(float64(n))
+		case uint64:
+			v.SetFloat(float64(n))
+		default:
+			return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+		}
+		return nil
+	case reflect.String:
+		switch s := x.(type) {
+		case string:
+			v.SetString(s)
+		case []byte:
+			v.SetString(string(s))
+		default:
+			return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+		}
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			switch b := x.(type) {
+			case []byte:
+				v.SetBytes(b)
+			case string:
+				v.SetBytes([]byte(b))
+			default:
+				return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+			}
+			return nil
+		}
+	case reflect.Array:
+		if b, ok := x.([]byte); ok && t.Elem().Kind() == reflect.Uint8 && len(b) == t.Len() {
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+	}
+	xv := reflect.ValueOf(x)
+	if xv.Type().AssignableTo(t) {
+		v.Set(xv)
+		return nil
+	}
+	return fmt.Errorf("msgpack: %T is not decodable into %v", x, t)
+}
@@ -0,0 +1,154 @@
+package funcutil
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Vertex struct {
+	X, Y int
+	Tag  string `json:"tag,omitempty"`
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	at := time.Date(2019, 5, 3, 10, 0, 0, 0, time.UTC)
+	tests := []interface{}{
+		true,
+		int64(-5),
+		int64(-300),
+		int64(-70000),
+		int64(1) << 40,
+		uint8(200),
+		uint64(1) << 63,
+		1.5,
+		"hello",
+		string(bytes.Repeat([]byte("a"), 300)),
+		[]byte{1, 2, 3},
+		[]int{1, 2, 3},
+		[2]string{"a", "b"},
+		map[string]int{"a": 1},
+		Vertex{X: 1, Y: -2, Tag: "p"},
+		&Vertex{X: 3},
+		at,
+	}
+	for _, v := range tests {
+		data, err := MarshalMsgpack(v)
+		if err != nil {
+			t.Errorf("%T: %v", v, err)
+			continue
+		}
+		out := reflect.New(reflect.TypeOf(v))
+		if err := UnmarshalMsgpack(data, out.Interface()); err != nil {
+			t.Errorf("%T: %v", v, err)
+			continue
+		}
+		if !reflect.DeepEqual(out.Elem().Interface(), v) {
+			t.Errorf("Should be %v got %v", v, out.Elem().Interface())
+		}
+	}
+	var small int8
+	data, _ := MarshalMsgpack(300)
+	if err := UnmarshalMsgpack(data, &small); err == nil {
+		t.Error("should failed due to overflow")
+	}
+	var any interface{}
+	data, _ = MarshalMsgpack(map[string]interface{}{"a": []interface{}{1, "b", nil}})
+	if err := UnmarshalMsgpack(data, &any); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{"a": []interface{}{int64(1), "b", nil}}
+	if !reflect.DeepEqual(any, expect) {
+		t.Errorf("Should be %v got %v", expect, any)
+	}
+}
+
+func TestCallMsgpack(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	payload, _ := MarshalMsgpack([]interface{}{[]float64{1, 2.5}, 2})
+	out, err := f.CallMsgpack("calculator.Sum", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rets []interface{}
+	if err := UnmarshalMsgpack(out, &rets); err != nil {
+		t.Fatal(err)
+	}
+	if len(rets) != 2 || rets[0] != 7.0 || rets[1] != nil {
+		t.Errorf("Unexpected results %v", rets)
+	}
+	payload, _ = MarshalMsgpack([]interface{}{map[string]interface{}{"a": 1}})
+	out, _ = f.CallMsgpack("calculator.Describe", payload)
+	UnmarshalMsgpack(out, &rets)
+	if rets[0] != "map[string]interface {}" {
+		t.Errorf("Should be map[string]interface {} got %v", rets[0])
+	}
+	payload, _ = MarshalMsgpack([]interface{}{[]float64{}, 1})
+	if _, err := f.CallMsgpack("calculator.Sum", payload); err == nil || err.Error() != "no values" {
+		t.Errorf("Should fail with no values got %v", err)
+	}
+	if _, err := f.CallMsgpack("calculator.Add", []byte{0x91}); err == nil {
+		t.Error("should failed due to truncated payload")
+	}
+	if c, _ := LookupCodec("application/msgpack"); c != MsgpackCodec {
+		t.Error("msgpack codec should be registered")
+	}
+}
+
+func TestMsgpackOversizedLength(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	payloads := [][]byte{
+		// an array claiming 2^31 items
+		{0x92, 0xdd, 0x7f, 0xff, 0xff, 0xff},
+		{0xdd, 0xff, 0xff, 0xff, 0xff},
+		// a map claiming 65535 pairs
+		{0x91, 0xde, 0xff, 0xff, 0x01},
+	}
+	for _, payload := range payloads {
+		if _, err := f.CallMsgpack("calculator.Add", payload); err == nil {
+			t.Errorf("% x: should failed due to truncated payload", payload)
+		}
+		var v []int
+		if err := UnmarshalMsgpack(payload, &v); err == nil {
+			t.Errorf("% x: should failed due to truncated payload", payload)
+		}
+	}
+}
+
+type nestedArray []nestedArray
+
+func TestMsgpackNestingDepth(t *testing.T) {
+	deep := append(bytes.Repeat([]byte{0x91}, 1<<20), 0xc0)
+	if _, err := MsgpackCodec.UnmarshalParams(deep); err == nil {
+		t.Error("should failed due to nesting depth")
+	}
+	var v nestedArray
+	if err := UnmarshalMsgpack(deep, &v); err == nil {
+		t.Error("should failed due to nesting depth")
+	}
+	deepMap := append(bytes.Repeat([]byte{0x81, 0xa1, 0x61}, 1<<20), 0xc0)
+	if _, err := MsgpackCodec.UnmarshalParams(append([]byte{0x91}, deepMap...)); err == nil {
+		t.Error("should failed due to nesting depth")
+	}
+	shallow := append(bytes.Repeat([]byte{0x91}, 100), 0xc0)
+	if _, err := MsgpackCodec.UnmarshalParams(shallow); err != nil {
+		t.Error(err)
+	}
+	if err := UnmarshalMsgpack(shallow, &v); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzUnmarshalMsgpack(f *testing.F) {
+	f.Add([]byte{0x92, 0xdd, 0x7f, 0xff, 0xff, 0xff})
+	f.Add([]byte{0x81, 0xa1, 0x61, 0x93, 0x01, 0xa1, 0x62, 0xc0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v interface{}
+		UnmarshalMsgpack(data, &v)
+		var m map[string][]int
+		UnmarshalMsgpack(data, &m)
+	})
+}
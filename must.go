@@ -0,0 +1,34 @@
+package funcutil
+
+import (
+	"context"
+	"fmt"
+)
+
+// MustCall is like Call but panics when the method can't be called
+func (f *FuncUtil) MustCall(methodName string, params ...interface{}) []interface{} {
+	rets, err := f.Call(methodName, params...)
+	if err != nil {
+		panic(fmt.Sprintf("funcutil: %s: %v", methodName, err))
+	}
+	return rets
+}
+
+// Call1 invokes a method returning exactly one value and returns that value,
+// it fails with ErrResultsMismatch for the other methods
+//
+//	info, err := f.Call1("service.Info")
+func (f *FuncUtil) Call1(methodName string, params ...interface{}) (interface{}, error) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return nil, opts.mapError(methodName, err)
+	}
+	if len(ci.retTypes) != 1 {
+		return nil, opts.mapError(methodName, ErrResultsMismatch)
+	}
+	rets, err := opts.call(context.Background(), ci, params)
+	if err != nil {
+		return nil, err
+	}
+	return rets[0], nil
+}
@@ -0,0 +1,34 @@
+package funcutil
+
+import "testing"
+
+func TestMustCall(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.MustCall("service.Run")
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("should panic due to unknown method")
+		}
+	}()
+	f.MustCall("service.NotExists")
+}
+
+func TestCall1(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.MustCall("service.Run")
+	info, err := f.Call1("service.Info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != "Running: true" {
+		t.Errorf("Should be Running: true got %v", info)
+	}
+	if _, err := f.Call1("service.Run"); err != ErrResultsMismatch {
+		t.Error("should failed due to no results")
+	}
+	if _, err := f.Call1("service.NotExists"); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown method")
+	}
+}
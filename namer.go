@@ -0,0 +1,15 @@
+package funcutil
+
+// namerMethod is the name of the MethodNamer method
+const namerMethod = "FuncutilName"
+
+// MethodNamer is implemented by the structs naming their registered methods.
+// FuncutilName returns the name exposing the method in place of <struct>.<method>,
+// the namespace and the version are still applied, or "" to hide the method.
+//
+//	func (s *service) FuncutilName(method string) string {
+//		return "svc." + strings.ToLower(method)
+//	}
+type MethodNamer interface {
+	FuncutilName(method string) string
+}
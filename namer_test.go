@@ -0,0 +1,47 @@
+package funcutil
+
+import (
+	"strings"
+	"testing"
+)
+
+type daemon struct {
+	stopped bool
+}
+
+func (d *daemon) Stop() {
+	d.stopped = true
+}
+
+func (d *daemon) Status() string {
+	return "running"
+}
+
+func (d *daemon) Internal() {}
+
+func (d *daemon) FuncutilName(method string) string {
+	if method == "Internal" {
+		return ""
+	}
+	return "sys.daemon." + strings.ToLower(method)
+}
+
+func TestMethodNamer(t *testing.T) {
+	f := New("com.example")
+	d := &daemon{}
+	f.Register(d)
+	if f.Len() != 2 || !f.Has("com.example.sys.daemon.stop") || !f.Has("com.example.sys.daemon.status") {
+		t.Errorf("Unexpected names %v", f.Dump())
+	}
+	if _, err := f.Call("com.example.sys.daemon.stop"); err != nil || !d.stopped {
+		t.Errorf("daemon should be stopped %v", err)
+	}
+	if mi, _ := f.Info("com.example.sys.daemon.status"); mi.Receiver != "daemon" || mi.Method != "Status" {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+	f = New()
+	f.RegisterVersion("2", d)
+	if !f.Has("sys.daemon.stop@2") {
+		t.Errorf("Unexpected names %v", f.Dump())
+	}
+}
@@ -0,0 +1,176 @@
+// Package natsrpc exposes a funcutil registry as NATS services.
+//
+// Every registered method is served on the subject <prefix>.<method name>, e.g.
+// rpc.billing.Invoice.Create, within a queue group so the requests are load balanced
+// between the servers. The request data is the array of the params encoded by the
+// server codec, the response sent to the reply subject is encoded the same way:
+//
+//	{"results": [42, null], "error": ""}
+//
+// The package doesn't depend on a specific NATS library, Conn is easily implemented
+// over github.com/nats-io/nats.go.
+package natsrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Msg is a NATS message
+type Msg struct {
+	Subject string
+	Reply   string
+	// Header holds the Authorization credential, see funcutil.FuncUtil.Authenticate
+	Header map[string][]string
+	Data   []byte
+}
+
+// Subscription is a NATS subscription
+type Subscription interface {
+	// Drain stops the subscription after the pending messages are processed
+	Drain() error
+}
+
+// Conn is the subset of a NATS connection used by the server
+type Conn interface {
+	QueueSubscribe(subject, queue string, handler func(*Msg)) (Subscription, error)
+	Publish(subject string, data []byte) error
+}
+
+// Server maps the NATS requests to the registry calls
+type Server struct {
+	sync.Mutex
+	f      *funcutil.FuncUtil
+	prefix string
+	queue  string
+	codec  funcutil.Codec
+	subs   []Subscription
+	// running counts the calls in progress, guarded by the lock of idle. Unlike a
+	// WaitGroup it may grow while Drain waits, the drained messages arrive meanwhile.
+	running int
+	idle    *sync.Cond
+}
+
+// NewServer creates a server of the methods under the subject prefix (may be empty),
+// joining the queue group. The requests are JSON encoded unless SetCodec is used.
+func NewServer(f *funcutil.FuncUtil, prefix, queue string) *Server {
+	return &Server{
+		f:      f,
+		prefix: prefix,
+		queue:  queue,
+		codec:  funcutil.JSONCodec,
+		idle:   sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// SetCodec sets the encoding of the requests and responses, it applies to the next Serve
+func (s *Server) SetCodec(c funcutil.Codec) {
+	s.Lock()
+	defer s.Unlock()
+	s.codec = c
+}
+
+// Subject returns the subject of the method
+func (s *Server) Subject(methodName string) string {
+	if s.prefix == "" {
+		return methodName
+	}
+	return s.prefix + "." + methodName
+}
+
+// Serve subscribes to the subjects of the methods registered so far
+func (s *Server) Serve(c Conn) error {
+	s.Lock()
+	defer s.Unlock()
+	codec := s.codec
+	for _, mi := range s.f.Methods() {
+		method := mi.Name
+		sub, err := c.QueueSubscribe(s.Subject(method), s.queue, func(m *Msg) {
+			s.begin()
+			defer s.end()
+			s.handle(c, codec, method, m)
+		})
+		if err != nil {
+			return err
+		}
+		s.subs = append(s.subs, sub)
+	}
+	return nil
+}
+
+func (s *Server) begin() {
+	s.idle.L.Lock()
+	s.running++
+	s.idle.L.Unlock()
+}
+
+func (s *Server) end() {
+	s.idle.L.Lock()
+	s.running--
+	if s.running == 0 {
+		s.idle.Broadcast()
+	}
+	s.idle.L.Unlock()
+}
+
+func (s *Server) handle(c Conn, codec funcutil.Codec, method string, m *Msg) {
+	resp := map[string]interface{}{
+		"results": []interface{}{},
+		"error":   "",
+	}
+	credential := ""
+	if values := m.Header["Authorization"]; len(values) > 0 {
+		credential = values[0]
+	}
+	ctx, err := s.f.Authenticate(context.Background(), credential)
+	var params []interface{}
+	if err == nil {
+		params, err = codec.UnmarshalParams(m.Data)
+	}
+	if err == nil {
+		var rets []interface{}
+		if rets, err = s.f.CallContext(ctx, method, params...); err == nil {
+			resp["results"] = funcutil.ResultValues(rets)
+			for _, ret := range rets {
+				if e, ok := ret.(error); ok {
+					err = e
+				}
+			}
+		}
+	}
+	if err != nil {
+		resp["error"] = err.Error()
+	}
+	if m.Reply == "" {
+		return
+	}
+	out, err := codec.Marshal(resp)
+	if err != nil {
+		return
+	}
+	c.Publish(m.Reply, out)
+}
+
+// Drain drains the subscriptions and waits for the running calls to finish,
+// it is used for a graceful shutdown
+func (s *Server) Drain() error {
+	s.Lock()
+	subs := s.subs
+	s.subs = nil
+	s.Unlock()
+
+	var firstErr error
+	for _, sub := range subs {
+		if err := sub.Drain(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.idle.L.Lock()
+	for s.running > 0 {
+		s.idle.Wait()
+	}
+	s.idle.L.Unlock()
+	return firstErr
+}
@@ -0,0 +1,148 @@
+package natsrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type counter struct {
+	n int
+}
+
+func (c *counter) Add(delta int) (int, error) {
+	if delta < 0 {
+		return c.n, errors.New("negative delta")
+	}
+	c.n += delta
+	return c.n, nil
+}
+
+type subscription struct {
+	conn    *conn
+	subject string
+}
+
+func (s *subscription) Drain() error {
+	delete(s.conn.subs, s.subject)
+	return nil
+}
+
+// conn is an in memory Conn with a single member per queue group
+type conn struct {
+	subs      map[string]func(*Msg)
+	queues    map[string]string
+	published map[string][]byte
+}
+
+func (c *conn) QueueSubscribe(subject, queue string, handler func(*Msg)) (Subscription, error) {
+	c.subs[subject] = handler
+	c.queues[subject] = queue
+	return &subscription{c, subject}, nil
+}
+
+func (c *conn) Publish(subject string, data []byte) error {
+	c.published[subject] = data
+	return nil
+}
+
+func (c *conn) request(subject, data string) string {
+	handler, exists := c.subs[subject]
+	if !exists {
+		return ""
+	}
+	handler(&Msg{Subject: subject, Reply: "_INBOX.1", Data: []byte(data)})
+	return string(c.published["_INBOX.1"])
+}
+
+func TestServer(t *testing.T) {
+	f := funcutil.New()
+	f.Namespace("math").Register(&counter{})
+	c := &conn{subs: map[string]func(*Msg){}, queues: map[string]string{}, published: map[string][]byte{}}
+	s := NewServer(f, "rpc", "workers")
+	if err := s.Serve(c); err != nil {
+		t.Fatal(err)
+	}
+	if c.queues["rpc.math.counter.Add"] != "workers" {
+		t.Errorf("Should be workers got %s", c.queues["rpc.math.counter.Add"])
+	}
+	if out := c.request("rpc.math.counter.Add", `[2]`); out != `{"error":"","results":[2,null]}` {
+		t.Errorf("Unexpected reply %s", out)
+	}
+	if out := c.request("rpc.math.counter.Add", `[-1]`); out != `{"error":"negative delta","results":[2,"negative delta"]}` {
+		t.Errorf("Unexpected reply %s", out)
+	}
+	if out := c.request("rpc.math.counter.Add", `["a"]`); out != `{"error":"arguments: parameter 0: json: cannot unmarshal string into Go value of type int","results":[]}` {
+		t.Errorf("Unexpected reply %s", out)
+	}
+	if err := s.Drain(); err != nil {
+		t.Error(err)
+	}
+	if len(c.subs) != 0 {
+		t.Errorf("Should be 0 got %d", len(c.subs))
+	}
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&counter{})
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, credential string) (funcutil.Identity, error) {
+		if credential != "secret" {
+			return funcutil.Identity{}, funcutil.ErrUnauthenticated
+		}
+		return funcutil.Identity{Name: "alice"}, nil
+	}))
+	c := &conn{subs: map[string]func(*Msg){}, queues: map[string]string{}, published: map[string][]byte{}}
+	NewServer(f, "", "").Serve(c)
+	c.subs["counter.Add"](&Msg{Reply: "_INBOX.1", Data: []byte(`[1]`)})
+	if out := string(c.published["_INBOX.1"]); out != `{"error":"Unauthenticated","results":[]}` {
+		t.Errorf("Unexpected reply %s", out)
+	}
+	c.subs["counter.Add"](&Msg{Reply: "_INBOX.1", Header: map[string][]string{"Authorization": {"secret"}}, Data: []byte(`[1]`)})
+	if out := string(c.published["_INBOX.1"]); out != `{"error":"","results":[1,null]}` {
+		t.Errorf("Unexpected reply %s", out)
+	}
+}
+
+type gate struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (g *gate) Pass() {
+	g.entered <- struct{}{}
+	<-g.release
+}
+
+func TestDrainWaitsForCalls(t *testing.T) {
+	f := funcutil.New()
+	g := &gate{entered: make(chan struct{}), release: make(chan struct{})}
+	f.Register(g)
+	c := &conn{subs: map[string]func(*Msg){}, queues: map[string]string{}, published: map[string][]byte{}}
+	s := NewServer(f, "", "workers")
+	s.Serve(c)
+	handler := c.subs["gate.Pass"]
+	msg := func() *Msg {
+		return &Msg{Subject: "gate.Pass", Data: []byte(`[]`)}
+	}
+	go handler(msg())
+	<-g.entered
+	drained := make(chan struct{})
+	go func() {
+		s.Drain()
+		close(drained)
+	}()
+	// a drained message arrives while Drain waits
+	go handler(msg())
+	<-g.entered
+	g.release <- struct{}{}
+	select {
+	case <-drained:
+		t.Error("Should wait for the running calls")
+	default:
+	}
+	g.release <- struct{}{}
+	<-drained
+}
@@ -0,0 +1,129 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+type schemaBuilder struct {
+	components map[string]interface{}
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, exists := b.components[t.Name()]; !exists {
+			// reserve the name first to stop recursive types
+			b.components[t.Name()] = nil
+			b.components[t.Name()] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	// interfaces, funcs and channels accept anything
+	return map[string]interface{}{}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		props[name] = b.schema(sf.Type)
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// tuple returns the schema of positional values as a JSON array
+func (b *schemaBuilder) tuple(types []reflect.Type) map[string]interface{} {
+	items := []interface{}{}
+	for _, t := range types {
+		items = append(items, b.schema(t))
+	}
+	return map[string]interface{}{
+		"type":        "array",
+		"prefixItems": items,
+		"minItems":    len(items),
+		"maxItems":    len(items),
+	}
+}
+
+// OpenAPISpec generates an OpenAPI 3.1 document describing the registered methods.
+// Each method is exposed as POST /<method name> taking its positional parameters
+// as a JSON array and returning its results the same way.
+func (f *FuncUtil) OpenAPISpec(title, version string) ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	b := &schemaBuilder{components: map[string]interface{}{}}
+	paths := map[string]interface{}{}
+	for name, ci := range f.calls {
+		op := map[string]interface{}{
+			"operationId": name,
+			"summary":     f.signatureOf(name),
+			"description": ci.doc,
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.tuple(ci.argTypes)},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "method results",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": b.tuple(ci.retTypes)},
+					},
+				},
+			},
+		}
+		if ci.deprecated {
+			op["deprecated"] = true
+		}
+		paths["/"+name] = map[string]interface{}{"post": op}
+	}
+	doc := map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": b.components,
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,35 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &Arith{})
+	b, err := f.OpenAPISpec("service", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	paths := doc["paths"].(map[string]interface{})
+	if len(paths) != 7 {
+		t.Errorf("Should be 7 paths got %d", len(paths))
+	}
+	if _, exists := paths["/service.Stop"]; !exists {
+		t.Error("/service.Stop should exists")
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	args, exists := schemas["Args"].(map[string]interface{})
+	if !exists {
+		t.Fatal("Args schema should exists")
+	}
+	props := args["properties"].(map[string]interface{})
+	if props["A"].(map[string]interface{})["type"] != "integer" {
+		t.Error("Args.A should be integer")
+	}
+}
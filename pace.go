@@ -0,0 +1,163 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrThrottled = errors.New("Call throttled")
+	ErrCoalesced = errors.New("Call coalesced")
+)
+
+// ThrottlePolicy tells what happens to the calls made too early by Throttle
+type ThrottlePolicy int
+
+const (
+	// ThrottleDrop makes the early calls fail with ErrThrottled, the default
+	ThrottleDrop ThrottlePolicy = iota
+	// ThrottleTrailing coalesces the early calls into a single call with the latest
+	// params, made once the interval elapsed
+	ThrottleTrailing
+)
+
+// pacer debounces or throttles the calls of a method
+type pacer struct {
+	sync.Mutex
+	debounce bool
+	interval time.Duration
+	policy   ThrottlePolicy
+	timer    *time.Timer
+	// next is the time the throttled method may run again
+	next time.Time
+	// due is the time the debounced method runs unless called again
+	due time.Time
+	// the latest coalesced call
+	ctx    context.Context
+	params []interface{}
+}
+
+// pacedKey marks the context of the coalesced calls, they are not paced again
+type pacedKey struct{}
+
+// Debounce coalesces the rapid calls of the method, it runs once the calls stopped for
+// wait with the params of the latest call. The coalesced calls return no results and
+// ErrCoalesced, the results of the run are discarded and its failure is logged, see
+// WithLogger. Zero removes the debounce.
+//
+//	f.Debounce("ui.Refresh", 200*time.Millisecond)
+func (f *FuncUtil) Debounce(methodName string, wait time.Duration) error {
+	return f.setPacer(methodName, wait, &pacer{debounce: true, interval: wait})
+}
+
+// Throttle runs the method at most once per interval, the early calls are dropped or
+// coalesced by the policy like by Debounce, ThrottleDrop by default. Zero removes the
+// throttle.
+//
+//	f.Throttle("sensor.Report", time.Second, funcutil.ThrottleTrailing)
+func (f *FuncUtil) Throttle(methodName string, interval time.Duration, policy ...ThrottlePolicy) error {
+	p := &pacer{interval: interval}
+	if len(policy) > 0 {
+		p.policy = policy[0]
+	}
+	return f.setPacer(methodName, interval, p)
+}
+
+func (f *FuncUtil) setPacer(methodName string, interval time.Duration, p *pacer) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.pacer = nil
+	if interval > 0 {
+		ci.pacer = p
+	}
+	return f.store(ci)
+}
+
+// clone returns a pacer with the same settings and no pending call
+func (p *pacer) clone() *pacer {
+	if p == nil {
+		return nil
+	}
+	return &pacer{debounce: p.debounce, interval: p.interval, policy: p.policy}
+}
+
+// pace reports whether the call runs now, otherwise it is coalesced with ErrCoalesced
+// or dropped with ErrThrottled
+func (o options) pace(ctx context.Context, ci callInfo, params []interface{}) (bool, error) {
+	if ci.pacer == nil || ctx.Value(pacedKey{}) != nil {
+		return true, nil
+	}
+	p := ci.pacer
+	p.Lock()
+	defer p.Unlock()
+	now := time.Now()
+	if !p.debounce && !now.Before(p.next) && p.timer == nil {
+		p.next = now.Add(p.interval)
+		return true, nil
+	}
+	if !p.debounce && p.policy == ThrottleDrop {
+		return false, ErrThrottled
+	}
+	// the coalesced call outlives the call context
+	p.ctx, p.params = context.WithoutCancel(ctx), params
+	p.due = now.Add(p.interval)
+	if p.timer == nil {
+		delay := p.interval
+		if !p.debounce {
+			delay = p.next.Sub(now)
+		}
+		p.timer = time.AfterFunc(delay, func() {
+			o.runPaced(ci, p)
+		})
+	}
+	return false, ErrCoalesced
+}
+
+// run takes the coalesced call once it is due, the debounced calls made after the
+// timer was set postpone it
+func (p *pacer) run() (context.Context, []interface{}, bool) {
+	p.Lock()
+	defer p.Unlock()
+	if now := time.Now(); p.debounce && now.Before(p.due) {
+		p.timer.Reset(p.due.Sub(now))
+		return nil, nil, false
+	}
+	p.timer = nil
+	p.next = time.Now().Add(p.interval)
+	return p.ctx, p.params, true
+}
+
+// runPaced makes the coalesced call of the pacer when it is due
+func (o options) runPaced(ci callInfo, p *pacer) {
+	if ctx, params, due := p.run(); due {
+		o.runCoalesced(ctx, ci, params)
+	}
+}
+
+// runCoalesced makes the coalesced call discarding its results, the failure and the
+// panic of the call are logged
+func (o options) runCoalesced(ctx context.Context, ci callInfo, params []interface{}) {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: panic: %v", ci.name, r)
+		}
+		if err != nil && o.logger != nil {
+			o.logger.Error("coalesced call failed", "method", ci.name, "error", err)
+		}
+	}()
+	var results []interface{}
+	if n := o.resultCount(ci); n > 0 {
+		results = make([]interface{}, n)
+	}
+	if err = o.invoke(context.WithValue(ctx, pacedKey{}, true), ci, params, results); err == nil {
+		err = resultError(results)
+	}
+}
@@ -0,0 +1,144 @@
+package funcutil
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type screen struct {
+	sync.Mutex
+	refreshed []string
+}
+
+func (s *screen) Refresh(view string) {
+	s.Lock()
+	defer s.Unlock()
+	s.refreshed = append(s.refreshed, view)
+}
+
+func (s *screen) views() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string{}, s.refreshed...)
+}
+
+func TestDebounce(t *testing.T) {
+	f := New()
+	s := &screen{}
+	f.Register(s)
+	if err := f.Debounce("screen.Refresh", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	for _, view := range []string{"a", "b", "c"} {
+		if rets, err := f.Call("screen.Refresh", view); err != ErrCoalesced || len(rets) != 0 {
+			t.Errorf("Should be coalesced got %v %v", rets, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(s.views()) != 0 {
+		t.Error("Should not refresh before the calls stopped")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if views := s.views(); len(views) != 1 || views[0] != "c" {
+		t.Errorf("Should refresh c once got %v", views)
+	}
+	f.Debounce("screen.Refresh", 0)
+	f.Call("screen.Refresh", "d")
+	if len(s.views()) != 2 {
+		t.Error("Should refresh at once")
+	}
+	if err := f.Debounce("screen.NotExists", time.Second); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	f := New()
+	s := &screen{}
+	f.Register(s)
+	if err := f.Throttle("screen.Refresh", 30*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Call("screen.Refresh", "a"); err != nil {
+		t.Error(err)
+	}
+	if _, err := f.Call("screen.Refresh", "b"); err != ErrThrottled {
+		t.Errorf("should failed due to throttling got %v", err)
+	}
+	time.Sleep(35 * time.Millisecond)
+	if _, err := f.Call("screen.Refresh", "c"); err != nil {
+		t.Error(err)
+	}
+	if views := s.views(); len(views) != 2 || views[1] != "c" {
+		t.Errorf("Should be a and c got %v", views)
+	}
+
+	f.Throttle("screen.Refresh", 30*time.Millisecond, ThrottleTrailing)
+	f.Call("screen.Refresh", "d")
+	f.Call("screen.Refresh", "e")
+	if _, err := f.Call("screen.Refresh", "f"); err != ErrCoalesced {
+		t.Errorf("Should be coalesced got %v", err)
+	}
+	time.Sleep(45 * time.Millisecond)
+	if views := s.views(); len(views) != 4 || views[2] != "d" || views[3] != "f" {
+		t.Errorf("Should be d and the trailing f got %v", views)
+	}
+}
+
+func (s *screen) Crash(view string) {
+	panic("cannot render " + view)
+}
+
+// logBuffer is written by the timers of the coalesced calls
+type logBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestDebounceReported(t *testing.T) {
+	f := New()
+	f.Register(&screen{})
+	buf := &logBuffer{}
+	f.WithLogger(slog.New(slog.NewTextHandler(buf, nil)))
+	f.Debounce("screen.Crash", 5*time.Millisecond)
+	f.Call("screen.Crash", "a")
+	time.Sleep(30 * time.Millisecond)
+	if out := buf.String(); !strings.Contains(out, "coalesced call failed") || !strings.Contains(out, "cannot render a") {
+		t.Errorf("Should log the panic got %s", out)
+	}
+}
+
+func TestDebounceOnce(t *testing.T) {
+	f := New()
+	s := &screen{}
+	f.Register(s)
+	f.Debounce("screen.Refresh", time.Millisecond)
+	for i := 0; i < 200; i++ {
+		f.Call("screen.Refresh", "a")
+		if i%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	refreshed := len(s.views())
+	time.Sleep(20 * time.Millisecond)
+	if len(s.views()) != refreshed {
+		t.Errorf("Should not run after the calls stopped got %d then %d", refreshed, len(s.views()))
+	}
+}
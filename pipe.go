@@ -0,0 +1,35 @@
+package funcutil
+
+import (
+	"fmt"
+)
+
+// Pipe composes the methods into a function calling them in order, the results of
+// each method are the params of the next one and converted like the params of Call.
+// A method returning a non nil error as its last result stops the pipeline with
+// that error, otherwise the error result isn't passed on.
+//
+//	words := f.Pipe("text.Read", "text.Split", "text.Count")
+//	rets, err := words("notes.txt")
+func (f *FuncUtil) Pipe(methodNames ...string) func(params ...interface{}) ([]interface{}, error) {
+	return func(params ...interface{}) ([]interface{}, error) {
+		for _, name := range methodNames {
+			mi, exists := f.Info(name)
+			if !exists {
+				return nil, fmt.Errorf("%s: %w", name, ErrMethodNotFound)
+			}
+			rets, err := f.Call(name, params...)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if n := len(mi.Results); n > 0 && mi.Results[n-1] == errorType {
+				if err, _ := rets[n-1].(error); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				rets = rets[:n-1]
+			}
+			params = rets
+		}
+		return params, nil
+	}
+}
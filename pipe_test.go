@@ -0,0 +1,45 @@
+package funcutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type text struct{}
+
+func (t *text) Split(s string) []string {
+	return strings.Fields(s)
+}
+
+func (t *text) Count(words []string) (int, error) {
+	if len(words) == 0 {
+		return 0, errors.New("no words")
+	}
+	return len(words), nil
+}
+
+func (t *text) Double(n float64) float64 {
+	return n * 2
+}
+
+func TestPipe(t *testing.T) {
+	f := New()
+	f.Register(&text{})
+	count := f.Pipe("text.Split", "text.Count", "text.Double")
+	if rets, err := count("a b c"); err != nil || len(rets) != 1 || rets[0] != 6.0 {
+		t.Errorf("Should be 6 got %v %v", rets, err)
+	}
+	if _, err := count(""); err == nil || err.Error() != "text.Count: no words" {
+		t.Errorf("should failed due to no words got %v", err)
+	}
+	if _, err := count(1, 2); !errors.Is(err, ErrParametersMismatch) {
+		t.Errorf("should failed due to parameters got %v", err)
+	}
+	if _, err := f.Pipe("text.Split", "text.NotExists")("a"); !errors.Is(err, ErrMethodNotFound) {
+		t.Error("method should not exists")
+	}
+	if rets, err := f.Pipe()("a", 1); err != nil || len(rets) != 2 {
+		t.Errorf("empty pipe should return the params got %v %v", rets, err)
+	}
+}
@@ -0,0 +1,64 @@
+package funcutil
+
+import (
+	"context"
+)
+
+// CallPlan is a precompiled call of a single registered method.
+// The method is resolved once, so Invoke skips the registry lookup, the calls go
+// through the same pipeline as Call and may run concurrently.
+// The call hooks and options are captured by Compile, changes made later don't apply to the plan.
+type CallPlan struct {
+	name string
+	ci   callInfo
+	opts options
+}
+
+// Compile resolves the method for repeated invocation
+func (f *FuncUtil) Compile(methodName string) (*CallPlan, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return nil, err
+	}
+	return &CallPlan{
+		name: methodName,
+		ci:   ci,
+		opts: f.opts.clone(),
+	}, nil
+}
+
+// Bind resolves the method once and returns its reusable handle, it is the same as Compile
+//
+//	stop, _ := f.Bind("service.Stop")
+//	for _, force := range requests {
+//		stop.Invoke(force)
+//	}
+func (f *FuncUtil) Bind(methodName string) (*CallPlan, error) {
+	return f.Compile(methodName)
+}
+
+// Name returns the method name of the plan
+func (p *CallPlan) Name() string {
+	return p.name
+}
+
+// Invoke calls the compiled method with the same semantics as FuncUtil.Call
+func (p *CallPlan) Invoke(params ...interface{}) ([]interface{}, error) {
+	return p.InvokeContext(context.Background(), params...)
+}
+
+// InvokeContext calls the compiled method with the same semantics as FuncUtil.CallContext
+func (p *CallPlan) InvokeContext(ctx context.Context, params ...interface{}) ([]interface{}, error) {
+	n := p.opts.resultCount(p.ci)
+	if n == 0 {
+		return nil, p.opts.invoke(ctx, p.ci, params, nil)
+	}
+	results := make([]interface{}, n)
+	if err := p.opts.invoke(ctx, p.ci, params, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,106 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &Arith{})
+	run, err := f.Compile("service.Run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run.Invoke(); err != nil {
+		t.Error(err)
+	}
+	running, _ := f.Compile("service.Running")
+	if rets, err := running.Invoke(); err != nil || !rets[0].(bool) {
+		t.Error("value should be set to true")
+	}
+	stop, _ := f.Compile("service.Stop")
+	if _, err := stop.Invoke(12); err == nil {
+		t.Error("should failed due to wrong argument type")
+	}
+	if _, err := stop.Invoke(); !errors.Is(err, ErrParametersMismatch) {
+		t.Error("should failed due to missing argument")
+	}
+	if _, err := f.Compile("service.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+func TestBind(t *testing.T) {
+	f := New()
+	f.Register(&Arith{})
+	multiply, err := f.Bind("Arith.Multiply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		reply := 0
+		if _, err := multiply.Invoke(&Args{i, 2}, &reply); err != nil || reply != i*2 {
+			t.Errorf("Should be %d got %d %v", i*2, reply, err)
+		}
+	}
+	if _, err := f.Bind("Arith.NotExists"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
+
+type countdown struct {
+	plan *CallPlan
+}
+
+func (c *countdown) Count(n int) int {
+	if n == 0 {
+		return 0
+	}
+	rets, _ := c.plan.Invoke(n - 1)
+	return rets[0].(int) + 1
+}
+
+func TestCompileReentrant(t *testing.T) {
+	c := &countdown{}
+	f := New()
+	f.Register(c)
+	c.plan, _ = f.Compile("countdown.Count")
+	if rets, err := c.plan.Invoke(3); err != nil || rets[0] != 3 {
+		t.Errorf("Should be 3 got %v %v", rets, err)
+	}
+}
+
+func TestCompileDispatcher(t *testing.T) {
+	c := &counter{}
+	f := New()
+	f.Register(c)
+	add, _ := f.Compile("counter.Add")
+	if rets, err := add.Invoke(2); err != nil || rets[0] != 2 {
+		t.Errorf("Should be 2 got %v %v", rets, err)
+	}
+	if c.dispatched != 1 {
+		t.Errorf("Should be dispatched 1 time got %d", c.dispatched)
+	}
+}
+
+func BenchmarkCall(b *testing.B) {
+	f := New()
+	f.Register(&Arith{})
+	reply := 0
+	args := &Args{7, 8}
+	for i := 0; i < b.N; i++ {
+		f.Call("Arith.Multiply", args, &reply)
+	}
+}
+
+func BenchmarkInvoke(b *testing.B) {
+	f := New()
+	f.Register(&Arith{})
+	reply := 0
+	args := &Args{7, 8}
+	plan, _ := f.Compile("Arith.Multiply")
+	for i := 0; i < b.N; i++ {
+		plan.Invoke(args, &reply)
+	}
+}
@@ -0,0 +1,49 @@
+package funcutil
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// servicesSymbol is the symbol looked up by LoadPlugin
+const servicesSymbol = "Services"
+
+// LoadPlugin opens the Go plugin at path and registers everything returned by its
+// exported Services function (or variable), so applications can be extended at runtime.
+//
+//	// in the plugin main package
+//	func Services() []interface{} {
+//		return []interface{}{&billing{}, &invoices{}}
+//	}
+func (f *FuncUtil) LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+	sym, err := p.Lookup(servicesSymbol)
+	if err != nil {
+		return err
+	}
+	return f.registerServices(sym)
+}
+
+// registerServices registers the services of a plugin symbol
+func (f *FuncUtil) registerServices(sym interface{}) error {
+	var services []interface{}
+	switch s := sym.(type) {
+	case func() []interface{}:
+		services = s()
+	case *[]interface{}:
+		services = *s
+	default:
+		return fmt.Errorf("plugin symbol %s must be func() []interface{} or []interface{}, got %T", servicesSymbol, sym)
+	}
+	f.Lock()
+	defer f.Unlock()
+	for _, s := range services {
+		if err := f.register(s, registration{ns: f.ns}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package funcutil
+
+import "testing"
+
+func TestLoadPlugin(t *testing.T) {
+	f := New()
+	if err := f.LoadPlugin("testdata/notexists.so"); err == nil {
+		t.Error("should failed due to missing plugin")
+	}
+	services := func() []interface{} {
+		return []interface{}{&service{}, &Monitor{}}
+	}
+	if err := f.registerServices(services); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Dump()) != 6 {
+		t.Errorf("Registered methods should be 6 got %d", len(f.Dump()))
+	}
+	vars := []interface{}{&counters{}}
+	if err := f.registerServices(&vars); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.registerServices(42); err == nil {
+		t.Error("should failed due to wrong symbol type")
+	}
+	if err := f.registerServices(&[]interface{}{service{}}); err != ErrNotStructPointer {
+		t.Error("should failed due to non pointer service")
+	}
+}
@@ -0,0 +1,81 @@
+package funcutil
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// PromotedPolicy tells how the methods promoted from the embedded types are registered
+type PromotedPolicy int
+
+const (
+	// PromotedKeep registers them under the name of the registered struct, the default
+	PromotedKeep PromotedPolicy = iota
+	// PromotedSkip doesn't register them
+	PromotedSkip
+	// PromotedEmbedded registers them under the name of the embedded type, the
+	// structs embedding the same type register the same names
+	PromotedEmbedded
+)
+
+// SetPromotedMethods sets how the structs registered later register the methods
+// promoted from their embedded types, MethodInfo.PromotedFrom tells their origin
+func (f *FuncUtil) SetPromotedMethods(policy PromotedPolicy) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.promoted = policy
+	return nil
+}
+
+// promotedFrom returns the embedded type declaring the method of the *struct type t,
+// reports false when t declares it
+func promotedFrom(t reflect.Type, name string) (reflect.Type, bool) {
+	if m, exists := t.MethodByName(name); !exists || !isWrapper(m.Func) {
+		return nil, false
+	}
+	// the value methods have wrappers in the pointer method set
+	if m, exists := t.Elem().MethodByName(name); exists && !isWrapper(m.Func) {
+		return nil, false
+	}
+	et := t.Elem()
+	for i := 0; i < et.NumField(); i++ {
+		sf := et.Field(i)
+		if !sf.Anonymous {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Interface {
+			if _, exists := ft.MethodByName(name); exists {
+				return ft, true
+			}
+			continue
+		}
+		if ft.Kind() != reflect.Ptr {
+			ft = reflect.PtrTo(ft)
+		}
+		if _, exists := ft.MethodByName(name); !exists {
+			continue
+		}
+		if ft.Elem().Kind() == reflect.Struct {
+			// the embedded type may have promoted it in turn
+			if inner, promoted := promotedFrom(ft, name); promoted {
+				return inner, true
+			}
+		}
+		return ft.Elem(), true
+	}
+	return nil, false
+}
+
+// isWrapper reports whether fn is generated by the compiler rather than declared
+func isWrapper(fn reflect.Value) bool {
+	rf := runtime.FuncForPC(fn.Pointer())
+	if rf == nil {
+		return false
+	}
+	file, _ := rf.FileLine(rf.Entry())
+	return file == "<autogenerated>"
+}
@@ -0,0 +1,76 @@
+package funcutil
+
+import (
+	"fmt"
+	"testing"
+)
+
+type Logger struct{}
+
+func (l *Logger) Log(msg string) string {
+	return "log: " + msg
+}
+
+func (l Logger) Level() string {
+	return "info"
+}
+
+type Base struct {
+	Logger
+}
+
+func (b *Base) Close() string {
+	return "base"
+}
+
+type Device struct {
+	*Base
+	fmt.Stringer
+}
+
+func (d *Device) Close() string {
+	return "device"
+}
+
+func (d Device) Open() string {
+	return "open"
+}
+
+func TestPromotedMethods(t *testing.T) {
+	f := New()
+	f.Register(&Device{Base: &Base{}})
+	for name, origin := range map[string]string{
+		"Device.Log":    "Logger",
+		"Device.Level":  "Logger",
+		"Device.String": "Stringer",
+		"Device.Close":  "",
+		"Device.Open":   "",
+	} {
+		if mi, exists := f.Info(name); !exists || mi.PromotedFrom != origin {
+			t.Errorf("%s should be promoted from %q got %+v", name, origin, mi)
+		}
+	}
+	if rets, err := f.Call("Device.Close"); err != nil || rets[0] != "device" {
+		t.Errorf("Should be device got %v %v", rets, err)
+	}
+
+	f = New()
+	f.SetPromotedMethods(PromotedSkip)
+	f.Register(&Device{Base: &Base{}})
+	if f.Len() != 2 || !f.Has("Device.Close") || !f.Has("Device.Open") {
+		t.Errorf("Should be Device.Close and Device.Open got %v", f.Dump())
+	}
+
+	f = New()
+	f.SetPromotedMethods(PromotedEmbedded)
+	f.Register(&Device{Base: &Base{}})
+	if rets, err := f.Call("Logger.Log", "hi"); err != nil || rets[0] != "log: hi" {
+		t.Errorf("Should be log: hi got %v %v", rets, err)
+	}
+	if mi, exists := f.Info("Logger.Level"); !exists || mi.Receiver != "Device" || mi.PromotedFrom != "Logger" {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+	if f.Has("Device.Log") || !f.Has("Device.Close") {
+		t.Error("only the promoted methods should be renamed")
+	}
+}
@@ -0,0 +1,123 @@
+package funcutil
+
+import (
+	"context"
+	"reflect"
+)
+
+// ProtoCodec marshals the protobuf messages, e.g. for google.golang.org/protobuf
+//
+//	f.SetProtoCodec(funcutil.ProtoCodec{
+//		IsMessage: func(t reflect.Type) bool {
+//			return t.Implements(reflect.TypeOf((*proto.Message)(nil)).Elem())
+//		},
+//		Marshal: func(m interface{}) ([]byte, error) {
+//			return proto.Marshal(m.(proto.Message))
+//		},
+//		Unmarshal: func(b []byte, m interface{}) error {
+//			return proto.Unmarshal(b, m.(proto.Message))
+//		},
+//	})
+type ProtoCodec struct {
+	// IsMessage tells whether the type is a message
+	IsMessage func(t reflect.Type) bool
+	Marshal   func(m interface{}) ([]byte, error)
+	// Unmarshal decodes into the message pointer m
+	Unmarshal func(b []byte, m interface{}) error
+}
+
+// protoMarshaler and protoUnmarshaler are implemented by the messages generated
+// by gogo/protobuf, which the default codec handles
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type protoUnmarshaler interface {
+	Unmarshal(b []byte) error
+}
+
+// protoAny is implemented by anypb.Any
+type protoAny interface {
+	GetTypeUrl() string
+	GetValue() []byte
+}
+
+var (
+	protoMarshalerType   = reflect.TypeOf((*protoMarshaler)(nil)).Elem()
+	protoUnmarshalerType = reflect.TypeOf((*protoUnmarshaler)(nil)).Elem()
+)
+
+// defaultProtoCodec handles the messages marshaling themselves
+var defaultProtoCodec = ProtoCodec{
+	IsMessage: func(t reflect.Type) bool {
+		return t.Kind() == reflect.Ptr && t.Implements(protoMarshalerType) && t.Implements(protoUnmarshalerType)
+	},
+	Marshal: func(m interface{}) ([]byte, error) {
+		return m.(protoMarshaler).Marshal()
+	},
+	Unmarshal: func(b []byte, m interface{}) error {
+		return m.(protoUnmarshaler).Unmarshal(b)
+	},
+}
+
+// SetProtoCodec sets how the protobuf messages are recognized and marshaled.
+// The message parameters accept the raw protobuf bytes or an anypb.Any wrapping them,
+// which are unmarshaled before the invocation, see CallProto for the results.
+// By default the messages are the pointers having Marshal and Unmarshal methods
+// like the gogo/protobuf messages.
+func (f *FuncUtil) SetProtoCodec(c ProtoCodec) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.proto = c
+	return nil
+}
+
+// unmarshalProto decodes the bytes supplied for the message types, reports false
+// when it doesn't apply
+func (o options) unmarshalProto(p interface{}, t reflect.Type) (reflect.Value, bool, error) {
+	c := o.proto
+	if c.IsMessage == nil || t.Kind() != reflect.Ptr || !c.IsMessage(t) {
+		return reflect.Value{}, false, nil
+	}
+	var b []byte
+	switch v := p.(type) {
+	case []byte:
+		b = v
+	case protoAny:
+		b = v.GetValue()
+	default:
+		return reflect.Value{}, false, nil
+	}
+	m := reflect.New(t.Elem())
+	return m, true, c.Unmarshal(b, m.Interface())
+}
+
+// CallProto invokes the method like Call and replaces the protobuf messages within
+// its results by their marshaled bytes, for the gRPC gateways
+func (f *FuncUtil) CallProto(methodName string, params ...interface{}) ([]interface{}, error) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return nil, opts.mapError(methodName, err)
+	}
+	rets, err := opts.call(context.Background(), ci, params)
+	if err != nil {
+		return nil, err
+	}
+	c := opts.proto
+	for i, ret := range rets {
+		if ret == nil || c.IsMessage == nil || !c.IsMessage(reflect.TypeOf(ret)) {
+			continue
+		}
+		if reflect.ValueOf(ret).IsNil() {
+			rets[i] = []byte(nil)
+			continue
+		}
+		if rets[i], err = c.Marshal(ret); err != nil {
+			return nil, err
+		}
+	}
+	return rets, nil
+}
@@ -0,0 +1,72 @@
+package funcutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Greeting marshals itself like the gogo/protobuf messages
+type Greeting struct {
+	Name string
+}
+
+func (g *Greeting) Marshal() ([]byte, error) {
+	return []byte("name:" + g.Name), nil
+}
+
+func (g *Greeting) Unmarshal(b []byte) error {
+	if !strings.HasPrefix(string(b), "name:") {
+		return errors.New("invalid greeting")
+	}
+	g.Name = strings.TrimPrefix(string(b), "name:")
+	return nil
+}
+
+// Any is like anypb.Any
+type Any struct {
+	TypeUrl string
+	Value   []byte
+}
+
+func (a *Any) GetTypeUrl() string {
+	return a.TypeUrl
+}
+
+func (a *Any) GetValue() []byte {
+	return a.Value
+}
+
+type greeterService struct {
+}
+
+func (g *greeterService) Greet(in *Greeting) (*Greeting, error) {
+	return &Greeting{Name: "Hello " + in.Name}, nil
+}
+
+func TestProtoParams(t *testing.T) {
+	f := New()
+	f.Register(&greeterService{})
+	for _, p := range []interface{}{[]byte("name:gopher"), &Any{"type.googleapis.com/Greeting", []byte("name:gopher")}} {
+		rets, err := f.CallProto("greeterService.Greet", p)
+		if err != nil {
+			t.Errorf("%T: %v", p, err)
+			continue
+		}
+		if b, ok := rets[0].([]byte); !ok || string(b) != "name:Hello gopher" {
+			t.Errorf("%T: Should be name:Hello gopher got %v", p, rets[0])
+		}
+	}
+	if _, err := f.Call("greeterService.Greet", []byte("gopher")); err == nil {
+		t.Error("should failed due to invalid message")
+	}
+	rets, err := f.Call("greeterService.Greet", &Greeting{Name: "gopher"})
+	if err != nil || rets[0].(*Greeting).Name != "Hello gopher" {
+		t.Errorf("Unexpected results %v %v", rets, err)
+	}
+
+	f.SetProtoCodec(ProtoCodec{})
+	if _, err := f.Call("greeterService.Greet", []byte("name:gopher")); err == nil {
+		t.Error("should failed due to disabled protobuf")
+	}
+}
@@ -0,0 +1,139 @@
+package funcutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CallRecord is a call written by RecordCalls as a JSON line. The errors returned by
+// the method are replaced by their message like in ResultValues.
+type CallRecord struct {
+	Time    time.Time         `json:"time"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+	Results []json.RawMessage `json:"results,omitempty"`
+	// Error is the error of the failed call
+	Error string `json:"error,omitempty"`
+}
+
+// Replayed is the outcome of a replayed call
+type Replayed struct {
+	Record  CallRecord
+	Results []interface{}
+	Err     error
+	// Diverged is set when the results or the error differ from the recorded ones
+	Diverged bool
+}
+
+type callRecorder struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// RecordCalls writes every call with its params and results to w, e.g. to reproduce
+// the issues of production against a local registry with Replay. The params are the
+// converted args without the context and the injected values, the struct fields tagged
+// `audit:"redact"` are redacted like in the audit log. Nil stops the recording.
+func (f *FuncUtil) RecordCalls(w io.Writer) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if w == nil {
+		f.opts.recorder = nil
+		return nil
+	}
+	f.opts.recorder = &callRecorder{w: w}
+	return nil
+}
+
+// record writes the call, err is read when the call returns
+func (c *callRecorder) record(name string, params []interface{}, start time.Time, results []interface{}, err *error) {
+	r := CallRecord{Time: start.UTC(), Method: name, Params: []json.RawMessage{}}
+	for _, p := range params {
+		if _, ok := p.(context.Context); ok {
+			continue
+		}
+		r.Params = append(r.Params, rawValue(redactValue(p)))
+	}
+	if *err != nil {
+		r.Error = (*err).Error()
+	} else {
+		for _, ret := range ResultValues(results) {
+			r.Results = append(r.Results, rawValue(ret))
+		}
+	}
+	line, _ := json.Marshal(r)
+	c.Lock()
+	defer c.Unlock()
+	c.w.Write(append(line, '\n'))
+}
+
+// rawValue encodes the value, or its type when JSON can't encode it
+func rawValue(v interface{}) json.RawMessage {
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%T", v))
+	}
+	return data
+}
+
+// Replay calls the methods recorded by RecordCalls in order, the params are decoded
+// into the parameter types like the JSON params of the transports
+//
+//	file, _ := os.Open("calls.jsonl")
+//	replayed, err := funcutil.Replay(f, file)
+func Replay(f *FuncUtil, r io.Reader) ([]Replayed, error) {
+	replayed := []Replayed{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, MaxEnvelopeSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record CallRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return replayed, err
+		}
+		params := make([]interface{}, len(record.Params))
+		for i, p := range record.Params {
+			params[i] = p
+		}
+		rp := Replayed{Record: record}
+		rp.Results, rp.Err = f.Call(record.Method, params...)
+		rp.Diverged = diverged(record, rp.Results, rp.Err)
+		replayed = append(replayed, rp)
+	}
+	return replayed, scanner.Err()
+}
+
+func diverged(record CallRecord, results []interface{}, err error) bool {
+	if err != nil || record.Error != "" {
+		return err == nil || err.Error() != record.Error
+	}
+	values := ResultValues(results)
+	if len(values) != len(record.Results) {
+		return true
+	}
+	for i, v := range values {
+		// compare the encodings regardless of the spacing
+		var recorded, got interface{}
+		json.Unmarshal(record.Results[i], &recorded)
+		json.Unmarshal(rawValue(v), &got)
+		if fmt.Sprint(recorded) != fmt.Sprint(got) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package funcutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecordCalls(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	buf := &bytes.Buffer{}
+	f.RecordCalls(buf)
+	f.Call("calculator.Add", 1, 2)
+	f.Call("calculator.Sum", []float64{1.5, 2}, 2.0)
+	f.Call("calculator.Add", 1)
+	f.RecordCalls(nil)
+	f.Call("calculator.Add", 3, 4)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Should be 3 records got %d", len(lines))
+	}
+	var record CallRecord
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Method != "calculator.Add" || string(record.Params[1]) != "2" || string(record.Results[0]) != "3" {
+		t.Errorf("Should be calculator.Add(1, 2) 3 got %s", lines[0])
+	}
+	json.Unmarshal([]byte(lines[2]), &record)
+	if record.Error == "" {
+		t.Errorf("Should record the error got %s", lines[2])
+	}
+
+	// replay against a fresh registry
+	g := New()
+	g.Register(&calculator{})
+	replayed, err := Replay(g, strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 3 {
+		t.Fatalf("Should be 3 replayed got %d", len(replayed))
+	}
+	for _, rp := range replayed {
+		if rp.Diverged {
+			t.Errorf("%s should not diverge got %v %v", rp.Record.Method, rp.Results, rp.Err)
+		}
+	}
+	if replayed[1].Results[0] != 7.0 {
+		t.Errorf("Should be 7 got %v", replayed[1].Results[0])
+	}
+	diverging := `{"method":"calculator.Add","params":[1,2],"results":[4]}`
+	if replayed, _ := Replay(g, strings.NewReader(diverging)); len(replayed) != 1 || !replayed[0].Diverged {
+		t.Errorf("Should diverge got %v", replayed)
+	}
+	if _, err := Replay(g, strings.NewReader("{")); err == nil {
+		t.Error("should failed due to invalid record")
+	}
+}
+
+func TestRecordCallsRedacted(t *testing.T) {
+	f := New()
+	f.Register(&accounts{})
+	buf := &bytes.Buffer{}
+	f.RecordCalls(buf)
+	f.CallJSON("accounts.Login", []byte(`[{"Name":"alice","Password":"s3cret"}]`))
+	f.Call("accounts.Import", map[string][]Credentials{"staff": {{"bob", "hunter2"}}})
+	if strings.Contains(buf.String(), "s3cret") || strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Password should be redacted %s", buf.String())
+	}
+	if strings.Count(buf.String(), Redacted) != 2 {
+		t.Errorf("Should be 2 redacted got %s", buf.String())
+	}
+}
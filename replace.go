@@ -0,0 +1,43 @@
+package funcutil
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	ErrNotRegistered = errors.New("Instance is not registered")
+	ErrTypeMismatch  = errors.New("Instances must be of the same type")
+)
+
+// Replace atomically rebinds all the methods registered with oldInstance to
+// newInstance, e.g. to reload the configuration of a service without re-registering.
+// Both must be of the same *struct type. Compiled call plans keep the old instance.
+func (f *FuncUtil) Replace(oldInstance, newInstance interface{}) error {
+	ov := reflect.ValueOf(oldInstance)
+	nv := reflect.ValueOf(newInstance)
+	if !ov.IsValid() || !nv.IsValid() || ov.Type() != nv.Type() {
+		return ErrTypeMismatch
+	}
+	if ov.Kind() != reflect.Ptr || ov.Elem().Kind() != reflect.Struct || nv.IsNil() {
+		return ErrNotStructPointer
+	}
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	replaced := false
+	for name, ci := range f.calls {
+		if !ci.v.IsValid() || ci.v.Type() != ov.Type() || ci.v.Pointer() != ov.Pointer() {
+			continue
+		}
+		ci.v = nv
+		f.calls[name] = ci
+		replaced = true
+	}
+	if !replaced {
+		return ErrNotRegistered
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package funcutil
+
+import "testing"
+
+func TestReplace(t *testing.T) {
+	f := New()
+	old := &service{}
+	f.Register(old, &Monitor{})
+	f.Call("service.Run")
+	if err := f.Replace(old, &service{}); err != nil {
+		t.Fatal(err)
+	}
+	if rets, _ := f.Call("service.Running"); rets[0].(bool) {
+		t.Error("new instance should not be running")
+	}
+	f.Call("service.Pause")
+	if !old.running {
+		t.Error("old instance should not be called")
+	}
+	if err := f.Replace(old, &service{}); err != ErrNotRegistered {
+		t.Error("old instance should not be registered anymore")
+	}
+	if err := f.Replace(&Monitor{}, &service{}); err != ErrTypeMismatch {
+		t.Error("should failed due to different types")
+	}
+	if err := f.Replace(service{}, service{}); err != ErrNotStructPointer {
+		t.Error("should failed due to non pointer instances")
+	}
+}
@@ -0,0 +1,35 @@
+package funcutil
+
+import (
+	"context"
+	"strconv"
+)
+
+// CallMap invokes the method and returns its results keyed by their names,
+// see SetResultNames. The unnamed results are keyed by their position as result<i>.
+//
+//	f.SetResultNames("users.Find", "user", "found")
+//	res, err := f.CallMap("users.Find", 42) // map[found:true user:0xc000010000]
+func (f *FuncUtil) CallMap(methodName string, params ...interface{}) (map[string]interface{}, error) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return nil, opts.mapError(methodName, err)
+	}
+	rets, err := opts.call(context.Background(), ci, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(rets))
+	for i, ret := range rets {
+		out[resultName(ci, i)] = ret
+	}
+	return out, nil
+}
+
+// resultName returns the name of the i-th result of the method
+func resultName(ci callInfo, i int) string {
+	if i < len(ci.retNames) && ci.retNames[i] != "" {
+		return ci.retNames[i]
+	}
+	return "result" + strconv.Itoa(i)
+}
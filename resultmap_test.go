@@ -0,0 +1,23 @@
+package funcutil
+
+import "testing"
+
+func TestCallMap(t *testing.T) {
+	f := New()
+	f.Register(&directory{})
+	res, err := f.CallMap("directory.Find", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["result0"] != "gopher" || res["result1"] != 7 || res["result2"] != true {
+		t.Errorf("Unexpected results %v", res)
+	}
+	f.SetResultNames("directory.Find", "name", "", "found")
+	res, _ = f.CallMap("directory.Find", 7)
+	if res["name"] != "gopher" || res["result1"] != 7 || res["found"] != true {
+		t.Errorf("Unexpected results %v", res)
+	}
+	if _, err := f.CallMap("directory.NotExists"); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown method")
+	}
+}
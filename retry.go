@@ -0,0 +1,89 @@
+package funcutil
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff returns the delay before the given retry, starting at 1
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits the same delay before every retry
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the delay from base for every retry up to max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Exponential waits 100ms before the first retry, doubling up to 10s
+var Exponential = ExponentialBackoff(100*time.Millisecond, 10*time.Second)
+
+// RetryPolicy tells how a method returning an error is invoked again
+type RetryPolicy struct {
+	// Max is the number of retries after the first attempt
+	Max int
+	// Backoff is the delay before each retry, no delay when nil
+	Backoff Backoff
+	// Retryable tells whether the error is transient, every error is when nil
+	Retryable func(err error) bool
+	// OnRetry observes the failed attempts which are retried
+	OnRetry func(name string, attempt int, err error)
+}
+
+// SetRetry makes the method invoked again when it returns a transient error,
+// according to the policy. A policy without retries removes the retry.
+// The arguments are converted once and reused by every attempt.
+//
+//	f.SetRetry("service.Flaky", funcutil.RetryPolicy{Max: 3, Backoff: funcutil.Exponential})
+func (f *FuncUtil) SetRetry(methodName string, policy RetryPolicy) error {
+	f.Lock()
+	defer f.Unlock()
+
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.retry = nil
+	if policy.Max > 0 {
+		ci.retry = &policy
+	}
+	return f.store(ci)
+}
+
+// retry tells whether the failed attempt must be retried, waiting for the backoff
+func (p *RetryPolicy) retry(ctx context.Context, name string, attempt int, err error) bool {
+	if p == nil || err == nil || attempt > p.Max {
+		return false
+	}
+	if p.Retryable != nil && !p.Retryable(err) {
+		return false
+	}
+	if p.OnRetry != nil {
+		p.OnRetry(name, attempt, err)
+	}
+	if p.Backoff == nil {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+type flaky struct {
+	failures int
+	calls    int
+}
+
+func (f *flaky) Fetch(id int) (int, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return 0, errTransient
+	}
+	return id, nil
+}
+
+func TestSetRetry(t *testing.T) {
+	f := New()
+	s := &flaky{failures: 2}
+	f.Register(s)
+	attempts := []int{}
+	err := f.SetRetry("flaky.Fetch", RetryPolicy{
+		Max:     3,
+		Backoff: ConstantBackoff(time.Millisecond),
+		OnRetry: func(name string, attempt int, err error) {
+			attempts = append(attempts, attempt)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rets, err := f.Call("flaky.Fetch", 7)
+	if err != nil || rets[0] != 7 || rets[1] != nil {
+		t.Errorf("Should be 7 got %v %v", rets, err)
+	}
+	if len(attempts) != 2 || s.calls != 3 {
+		t.Errorf("Should be 2 retries got %v", attempts)
+	}
+
+	s.calls, s.failures = 0, 10
+	if rets, _ := f.Call("flaky.Fetch", 7); rets[1] != errTransient || s.calls != 4 {
+		t.Errorf("Should be 4 calls got %d", s.calls)
+	}
+
+	s.calls = 0
+	f.SetRetry("flaky.Fetch", RetryPolicy{Max: 3, Retryable: func(err error) bool { return false }})
+	if f.Call("flaky.Fetch", 7); s.calls != 1 {
+		t.Errorf("Should be 1 call got %d", s.calls)
+	}
+	if err := f.SetRetry("flaky.NotExists", RetryPolicy{Max: 1}); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown method")
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(time.Second, 5*time.Second)
+	for retry, expect := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if retry == 0 {
+			continue
+		}
+		if d := b(retry); d != expect {
+			t.Errorf("Should be %v got %v", expect, d)
+		}
+	}
+}
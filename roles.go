@@ -0,0 +1,48 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrForbidden = errors.New("Forbidden")
+)
+
+// Require restricts the method to the callers having all the roles, the identity of
+// the caller is carried by the context of the call, see Authenticate. The calls
+// lacking any role fail with ErrMissingRoles. No roles lifts the restriction.
+//
+//	f.Require("service.Stop", "admin")
+func (f *FuncUtil) Require(methodName string, roles ...string) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	ci.roles = roles
+	return f.store(ci)
+}
+
+// checkRoles verifies the identity of ctx has the roles of the method
+func (mi *callInfo) checkRoles(ctx context.Context) error {
+	if len(mi.roles) == 0 {
+		return nil
+	}
+	id, _ := IdentityFromContext(ctx)
+	has := map[string]bool{}
+	for _, role := range id.Roles {
+		has[role] = true
+	}
+	missing := []string{}
+	for _, role := range mi.roles {
+		if !has[role] {
+			missing = append(missing, role)
+		}
+	}
+	if len(missing) > 0 {
+		return ErrMissingRoles{Method: mi.name, Roles: missing}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	if err := f.Require("service.Stop", "admin", "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if mi, _ := f.Info("service.Stop"); len(mi.Roles) != 2 {
+		t.Errorf("Should be 2 roles got %v", mi.Roles)
+	}
+	admin := ContextWithIdentity(context.Background(), Identity{Name: "alice", Roles: []string{"ops", "admin"}})
+	ops := ContextWithIdentity(context.Background(), Identity{Name: "bob", Roles: []string{"ops"}})
+	if _, err := f.CallContext(admin, "service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	_, err := f.CallContext(ops, "service.Stop", true)
+	if !errors.Is(err, ErrForbidden) || err.Error() != "Forbidden: service.Stop requires the roles admin" {
+		t.Errorf("should failed due to missing role got %v", err)
+	}
+	var missing ErrMissingRoles
+	if _, err := f.Call("service.Stop", true); !errors.As(err, &missing) || len(missing.Roles) != 2 {
+		t.Errorf("should failed due to missing roles got %v", err)
+	}
+	plan, _ := f.Compile("service.Stop")
+	if _, err := plan.InvokeContext(ops, true); !errors.Is(err, ErrForbidden) {
+		t.Errorf("should failed due to missing role got %v", err)
+	}
+	if _, err := f.Call("service.Running"); err != nil {
+		t.Error(err)
+	}
+	f.Require("service.Stop")
+	if _, err := f.Call("service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	if err := f.Require("service.NotExists", "admin"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,183 @@
+package funcutil
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"io"
+	"net/rpc"
+	"reflect"
+	"sync"
+)
+
+// isRPCStyle reports whether the method has the net/rpc shape:
+// Method(args T, reply *R) error
+func (mi *callInfo) isRPCStyle() bool {
+	args := mi.requestArgs()
+	return len(args) == 2 &&
+		args[1].Kind() == reflect.Ptr &&
+		len(mi.retTypes) == 1 &&
+		mi.retTypes[0] == errorType
+}
+
+// requestArgs returns the parameter types supplied by the requests, i.e. without
+// the context the calls receive
+func (mi *callInfo) requestArgs() []reflect.Type {
+	if len(mi.argTypes) > 0 && mi.argTypes[0] == contextType {
+		return mi.argTypes[1:]
+	}
+	return mi.argTypes
+}
+
+// ServeCodec serves the registered methods to net/rpc clients using the given codec,
+// so the registry can replace an existing net/rpc server without changing the clients.
+// Methods with net/rpc shape Method(args T, reply *R) error get their reply allocated,
+// other methods receive the request body as their single argument (if any) and reply
+// with their first non error result. Both may take a context.Context first, see
+// CallContext.
+// The net/rpc requests carry no credential, the calls are authenticated without any,
+// see ServeCodecCredential.
+// ServeCodec blocks until the client hangs up.
+func (f *FuncUtil) ServeCodec(codec rpc.ServerCodec) {
+	f.ServeCodecCredential(codec, "")
+}
+
+// ServeCodecCredential is ServeCodec with the calls authenticated by the credential of
+// the connection, e.g. the name of its TLS client certificate, see Authenticate
+func (f *FuncUtil) ServeCodecCredential(codec rpc.ServerCodec, credential string) {
+	ctx, authErr := f.Authenticate(context.Background(), credential)
+	sending := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for {
+		req := &rpc.Request{}
+		if err := codec.ReadRequestHeader(req); err != nil {
+			break
+		}
+		if authErr != nil {
+			codec.ReadRequestBody(nil)
+			f.sendResponse(sending, codec, req, nil, authErr)
+			continue
+		}
+		ci, opts, err := f.resolve(req.ServiceMethod)
+		if err != nil {
+			codec.ReadRequestBody(nil)
+			f.sendResponse(sending, codec, req, nil, opts.mapError(req.ServiceMethod, err))
+			continue
+		}
+		params, reply, err := f.readParams(codec, ci)
+		if err != nil {
+			f.sendResponse(sending, codec, req, nil, err)
+			continue
+		}
+		wg.Add(1)
+		go func(req *rpc.Request) {
+			defer wg.Done()
+			rets, err := opts.call(ctx, ci, params)
+			if err == nil && reply.IsValid() {
+				// rpc style method returns only error
+				if rets[0] != nil {
+					err = rets[0].(error)
+				}
+				rets = []interface{}{reply.Elem().Interface()}
+			}
+			f.sendResponse(sending, codec, req, rets, err)
+		}(req)
+	}
+	wg.Wait()
+	codec.Close()
+}
+
+// ServeConn runs ServeCodec on a single connection using the net/rpc gob codec
+func (f *FuncUtil) ServeConn(conn io.ReadWriteCloser) {
+	buf := bufio.NewWriter(conn)
+	f.ServeCodec(&gobServerCodec{
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(buf),
+		encBuf: buf,
+	})
+}
+
+func (f *FuncUtil) readParams(codec rpc.ServerCodec, ci callInfo) ([]interface{}, reflect.Value, error) {
+	var reply reflect.Value
+	args := ci.requestArgs()
+	if len(args) == 0 {
+		return nil, reply, codec.ReadRequestBody(nil)
+	}
+	arg := reflect.New(args[0])
+	if err := codec.ReadRequestBody(arg.Interface()); err != nil {
+		return nil, reply, err
+	}
+	params := []interface{}{arg.Elem().Interface()}
+	if ci.isRPCStyle() {
+		reply = reflect.New(args[1].Elem())
+		params = append(params, reply.Interface())
+	}
+	return params, reply, nil
+}
+
+func (f *FuncUtil) sendResponse(sending *sync.Mutex, codec rpc.ServerCodec, req *rpc.Request, rets []interface{}, err error) {
+	resp := &rpc.Response{
+		ServiceMethod: req.ServiceMethod,
+		Seq:           req.Seq,
+	}
+	var body interface{}
+	for _, ret := range rets {
+		if e, isErr := ret.(error); isErr && err == nil {
+			err = e
+		} else if body == nil {
+			body = ret
+		}
+	}
+	if err != nil {
+		resp.Error = err.Error()
+		body = nil
+	}
+	if body == nil {
+		body = struct{}{}
+	}
+	sending.Lock()
+	codec.WriteResponse(resp, body)
+	sending.Unlock()
+}
+
+// gobServerCodec mirrors the unexported net/rpc gob codec
+type gobServerCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+}
+
+func (c *gobServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	return c.dec.Decode(r)
+}
+
+func (c *gobServerCodec) ReadRequestBody(body interface{}) error {
+	return c.dec.Decode(body)
+}
+
+func (c *gobServerCodec) WriteResponse(r *rpc.Response, body interface{}) (err error) {
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *gobServerCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.rwc.Close()
+}
@@ -0,0 +1,141 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+)
+
+type Args struct {
+	A, B int
+}
+
+type Arith struct {
+}
+
+func (a *Arith) Multiply(args *Args, reply *int) error {
+	*reply = args.A * args.B
+	return nil
+}
+
+func (a *Arith) Divide(args *Args, reply *int) error {
+	if args.B == 0 {
+		return errors.New("divide by zero")
+	}
+	*reply = args.A / args.B
+	return nil
+}
+
+type Tenants struct {
+}
+
+func (t *Tenants) Scale(ctx context.Context, args *Args, reply *string) error {
+	id, _ := IdentityFromContext(ctx)
+	*reply = fmt.Sprintf("%s:%d", id.Name, args.A*args.B)
+	return nil
+}
+
+func (t *Tenants) Count(ctx context.Context, n int) int {
+	return n + 1
+}
+
+func TestServeConnContext(t *testing.T) {
+	f := New()
+	f.Register(&Tenants{})
+	f.SetAuthenticator(AuthenticatorFunc(func(ctx context.Context, credential string) (Identity, error) {
+		return Identity{Name: "anonymous"}, nil
+	}))
+	cli, srv := net.Pipe()
+	go f.ServeConn(srv)
+	client := rpc.NewClient(cli)
+	defer client.Close()
+
+	var reply string
+	if err := client.Call("Tenants.Scale", &Args{7, 8}, &reply); err != nil {
+		t.Error(err)
+	}
+	if reply != "anonymous:56" {
+		t.Errorf("Should be anonymous:56 got %s", reply)
+	}
+	var count int
+	if err := client.Call("Tenants.Count", 41, &count); err != nil {
+		t.Error(err)
+	}
+	if count != 42 {
+		t.Errorf("Should be 42 got %d", count)
+	}
+}
+
+func TestServeConn(t *testing.T) {
+	f := New()
+	f.Register(&Arith{})
+	cli, srv := net.Pipe()
+	go f.ServeConn(srv)
+	client := rpc.NewClient(cli)
+	defer client.Close()
+
+	var reply int
+	if err := client.Call("Arith.Multiply", &Args{7, 8}, &reply); err != nil {
+		t.Error(err)
+	}
+	if reply != 56 {
+		t.Errorf("Should be 56 got %d", reply)
+	}
+	if err := client.Call("Arith.Divide", &Args{7, 0}, &reply); err == nil || err.Error() != "divide by zero" {
+		t.Errorf("Should fail with divide by zero got %v", err)
+	}
+	if err := client.Call("Arith.NotExists", &Args{7, 0}, &reply); err == nil {
+		t.Error("method should not exists")
+	}
+}
+
+func TestServeCodec(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	cli, srv := net.Pipe()
+	go f.ServeCodec(jsonrpc.NewServerCodec(srv))
+	client := jsonrpc.NewClient(cli)
+	defer client.Close()
+
+	var info string
+	if err := client.Call("service.Run", nil, nil); err != nil {
+		t.Error(err)
+	}
+	if err := client.Call("service.Info", nil, &info); err != nil {
+		t.Error(err)
+	}
+	if info != "Running: true" {
+		t.Errorf("Should be Running: true got %s", info)
+	}
+}
+
+func TestServeCodecCredential(t *testing.T) {
+	f := New()
+	f.Register(&Arith{})
+	f.SetAuthenticator(AuthenticatorFunc(func(ctx context.Context, credential string) (Identity, error) {
+		if credential != "client.example.com" {
+			return Identity{}, ErrUnauthenticated
+		}
+		return Identity{Name: credential}, nil
+	}))
+	var reply int
+	cli, srv := net.Pipe()
+	go f.ServeConn(srv)
+	client := rpc.NewClient(cli)
+	if err := client.Call("Arith.Multiply", &Args{7, 8}, &reply); err == nil || err.Error() != ErrUnauthenticated.Error() {
+		t.Errorf("should failed due to missing credential got %v", err)
+	}
+	client.Close()
+
+	cli, srv = net.Pipe()
+	go f.ServeCodecCredential(jsonrpc.NewServerCodec(srv), "client.example.com")
+	client = jsonrpc.NewClient(cli)
+	defer client.Close()
+	if err := client.Call("Arith.Multiply", &Args{7, 8}, &reply); err != nil || reply != 56 {
+		t.Errorf("Should be 56 got %d %v", reply, err)
+	}
+}
@@ -0,0 +1,62 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrNotPointer = errors.New("Destination must be a non nil pointer")
+)
+
+// CallScan invokes the method and stores its results into the pointers of dest
+// like sql.Rows.Scan, the results are converted into the pointed types like the
+// parameters of Call. A nil dest discards its result.
+//
+//	var info string
+//	var err error
+//	f.CallScan("service.Info", []interface{}{&info, &err})
+func (f *FuncUtil) CallScan(methodName string, dest []interface{}, params ...interface{}) error {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return opts.mapError(methodName, err)
+	}
+	if len(dest) != len(ci.retTypes) {
+		return opts.mapError(methodName, ErrResultsMismatch)
+	}
+	rets, err := opts.call(context.Background(), ci, params)
+	if err != nil {
+		return err
+	}
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+		if err := opts.assign(d, rets[i]); err != nil {
+			return fmt.Errorf("results: [%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// assign converts v into the type pointed by dest and stores it
+func (o options) assign(dest interface{}, v interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return ErrNotPointer
+	}
+	et := dv.Type().Elem()
+	if v == nil {
+		dv.Elem().Set(reflect.Zero(et))
+		return nil
+	}
+	rv, err := o.argValue(v, et)
+	if err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "arguments: "))
+	}
+	dv.Elem().Set(rv)
+	return nil
+}
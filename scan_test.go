@@ -0,0 +1,33 @@
+package funcutil
+
+import "testing"
+
+func TestCallScan(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &service{})
+	var sum float64
+	var err error
+	if err := f.CallScan("calculator.Sum", []interface{}{&sum, &err}, []float64{1, 2}, 2.0); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 || err != nil {
+		t.Errorf("Should be 6 got %v %v", sum, err)
+	}
+	if f.CallScan("calculator.Sum", []interface{}{nil, &err}, []float64{}, 1.0); err == nil || err.Error() != "no values" {
+		t.Errorf("Should be no values got %v", err)
+	}
+	var total int32
+	if err := f.CallScan("calculator.Add", []interface{}{&total}, 40, 2); err != nil || total != 42 {
+		t.Errorf("Should be 42 got %v %v", total, err)
+	}
+	var names []string
+	if err := f.CallScan("calculator.Add", []interface{}{&names}, 40, 2); err == nil {
+		t.Error("should failed due to inconvertible result")
+	}
+	if err := f.CallScan("calculator.Add", []interface{}{total}, 40, 2); err == nil || err.Error() != "results: [0]: "+ErrNotPointer.Error() {
+		t.Errorf("Should fail with %v got %v", ErrNotPointer, err)
+	}
+	if err := f.CallScan("calculator.Add", []interface{}{}, 40, 2); err != ErrResultsMismatch {
+		t.Error("should failed due to results mismatch")
+	}
+}
@@ -0,0 +1,53 @@
+package funcutil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timer is the handle of a call scheduled by CallAt or CallAfter
+type Timer struct {
+	timer *time.Timer
+	once  sync.Once
+	done  chan Reply
+}
+
+// Stop cancels the call, it reports whether the call was cancelled before running
+func (t *Timer) Stop() bool {
+	if !t.timer.Stop() {
+		return false
+	}
+	t.once.Do(func() {
+		t.done <- Reply{Err: context.Canceled}
+	})
+	return true
+}
+
+// Done returns the channel receiving the reply of the call, or context.Canceled when
+// the call is stopped
+func (t *Timer) Done() <-chan Reply {
+	return t.done
+}
+
+// CallAfter calls the method on the executor once d elapsed, see SetExecutor.
+// The call is made with the params and options of the time it runs.
+//
+//	t := f.CallAfter(time.Minute, "session.Expire", id)
+//	...
+//	t.Stop()
+func (f *FuncUtil) CallAfter(d time.Duration, methodName string, params ...interface{}) *Timer {
+	t := &Timer{done: make(chan Reply, 1)}
+	t.timer = time.AfterFunc(d, func() {
+		t.once.Do(func() {
+			priority, _ := f.priorityOf(methodName)
+			f.submitCall(context.Background(), priority, methodName, params, t.done)
+		})
+	})
+	return t
+}
+
+// CallAt calls the method at the time like CallAfter, the past times call it at once
+func (f *FuncUtil) CallAt(at time.Time, methodName string, params ...interface{}) *Timer {
+	return f.CallAfter(time.Until(at), methodName, params...)
+}
@@ -0,0 +1,37 @@
+package funcutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallAfter(t *testing.T) {
+	f := New()
+	f.Register(&calculator{})
+	start := time.Now()
+	timer := f.CallAfter(20*time.Millisecond, "calculator.Add", 1, 2)
+	r := <-timer.Done()
+	if r.Err != nil || r.Results[0] != int64(3) {
+		t.Errorf("Should be 3 got %v %v", r.Results, r.Err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("Should be called after 20ms")
+	}
+	if timer.Stop() {
+		t.Error("Should not stop the call made")
+	}
+
+	timer = f.CallAfter(time.Hour, "calculator.Add", 1, 2)
+	if !timer.Stop() {
+		t.Error("Should stop the pending call")
+	}
+	if r := <-timer.Done(); r.Err != context.Canceled {
+		t.Errorf("should failed due to cancellation got %v", r.Err)
+	}
+
+	r = <-f.CallAt(time.Now().Add(-time.Second), "calculator.NotExists").Done()
+	if r.Err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,65 @@
+package funcutil
+
+// Scope is a view of a registry under a namespace, its methods apply the
+// namespace prefix to the registered and called names
+type Scope struct {
+	f  *FuncUtil
+	ns string
+}
+
+func joinNamespace(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	if child == "" {
+		return parent
+	}
+	return parent + "." + child
+}
+
+// Namespace returns the view of the registry under the namespace,
+// appended to the registry namespace if any
+//
+//	device := f.Namespace("device")
+//	device.Register(&Monitor{})
+//	device.Call("Monitor.Display") // same as f.Call("device.Monitor.Display")
+func (f *FuncUtil) Namespace(name string) *Scope {
+	return &Scope{f: f, ns: joinNamespace(f.ns, name)}
+}
+
+// Namespace returns the nested view under the namespace
+func (s *Scope) Namespace(name string) *Scope {
+	return &Scope{f: s.f, ns: joinNamespace(s.ns, name)}
+}
+
+// Name returns the full namespace of the view
+func (s *Scope) Name() string {
+	return s.ns
+}
+
+// Registry returns the underlying registry
+func (s *Scope) Registry() *FuncUtil {
+	return s.f
+}
+
+// Register registers the structs under the namespace, see FuncUtil.Register
+func (s *Scope) Register(vars ...interface{}) error {
+	s.f.Lock()
+	defer s.f.Unlock()
+	return s.f.registerAll(vars, s.ns)
+}
+
+// RegisterMap registers the functions under the namespace, see FuncUtil.RegisterMap
+func (s *Scope) RegisterMap(funcs map[string]interface{}) error {
+	return s.f.registerMap(funcs, s.ns)
+}
+
+// Call invokes the method named relative to the namespace
+func (s *Scope) Call(methodName string, params ...interface{}) ([]interface{}, error) {
+	return s.f.Call(joinNamespace(s.ns, methodName), params...)
+}
+
+// CallInto invokes the method named relative to the namespace, see FuncUtil.CallInto
+func (s *Scope) CallInto(methodName string, results []interface{}, params ...interface{}) error {
+	return s.f.CallInto(joinNamespace(s.ns, methodName), results, params...)
+}
@@ -0,0 +1,32 @@
+package funcutil
+
+import "testing"
+
+func TestNamespaceScope(t *testing.T) {
+	f := New("com.example")
+	device := f.Namespace("device")
+	device.Register(&Monitor{})
+	display := device.Namespace("display")
+	display.RegisterMap(map[string]interface{}{
+		"brightness": func() int { return 80 },
+	})
+	if display.Name() != "com.example.device.display" {
+		t.Errorf("Unexpected namespace %s", display.Name())
+	}
+	if rets, err := device.Call("Monitor.Display"); err != nil || rets[0] != "Display()" {
+		t.Errorf("Should be Display() got %v %v", rets, err)
+	}
+	if rets, err := f.Call("com.example.device.display.brightness"); err != nil || rets[0] != 80 {
+		t.Errorf("Should be 80 got %v %v", rets, err)
+	}
+	results := make([]interface{}, 1)
+	if err := display.CallInto("brightness", results); err != nil || results[0] != 80 {
+		t.Errorf("Should be 80 got %v %v", results, err)
+	}
+	if mi, _ := f.Info("com.example.device.Monitor.Display"); mi.Namespace != "com.example.device" {
+		t.Errorf("Unexpected namespace %s", mi.Namespace)
+	}
+	if _, err := display.Call("Monitor.Display"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
@@ -0,0 +1,163 @@
+package funcutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ScriptStep is a call of a script, see RunScript
+type ScriptStep struct {
+	Call string            `json:"call"`
+	Args []json.RawMessage `json:"args"`
+	// Save names the results stored as variables, empty names are skipped
+	Save []string `json:"save"`
+	// Expect are the expected results, compared by their JSON encoding
+	Expect []json.RawMessage `json:"expect"`
+	// ExpectError is the expected error message of the call or its error result
+	ExpectError string `json:"expect_error"`
+}
+
+// Script is a sequence of calls
+type Script struct {
+	Steps []ScriptStep `json:"steps"`
+}
+
+// RunScript executes the calls of the JSON script in order and returns the variables
+// saved by the steps. The args which are strings like "$name" are replaced by the
+// variable name, within arrays and objects as well, "$$" escapes a leading "$".
+// The first failed call or assertion stops the script. The YAML scripts are run
+// once converted to JSON, e.g. by sigs.k8s.io/yaml.YAMLToJSON.
+//
+//	{"steps": [
+//		{"call": "users.Create", "args": ["gopher"], "save": ["id"]},
+//		{"call": "users.Get", "args": ["$id"], "expect": [{"name": "gopher"}, null]}
+//	]}
+func (f *FuncUtil) RunScript(r io.Reader) (map[string]interface{}, error) {
+	s := Script{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("script: %v", err)
+	}
+	vars := map[string]interface{}{}
+	for i, step := range s.Steps {
+		if err := f.runStep(step, vars); err != nil {
+			return vars, fmt.Errorf("step %d (%s): %v", i+1, step.Call, err)
+		}
+	}
+	return vars, nil
+}
+
+func (f *FuncUtil) runStep(step ScriptStep, vars map[string]interface{}) error {
+	params := make([]interface{}, len(step.Args))
+	for i, arg := range step.Args {
+		p, err := scriptArg(arg, vars)
+		if err != nil {
+			return fmt.Errorf("args: [%d]: %v", i, err)
+		}
+		params[i] = p
+	}
+	rets, err := f.Call(step.Call, params...)
+	if err == nil {
+		err = resultError(rets)
+	}
+	switch {
+	case step.ExpectError != "":
+		if err == nil || err.Error() != step.ExpectError {
+			return fmt.Errorf("expected error %q got %v", step.ExpectError, err)
+		}
+	case err != nil && step.Expect == nil:
+		return err
+	}
+	if step.Expect != nil {
+		if len(step.Expect) != len(rets) {
+			return fmt.Errorf("expected %d results got %d", len(step.Expect), len(rets))
+		}
+		for i, ret := range ResultValues(rets) {
+			if err := expectJSON(step.Expect[i], ret); err != nil {
+				return fmt.Errorf("results: [%d]: %v", i, err)
+			}
+		}
+	}
+	for i, name := range step.Save {
+		if name != "" && i < len(rets) {
+			vars[name] = rets[i]
+		}
+	}
+	return nil
+}
+
+// scriptArg returns the param of the raw arg replacing the variable references
+func scriptArg(arg json.RawMessage, vars map[string]interface{}) (interface{}, error) {
+	if !strings.Contains(string(arg), `"$`) {
+		return arg, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(arg, &v); err != nil {
+		return nil, err
+	}
+	if s, ok := v.(string); ok && strings.HasPrefix(s, "$") && !strings.HasPrefix(s, "$$") {
+		// keep the type of the variable
+		return lookupVar(s, vars)
+	}
+	replaced, err := replaceVars(v, vars)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(replaced)
+	return json.RawMessage(b), err
+}
+
+func lookupVar(ref string, vars map[string]interface{}) (interface{}, error) {
+	v, exists := vars[ref[1:]]
+	if !exists {
+		return nil, fmt.Errorf("undefined variable %s", ref)
+	}
+	return v, nil
+}
+
+func replaceVars(v interface{}, vars map[string]interface{}) (interface{}, error) {
+	var err error
+	switch x := v.(type) {
+	case string:
+		if strings.HasPrefix(x, "$$") {
+			return x[1:], nil
+		}
+		if strings.HasPrefix(x, "$") {
+			return lookupVar(x, vars)
+		}
+	case []interface{}:
+		for i := range x {
+			if x[i], err = replaceVars(x[i], vars); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for k := range x {
+			if x[k], err = replaceVars(x[k], vars); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// expectJSON compares the JSON encoding of v with the expected JSON
+func expectJSON(expected json.RawMessage, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return err
+	}
+	json.Unmarshal(b, &got)
+	if !reflect.DeepEqual(want, got) {
+		return fmt.Errorf("expected %s got %s", expected, b)
+	}
+	return nil
+}
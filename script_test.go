@@ -0,0 +1,67 @@
+package funcutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type Member struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type userStore struct {
+	users []*Member
+}
+
+func (s *userStore) Create(name string) int {
+	s.users = append(s.users, &Member{ID: len(s.users) + 1, Name: name})
+	return len(s.users)
+}
+
+func (s *userStore) Get(id int) (*Member, error) {
+	if id < 1 || id > len(s.users) {
+		return nil, errors.New("user not found")
+	}
+	return s.users[id-1], nil
+}
+
+func (s *userStore) Rename(u Member) string {
+	return u.Name
+}
+
+func TestRunScript(t *testing.T) {
+	f := New()
+	f.Register(&userStore{})
+	vars, err := f.RunScript(strings.NewReader(`{"steps": [
+		{"call": "userStore.Create", "args": ["gopher"], "save": ["id"]},
+		{"call": "userStore.Get", "args": ["$id"], "expect": [{"id": 1, "name": "gopher"}, null], "save": ["user"]},
+		{"call": "userStore.Rename", "args": [{"id": "$id", "name": "$$gopher"}], "expect": ["$gopher"]},
+		{"call": "userStore.Get", "args": [7], "expect_error": "user not found"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["id"] != 1 || vars["user"].(*Member).Name != "gopher" {
+		t.Errorf("Unexpected variables %v", vars)
+	}
+
+	tests := []struct {
+		script string
+		err    string
+	}{
+		{`{"steps": [{"call": "userStore.Get", "args": [7]}]}`, "step 1 (userStore.Get): user not found"},
+		{`{"steps": [{"call": "userStore.Get", "args": ["$id"]}]}`, "step 1 (userStore.Get): args: [0]: undefined variable $id"},
+		{`{"steps": [{"call": "userStore.Create", "args": ["a"], "expect": [2]}]}`, "step 1 (userStore.Create): results: [0]: expected 2 got 1"},
+		{`{"steps": [{"call": "userStore.Create", "args": ["a"], "expect_error": "failed"}]}`, `step 1 (userStore.Create): expected error "failed" got <nil>`},
+		{`{"steps": [{"method": "userStore.Create"}]}`, `script: json: unknown field "method"`},
+	}
+	for _, test := range tests {
+		f := New()
+		f.Register(&userStore{})
+		if _, err := f.RunScript(strings.NewReader(test.script)); err == nil || err.Error() != test.err {
+			t.Errorf("Should fail with %s got %v", test.err, err)
+		}
+	}
+}
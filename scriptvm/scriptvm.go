@@ -0,0 +1,88 @@
+// Package scriptvm exposes a funcutil registry to embedded script engines.
+//
+// The methods become functions of nested objects following their names, so
+// service.SetHello is called from the script as service.SetHello("hi"). The script
+// values are converted into the parameter types by Call, a method returning a single
+// value returns it, several values are returned as an array and a non nil error
+// result is raised as an error of the script.
+//
+// The package doesn't depend on a specific engine, VM is implemented by
+// *goja.Runtime as is:
+//
+//	vm := goja.New()
+//	scriptvm.Bind(vm, f)
+//	vm.RunString(`service.SetHello("hi")`)
+//
+// Lua engines bind the functions of Objects through their Go bridges, e.g. gopher-luar.
+package scriptvm
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// VM sets the global variables of a script engine
+type VM interface {
+	Set(name string, value interface{}) error
+}
+
+// Func is the script function calling a method
+type Func func(args ...interface{}) (interface{}, error)
+
+// Objects returns the global objects holding the functions of the registered methods,
+// keyed by the first part of their names
+func Objects(f *funcutil.FuncUtil) map[string]interface{} {
+	root := map[string]interface{}{}
+	for _, mi := range f.Methods() {
+		parts := strings.Split(mi.Name, ".")
+		obj := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := obj[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				obj[part] = child
+			}
+			obj = child
+		}
+		obj[parts[len(parts)-1]] = method(f, mi)
+	}
+	return root
+}
+
+// Bind sets the objects of the registered methods as globals of the VM
+func Bind(vm VM, f *funcutil.FuncUtil) error {
+	for name, obj := range Objects(f) {
+		if err := vm.Set(name, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func method(f *funcutil.FuncUtil, mi funcutil.MethodInfo) Func {
+	// the trailing error is raised instead of returned
+	raise := len(mi.Results) > 0 && mi.Results[len(mi.Results)-1] == errorType
+	return func(args ...interface{}) (interface{}, error) {
+		rets, err := f.Call(mi.Name, args...)
+		if err != nil {
+			return nil, err
+		}
+		if raise {
+			if err, _ := rets[len(rets)-1].(error); err != nil {
+				return nil, err
+			}
+			rets = rets[:len(rets)-1]
+		}
+		switch len(rets) {
+		case 0:
+			return nil, nil
+		case 1:
+			return rets[0], nil
+		}
+		return rets, nil
+	}
+}
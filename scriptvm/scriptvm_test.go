@@ -0,0 +1,65 @@
+package scriptvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type service struct {
+	hello string
+}
+
+func (s *service) SetHello(m string) {
+	s.hello = m
+}
+
+func (s *service) Hello() string {
+	return s.hello
+}
+
+func (s *service) Split(n float64) (int, int, error) {
+	if n < 0 {
+		return 0, 0, errors.New("negative")
+	}
+	return int(n) / 2, int(n) % 2, nil
+}
+
+// vm stores the globals like a script engine
+type vm map[string]interface{}
+
+func (v vm) Set(name string, value interface{}) error {
+	v[name] = value
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	f := funcutil.New("app")
+	f.Register(&service{})
+	v := vm{}
+	if err := Bind(v, f); err != nil {
+		t.Fatal(err)
+	}
+	svc := v["app"].(map[string]interface{})["service"].(map[string]interface{})
+	if ret, err := svc["SetHello"].(Func)("hi"); ret != nil || err != nil {
+		t.Errorf("Unexpected result %v %v", ret, err)
+	}
+	if ret, _ := svc["Hello"].(Func)(); ret != "hi" {
+		t.Errorf("Should be hi got %v", ret)
+	}
+	// script numbers are float64 or int64
+	ret, err := svc["Split"].(Func)(int64(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rets := ret.([]interface{}); len(rets) != 2 || rets[0] != 3 || rets[1] != 1 {
+		t.Errorf("Unexpected results %v", rets)
+	}
+	if _, err := svc["Split"].(Func)(-1.0); err == nil || err.Error() != "negative" {
+		t.Errorf("Should fail with negative got %v", err)
+	}
+	if _, err := svc["Hello"].(Func)("extra"); !errors.Is(err, funcutil.ErrParametersMismatch) {
+		t.Errorf("Should fail with %v got %v", funcutil.ErrParametersMismatch, err)
+	}
+}
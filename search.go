@@ -0,0 +1,64 @@
+package funcutil
+
+import (
+	"sort"
+	"strings"
+)
+
+// Search returns the methods matching the query best first, ignoring the case.
+// The methods whose names contain the query rank first, then the ones whose
+// signatures contain it and last the ones whose names contain its characters in
+// order, e.g. "svcstp" finds "service.Stop".
+func (f *FuncUtil) Search(query string) []MethodInfo {
+	q := strings.ToLower(query)
+	type match struct {
+		mi    MethodInfo
+		score int
+	}
+	matches := []match{}
+	f.Range(func(name string, mi MethodInfo) bool {
+		if score := searchScore(q, strings.ToLower(name), strings.ToLower(mi.Signature)); score > 0 {
+			matches = append(matches, match{mi, score})
+		}
+		return true
+	})
+	// Range is sorted by name, keep that order for the same scores
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	methods := make([]MethodInfo, len(matches))
+	for i, m := range matches {
+		methods[i] = m.mi
+	}
+	return methods
+}
+
+// searchScore ranks the method for the query, zero when it doesn't match
+func searchScore(q, name, signature string) int {
+	const (
+		nameScore      = 3000
+		signatureScore = 2000
+		fuzzyScore     = 1000
+	)
+	if i := strings.Index(name, q); i >= 0 {
+		// prefer the earlier and the closer matches
+		return nameScore - i - (len(name) - len(q))
+	}
+	if strings.Contains(signature, q) {
+		return signatureScore - len(signature)
+	}
+	// the characters of the query must appear in order, the gaps lower the score
+	gaps, pos := 0, 0
+	for _, c := range q {
+		i := strings.IndexRune(name[pos:], c)
+		if i < 0 {
+			return 0
+		}
+		gaps += i
+		pos += i + len(string(c))
+	}
+	if fuzzyScore-gaps < 1 {
+		return 1
+	}
+	return fuzzyScore - gaps
+}
@@ -0,0 +1,33 @@
+package funcutil
+
+import (
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	f := New()
+	f.Register(&service{}, &calculator{}, &text{})
+	names := func(methods []MethodInfo) []string {
+		n := []string{}
+		for _, mi := range methods {
+			n = append(n, mi.Name)
+		}
+		return n
+	}
+	if n := names(f.Search("STOP")); len(n) != 1 || n[0] != "service.Stop" {
+		t.Errorf("Should be service.Stop got %v", n)
+	}
+	if n := names(f.Search("float64")); len(n) != 2 || n[0] != "text.Double" || n[1] != "calculator.Sum" {
+		t.Errorf("Should be text.Double, calculator.Sum got %v", n)
+	}
+	if n := names(f.Search("svcstp")); len(n) != 1 || n[0] != "service.Stop" {
+		t.Errorf("Should be service.Stop got %v", n)
+	}
+	// the name matches rank before the fuzzy ones
+	if n := names(f.Search("sum")); len(n) == 0 || n[0] != "calculator.Sum" {
+		t.Errorf("Should be calculator.Sum first got %v", n)
+	}
+	if n := names(f.Search("zzz")); len(n) != 0 {
+		t.Errorf("Should be empty got %v", n)
+	}
+}
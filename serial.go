@@ -0,0 +1,148 @@
+package funcutil
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// worker runs the calls of a serialized receiver one at a time on its goroutine
+type worker struct {
+	sync.Mutex
+	queue  []func()
+	wake   chan struct{}
+	closed bool
+}
+
+func newWorker() *worker {
+	w := &worker{wake: make(chan struct{}, 1)}
+	go w.loop()
+	return w
+}
+
+// loop runs the queued jobs in order, it returns once the worker is closed and the
+// jobs queued before are done
+func (w *worker) loop() {
+	for range w.wake {
+		for {
+			w.Lock()
+			if len(w.queue) == 0 {
+				closed := w.closed
+				w.Unlock()
+				if closed {
+					return
+				}
+				break
+			}
+			job := w.queue[0]
+			w.queue[0] = nil
+			w.queue = w.queue[1:]
+			w.Unlock()
+			job()
+		}
+	}
+}
+
+// post queues the job, it reports false when the worker is closed
+func (w *worker) post(job func()) bool {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return false
+	}
+	w.queue = append(w.queue, job)
+	w.signal()
+	return true
+}
+
+func (w *worker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run calls fn on the worker goroutine and waits for it, a panic of fn is raised again
+// in the caller. Without worker or once it is closed fn is called directly.
+func (w *worker) run(fn func()) {
+	if w == nil {
+		fn()
+		return
+	}
+	done := make(chan interface{}, 1)
+	job := func() {
+		defer func() {
+			done <- recover()
+		}()
+		fn()
+	}
+	if !w.post(job) {
+		fn()
+		return
+	}
+	if r := <-done; r != nil {
+		panic(r)
+	}
+}
+
+type workerKey struct{}
+
+// outside returns the worker unless ctx is of a job already running on it
+func (w *worker) outside(ctx context.Context) *worker {
+	if w != nil && ctx.Value(workerKey{}) == w {
+		return nil
+	}
+	return w
+}
+
+func (w *worker) close() {
+	if w != nil {
+		w.Lock()
+		defer w.Unlock()
+		w.closed = true
+		w.signal()
+	}
+}
+
+// Serialize queues all the calls of the methods registered with instance through a
+// single worker goroutine, so the instance never sees concurrent calls even through
+// the compiled plans. The methods must not call the other methods of the same
+// instance through the registry, the call would wait for itself.
+func (f *FuncUtil) Serialize(instance interface{}) error {
+	return f.setWorker(instance, newWorker())
+}
+
+// Unserialize stops the worker of the instance started by Serialize
+func (f *FuncUtil) Unserialize(instance interface{}) error {
+	return f.setWorker(instance, nil)
+}
+
+func (f *FuncUtil) setWorker(instance interface{}, w *worker) error {
+	v := reflect.ValueOf(instance)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		w.close()
+		return ErrNotStructPointer
+	}
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		w.close()
+		return err
+	}
+	found := false
+	for name, ci := range f.calls {
+		if !ci.v.IsValid() || ci.factory != nil || ci.v.Type() != v.Type() || ci.v.Pointer() != v.Pointer() {
+			continue
+		}
+		// the methods of the instance share the same worker
+		ci.worker.close()
+		ci.worker = w
+		f.calls[name] = ci
+		found = true
+	}
+	if !found {
+		w.close()
+		return ErrNotRegistered
+	}
+	return nil
+}
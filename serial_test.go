@@ -0,0 +1,79 @@
+package funcutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type unguarded struct {
+	active int32
+	peak   int32
+	count  int
+}
+
+func (u *unguarded) Touch() {
+	n := atomic.AddInt32(&u.active, 1)
+	if n > atomic.LoadInt32(&u.peak) {
+		atomic.StoreInt32(&u.peak, n)
+	}
+	time.Sleep(time.Millisecond)
+	u.count++
+	atomic.AddInt32(&u.active, -1)
+}
+
+func (u *unguarded) Count() int {
+	return u.count
+}
+
+func (u *unguarded) Fail() {
+	panic("failed")
+}
+
+func TestSerialize(t *testing.T) {
+	f := New()
+	u := &unguarded{}
+	f.Register(u)
+	if err := f.Serialize(u); err != nil {
+		t.Fatal(err)
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			touch, _ := f.Bind("unguarded.Touch")
+			for j := 0; j < 5; j++ {
+				touch.Invoke()
+			}
+		}()
+	}
+	wg.Wait()
+	if u.peak != 1 {
+		t.Errorf("Should be 1 concurrent call got %d", u.peak)
+	}
+	if rets, err := f.Call("unguarded.Count"); err != nil || rets[0] != 40 {
+		t.Errorf("Should be 40 got %v %v", rets, err)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != "failed" {
+				t.Errorf("Should panic with failed got %v", r)
+			}
+		}()
+		f.Call("unguarded.Fail")
+	}()
+	if err := f.Unserialize(u); err != nil {
+		t.Error(err)
+	}
+	if rets, err := f.Call("unguarded.Count"); err != nil || rets[0] != 40 {
+		t.Errorf("Should be 40 got %v %v", rets, err)
+	}
+	if err := f.Serialize(&unguarded{}); err != ErrNotRegistered {
+		t.Errorf("should failed due to unregistered instance got %v", err)
+	}
+	if err := f.Serialize(unguarded{}); err != ErrNotStructPointer {
+		t.Errorf("should failed due to non pointer got %v", err)
+	}
+}
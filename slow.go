@@ -0,0 +1,34 @@
+package funcutil
+
+import "time"
+
+// SlowCallFunc is notified of the calls exceeding the latency budget
+type SlowCallFunc func(methodName string, d time.Duration, params []interface{})
+
+type slowCall struct {
+	threshold time.Duration
+	fn        SlowCallFunc
+}
+
+// OnSlowCall calls fn after every call taking longer than threshold, e.g. to log or alert
+// when a method exceeds its latency budget. The duration includes the hooks and the
+// conversion of the params, nil fn disables the notification.
+//
+//	f.OnSlowCall(time.Second, func(name string, d time.Duration, params []interface{}) {
+//		log.Printf("slow call %s took %v", name, d)
+//	})
+func (f *FuncUtil) OnSlowCall(threshold time.Duration, fn SlowCallFunc) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.slow = slowCall{threshold: threshold, fn: fn}
+	return nil
+}
+
+func (s slowCall) check(methodName string, start time.Time, params []interface{}) {
+	if d := time.Since(start); d > s.threshold {
+		s.fn(methodName, d, params)
+	}
+}
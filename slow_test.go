@@ -0,0 +1,42 @@
+package funcutil
+
+import (
+	"testing"
+	"time"
+)
+
+type sleeper struct{}
+
+func (s *sleeper) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func TestOnSlowCall(t *testing.T) {
+	f := New()
+	f.Register(&sleeper{})
+	slow := []string{}
+	f.OnSlowCall(10*time.Millisecond, func(name string, d time.Duration, params []interface{}) {
+		if d < 10*time.Millisecond || len(params) != 1 {
+			t.Errorf("Should be longer than 10ms with 1 param got %v %v", d, params)
+		}
+		slow = append(slow, name)
+	})
+	f.Call("sleeper.Sleep", time.Millisecond)
+	if len(slow) != 0 {
+		t.Errorf("Should be no slow calls got %v", slow)
+	}
+	f.Call("sleeper.Sleep", 20*time.Millisecond)
+	if len(slow) != 1 || slow[0] != "sleeper.Sleep" {
+		t.Errorf("Should be sleeper.Sleep got %v", slow)
+	}
+	plan, _ := f.Compile("sleeper.Sleep")
+	plan.Invoke(20 * time.Millisecond)
+	if len(slow) != 2 {
+		t.Errorf("Should be 2 slow calls got %v", slow)
+	}
+	f.OnSlowCall(0, nil)
+	f.Call("sleeper.Sleep", 20*time.Millisecond)
+	if len(slow) != 2 {
+		t.Errorf("Should be 2 slow calls got %v", slow)
+	}
+}
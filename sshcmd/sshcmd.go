@@ -0,0 +1,100 @@
+// Package sshcmd runs the commands of a funcutil registry over SSH sessions, for the
+// remote administration of the registered services.
+//
+// A session with a command runs that command, an interactive session reads the
+// commands line by line until exit or quit. The commands and their replies follow
+// the line protocol of funcutil.ServeControlSocket:
+//
+//	$ ssh -p 2222 admin@host 'service.Stop true'
+//	OK []
+//
+// The package doesn't depend on a specific SSH library, Session is implemented by
+// github.com/gliderlabs/ssh sessions:
+//
+//	h := sshcmd.NewHandler(f)
+//	ssh.ListenAndServe(":2222", func(s ssh.Session) { h.Serve(s) }, hostKeys, passwords)
+package sshcmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Session is an authenticated SSH session
+type Session interface {
+	io.ReadWriter
+	// User is the name of the authenticated user
+	User() string
+	// RawCommand is the command given to ssh, empty for the interactive sessions
+	RawCommand() string
+	Exit(code int) error
+}
+
+// Handler runs the commands of the sessions
+type Handler struct {
+	f *funcutil.FuncUtil
+	// Prompt is written before reading the commands of the interactive sessions
+	Prompt string
+}
+
+// NewHandler creates the handler of the registry commands, the calls are authorized
+// by the registry, see funcutil.FuncUtil.SetAuthorizer
+func NewHandler(f *funcutil.FuncUtil) *Handler {
+	return &Handler{f: f, Prompt: "> "}
+}
+
+// Serve runs the commands of the session, it exits with 1 when the command of a
+// non interactive session fails
+func (h *Handler) Serve(s Session) {
+	if cmd := s.RawCommand(); cmd != "" {
+		reply := h.Exec(s.User(), cmd)
+		fmt.Fprintln(s, reply)
+		if strings.HasPrefix(reply, "ERR") {
+			s.Exit(1)
+			return
+		}
+		s.Exit(0)
+		return
+	}
+	scanner := bufio.NewScanner(s)
+	for {
+		io.WriteString(s, h.Prompt)
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case "exit", "quit":
+			s.Exit(0)
+			return
+		}
+		fmt.Fprintln(s, h.Exec(s.User(), line))
+	}
+	s.Exit(0)
+}
+
+// Exec runs the command for the user and returns its reply. The user name is the
+// credential given to the authenticator of the registry, see
+// funcutil.FuncUtil.Authenticate, without authenticator the method is called with
+// the identity named after the user.
+func (h *Handler) Exec(user, line string) string {
+	name, params, err := funcutil.ParseCommand(line)
+	if err != nil {
+		return funcutil.ReplyLine(nil, err)
+	}
+	ctx, err := h.f.Authenticate(context.Background(), user)
+	if err != nil {
+		return funcutil.ReplyLine(nil, err)
+	}
+	if _, ok := funcutil.IdentityFromContext(ctx); !ok {
+		ctx = funcutil.ContextWithIdentity(ctx, funcutil.Identity{Name: user})
+	}
+	return funcutil.ReplyLine(h.f.CallContext(ctx, name, params...))
+}
@@ -0,0 +1,111 @@
+package sshcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type session struct {
+	*strings.Reader
+	bytes.Buffer
+	user, cmd string
+	code      int
+}
+
+func (s *session) Read(p []byte) (int, error) {
+	return s.Reader.Read(p)
+}
+
+func (s *session) Write(p []byte) (int, error) {
+	return s.Buffer.Write(p)
+}
+
+func (s *session) User() string {
+	return s.user
+}
+
+func (s *session) RawCommand() string {
+	return s.cmd
+}
+
+func (s *session) Exit(code int) error {
+	s.code = code
+	return nil
+}
+
+type counter struct {
+	n int
+}
+
+func (c *counter) Add(n int) int {
+	c.n += n
+	return c.n
+}
+
+func (c *counter) Reset() {
+	c.n = 0
+}
+
+func TestHandler(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&counter{})
+	f.SetAuthorizer(func(ctx context.Context, id funcutil.Identity, methodName string) error {
+		if methodName == "counter.Reset" && id.Name != "admin" {
+			return errors.New("Forbidden")
+		}
+		return nil
+	})
+	h := NewHandler(f)
+
+	s := &session{Reader: strings.NewReader(""), user: "bob", cmd: "counter.Add 2"}
+	h.Serve(s)
+	if s.String() != "OK [2]\n" || s.code != 0 {
+		t.Errorf("Should be OK [2] got %q %d", s.String(), s.code)
+	}
+	s = &session{Reader: strings.NewReader(""), user: "bob", cmd: "counter.Reset"}
+	h.Serve(s)
+	if s.String() != "ERR Forbidden\n" || s.code != 1 {
+		t.Errorf("Should be ERR Forbidden got %q %d", s.String(), s.code)
+	}
+
+	s = &session{Reader: strings.NewReader("counter.Add 3\n\ncounter.Reset\nexit\ncounter.Add 1\n"), user: "admin"}
+	h.Serve(s)
+	if expected := "> OK [5]\n> > OK []\n> "; s.String() != expected || s.code != 0 {
+		t.Errorf("Should be %q got %q %d", expected, s.String(), s.code)
+	}
+}
+
+func TestCaller(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&counter{})
+	buf := &bytes.Buffer{}
+	f.WithAuditLogger(buf)
+	NewHandler(f).Exec("alice", "counter.Add 1")
+	if !strings.Contains(buf.String(), `"alice"`) {
+		t.Errorf("Should be audited as alice got %s", buf)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&counter{})
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, user string) (funcutil.Identity, error) {
+		if user == "admin" {
+			return funcutil.Identity{Name: user, Roles: []string{"admin"}}, nil
+		}
+		return funcutil.Identity{Name: user}, nil
+	}))
+	f.Require("counter.Reset", "admin")
+	h := NewHandler(f)
+	if reply := h.Exec("admin", "counter.Reset"); reply != "OK []" {
+		t.Errorf("Should be OK [] got %s", reply)
+	}
+	if reply := h.Exec("bob", "counter.Reset"); !strings.HasPrefix(reply, "ERR "+funcutil.ErrForbidden.Error()) {
+		t.Errorf("should failed due to missing role got %s", reply)
+	}
+}
@@ -0,0 +1,67 @@
+package funcutil
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+var (
+	ErrNotStream = errors.New("Method doesn't return a channel")
+)
+
+// CallStream invokes a method returning a channel and returns a channel receiving
+// its values, see CallStreamContext
+func (f *FuncUtil) CallStream(methodName string, params ...interface{}) (<-chan interface{}, error) {
+	return f.CallStreamContext(context.Background(), methodName, params...)
+}
+
+// CallStreamContext invokes a method returning a channel, optionally along with an
+// error, and returns a channel receiving its values. The returned channel is closed
+// when the method channel is closed or ctx is done, the method receives ctx when it
+// takes a context, see CallContext.
+//
+//	// func (t *ticker) Ticks(ctx context.Context, n int) <-chan int
+//	ticks, err := f.CallStreamContext(ctx, "ticker.Ticks", 10)
+//	for tick := range ticks {
+//	}
+func (f *FuncUtil) CallStreamContext(ctx context.Context, methodName string, params ...interface{}) (<-chan interface{}, error) {
+	ci, opts, err := f.resolve(methodName)
+	if err != nil {
+		return nil, opts.mapError(methodName, err)
+	}
+	if len(ci.retTypes) == 0 || ci.retTypes[0].Kind() != reflect.Chan || ci.retTypes[0].ChanDir()&reflect.RecvDir == 0 {
+		return nil, opts.mapError(methodName, ErrNotStream)
+	}
+	rets, err := opts.call(ctx, ci, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := resultError(rets); err != nil {
+		return nil, err
+	}
+	src := reflect.ValueOf(rets[0])
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		if !src.IsValid() || src.IsNil() {
+			return
+		}
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: src},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			chosen, v, ok := reflect.Select(cases)
+			if chosen == 1 || !ok {
+				return
+			}
+			select {
+			case out <- v.Interface():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
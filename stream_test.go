@@ -0,0 +1,62 @@
+package funcutil
+
+import (
+	"context"
+	"testing"
+)
+
+type ticker struct {
+}
+
+func (t *ticker) Ticks(ctx context.Context, n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; i < n; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (t *ticker) Count() int {
+	return 0
+}
+
+func TestCallStream(t *testing.T) {
+	f := New()
+	f.Register(&ticker{})
+	ticks, err := f.CallStream("ticker.Ticks", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []interface{}{}
+	for v := range ticks {
+		values = append(values, v)
+	}
+	if len(values) != 3 || values[2] != 2 {
+		t.Errorf("Unexpected values %v", values)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks, err = f.CallStreamContext(ctx, "ticker.Ticks", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ticks
+	cancel()
+	n := 0
+	for range ticks {
+		n++
+	}
+	if n >= 999 {
+		t.Errorf("Stream should be cancelled got %d values", n)
+	}
+	if _, err := f.CallStream("ticker.Count"); err != ErrNotStream {
+		t.Error("should failed due to non channel result")
+	}
+}
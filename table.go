@@ -0,0 +1,126 @@
+package funcutil
+
+import (
+	"strings"
+)
+
+// table is the copy of the registered methods and the options read by the calls
+// without locking. It is published at the first call after a change, so the
+// registrations never wait for the calls in flight and the other way around.
+type table struct {
+	calls map[string]callInfo
+	// names maps the unversioned and aliased names to the registered ones
+	names map[string]string
+	opts  options
+	// lazy is set while lazy registrations are pending, the unknown names may be theirs
+	lazy bool
+}
+
+// published returns the current table
+func (f *FuncUtil) published() *table {
+	if t := f.table.Load(); t != nil {
+		return t
+	}
+	// the changes wait for the copy, so it can't be stale
+	f.RLock()
+	defer f.RUnlock()
+	t := &table{
+		calls: copyCalls(f.calls),
+		names: f.resolvedNames(),
+		opts:  f.opts,
+		lazy:  len(f.lazy) > 0,
+	}
+	f.table.Store(t)
+	return t
+}
+
+// lookup is FuncUtil.lookup for the published names
+func (t *table) lookup(methodName string) (callInfo, bool) {
+	if ci, exists := t.calls[methodName]; exists {
+		return ci, true
+	}
+	if name, exists := t.names[methodName]; exists {
+		return t.calls[name], true
+	}
+	return callInfo{}, false
+}
+
+// resolvedNames maps the names resolved by lookup without being registered, i.e. the
+// unversioned and aliased names, to the registered ones. The caller must hold the lock.
+func (f *FuncUtil) resolvedNames() map[string]string {
+	names := map[string]string{}
+	versions := map[string][]string{}
+	for name := range f.calls {
+		if base, version := splitVersion(name); version != "" {
+			versions[base] = append(versions[base], version)
+		}
+	}
+	for base := range f.defaultVersions {
+		if _, exists := versions[base]; !exists {
+			versions[base] = nil
+		}
+	}
+	for base, vs := range versions {
+		if _, exists := f.calls[base]; exists {
+			continue
+		}
+		if name, exists := f.pickVersion(base, vs); exists {
+			names[base] = name
+		}
+	}
+	if len(f.aliases) == 0 {
+		return names
+	}
+	targets := make(map[string]string, len(f.calls)+len(names))
+	for name := range f.calls {
+		targets[name] = name
+	}
+	for base, name := range names {
+		targets[base] = name
+	}
+	for alias, ns := range f.aliases {
+		for target, name := range targets {
+			if !strings.HasPrefix(target, ns+".") {
+				continue
+			}
+			aliased := alias + target[len(ns):]
+			if _, exists := targets[aliased]; exists || f.pendingLazy(aliased) {
+				continue
+			}
+			// the longest alias wins
+			if unaliased, _ := f.unalias(aliased); unaliased == target {
+				names[aliased] = name
+			}
+		}
+	}
+	return names
+}
+
+// pendingLazy tells whether the name is of a lazy registration not made yet
+func (f *FuncUtil) pendingLazy(methodName string) bool {
+	i := strings.LastIndex(methodName, ".")
+	if i < 0 {
+		return false
+	}
+	_, exists := f.lazy[methodName[:i]]
+	return exists
+}
+
+// change prepares a change of the registry, it fails on the frozen registries and
+// drops the published table. The caller must hold the lock.
+func (f *FuncUtil) change() error {
+	if f.frozen.Load() {
+		return ErrFrozen
+	}
+	f.table.Store(nil)
+	return nil
+}
+
+// store updates the registered method. The caller must hold the lock.
+func (f *FuncUtil) store(ci callInfo) error {
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.calls[ci.name] = ci
+	return nil
+}
@@ -0,0 +1,108 @@
+package funcutil
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublishedTable(t *testing.T) {
+	f := New()
+	f.Register(&Monitor{})
+	if _, err := f.Call("Monitor.Display"); err != nil {
+		t.Fatal(err)
+	}
+	// the changes are visible to the next calls
+	f.Register(&service{})
+	if _, err := f.Call("service.Stop", true); err != nil {
+		t.Error(err)
+	}
+	f.Deprecate("service.Stop", "use Pause")
+	if info, _ := f.Info("service.Stop"); !info.Deprecated {
+		t.Error("Should be deprecated")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := f.Call("service.Running"); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			f.RegisterMap(map[string]interface{}{
+				fmt.Sprintf("fn%d", i): func() int { return i },
+			})
+		}(i)
+	}
+	wg.Wait()
+	if rets, err := f.Call("fn3"); err != nil || rets[0] != 3 {
+		t.Errorf("Should be 3 got %v %v", rets, err)
+	}
+	called := false
+	f.OnBeforeCall(func(name string, params []interface{}) error {
+		called = true
+		return nil
+	})
+	f.Call("Monitor.Display")
+	if !called {
+		t.Error("Should run the hook")
+	}
+
+}
+
+func BenchmarkCallParallel(b *testing.B) {
+	f := New()
+	f.Register(&Arith{})
+	b.RunParallel(func(pb *testing.PB) {
+		reply := 0
+		args := &Args{7, 8}
+		for pb.Next() {
+			f.Call("Arith.Multiply", args, &reply)
+		}
+	})
+}
+
+func TestPublishedNames(t *testing.T) {
+	f := New("com.example")
+	f.Register(&service{})
+	f.RegisterVersion("v2", &reporter{"v2"})
+	f.RegisterVersion("v10", &reporter{"v10"})
+	f.AliasNamespace("ex", "com.example")
+	f.Call("com.example.service.Running")
+
+	// the published names are resolved without the registry lock
+	f.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for name, version := range map[string]string{
+			"com.example.reporter.Format": "v10",
+			"ex.reporter.Format":          "v10",
+			"ex.reporter.Format@v2":       "v2",
+		} {
+			if rets, err := f.Call(name); err != nil || rets[0] != version {
+				t.Errorf("%s: Should be %s got %v %v", name, version, rets, err)
+			}
+		}
+		if _, err := f.Call("ex.service.Stop", true); err != nil {
+			t.Error(err)
+		}
+		if _, err := f.Call("ex.service.NotExists"); err != ErrMethodNotFound {
+			t.Errorf("method should not exists got %v", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("the names should be resolved without locking")
+	}
+	f.Unlock()
+	<-done
+}
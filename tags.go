@@ -0,0 +1,55 @@
+package funcutil
+
+// Tag attaches the tags to the method, e.g. to call every method tagged shutdown
+// with CallTagged
+//
+//	f.Tag("cache.Flush", "shutdown")
+func (f *FuncUtil) Tag(methodName string, tags ...string) error {
+	f.Lock()
+	defer f.Unlock()
+	ci, err := f.lookup(methodName)
+	if err != nil {
+		return err
+	}
+	// don't share the tags with the copies of the registry
+	merged := append([]string{}, ci.tags...)
+	for _, tag := range tags {
+		if !ci.hasTag(tag) {
+			merged = append(merged, tag)
+		}
+	}
+	ci.tags = merged
+	return f.store(ci)
+}
+
+// ByTag returns the descriptions of the methods tagged with tag sorted by name
+func (f *FuncUtil) ByTag(tag string) []MethodInfo {
+	methods := []MethodInfo{}
+	f.Range(func(name string, mi MethodInfo) bool {
+		for _, t := range mi.Tags {
+			if t == tag {
+				methods = append(methods, mi)
+				break
+			}
+		}
+		return true
+	})
+	return methods
+}
+
+// CallTagged invokes every method tagged with tag and accepting the params in name
+// order, like CallAll
+func (f *FuncUtil) CallTagged(tag string, params ...interface{}) (map[string][]interface{}, error) {
+	return f.broadcast(func(name string, ci callInfo) bool {
+		return ci.hasTag(tag)
+	}, params)
+}
+
+func (mi *callInfo) hasTag(tag string) bool {
+	for _, t := range mi.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
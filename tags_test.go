@@ -0,0 +1,38 @@
+package funcutil
+
+import "testing"
+
+func TestTag(t *testing.T) {
+	f := New()
+	f.Register(&service{})
+	f.Register(&Monitor{})
+	if err := f.Tag("service.Run", "lifecycle"); err != nil {
+		t.Fatal(err)
+	}
+	f.Tag("service.Stop", "lifecycle", "shutdown-hook")
+	f.Tag("service.Stop", "shutdown-hook")
+	if mi, _ := f.Info("service.Stop"); len(mi.Tags) != 2 {
+		t.Errorf("Should be 2 tags got %v", mi.Tags)
+	}
+	tagged := f.ByTag("lifecycle")
+	if len(tagged) != 2 || tagged[0].Name != "service.Run" || tagged[1].Name != "service.Stop" {
+		t.Errorf("Should be service.Run and service.Stop got %v", tagged)
+	}
+	if len(f.ByTag("unknown")) != 0 {
+		t.Error("Should be no methods")
+	}
+	results, err := f.CallTagged("shutdown-hook", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := results["service.Stop"]; !exists || len(results) != 1 {
+		t.Errorf("Should be service.Stop got %v", results)
+	}
+	// methods not accepting the params are skipped
+	if results, _ := f.CallTagged("lifecycle"); len(results) != 1 {
+		t.Errorf("Should be 1 result got %v", results)
+	}
+	if err := f.Tag("service.NotExists", "lifecycle"); err != ErrMethodNotFound {
+		t.Error("method should not exists")
+	}
+}
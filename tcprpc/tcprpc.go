@@ -0,0 +1,133 @@
+// Package tcprpc makes a funcutil registry callable over TCP, or any stream
+// connection, with a client mirroring the registry API.
+//
+// The requests and responses are the call envelopes of funcutil, prefixed by their
+// size and encoded with gob unless both sides use SetEncoding:
+//
+//	go tcprpc.Serve(listener, f)
+//
+//	c, err := tcprpc.Dial("tcp", "localhost:4000")
+//	rets, err := c.Call("service.Stop", true)
+package tcprpc
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+// Server serves the registry calls received by the connections
+type Server struct {
+	sync.Mutex
+	f        *funcutil.FuncUtil
+	encoding funcutil.EnvelopeEncoding
+}
+
+// NewServer creates the server of the registry
+func NewServer(f *funcutil.FuncUtil) *Server {
+	return &Server{f: f, encoding: funcutil.EnvelopeGob}
+}
+
+// Serve accepts the connections of the listener and serves them with the default server
+func Serve(l net.Listener, f *funcutil.FuncUtil) error {
+	return NewServer(f).Serve(l)
+}
+
+// SetEncoding sets the encoding of the envelopes, it applies to the next connections
+func (s *Server) SetEncoding(e funcutil.EnvelopeEncoding) {
+	s.Lock()
+	defer s.Unlock()
+	s.encoding = e
+}
+
+// Serve accepts the connections of the listener and serves each one on its goroutine,
+// it returns the error stopping the listener
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn serves the calls of the connection in order until it is closed
+func (s *Server) ServeConn(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	s.Lock()
+	e := s.encoding
+	s.Unlock()
+	r := bufio.NewReader(conn)
+	for {
+		if err := s.f.DecodeAndCall(r, conn, e); err != nil {
+			return
+		}
+	}
+}
+
+// Client calls the methods of a remote registry, the calls of a client are sent one
+// at a time
+type Client struct {
+	sync.Mutex
+	conn       io.ReadWriteCloser
+	r          *bufio.Reader
+	encoding   funcutil.EnvelopeEncoding
+	credential string
+}
+
+// NewClient creates the client of the registry served on the connection
+func NewClient(conn io.ReadWriteCloser) *Client {
+	return &Client{conn: conn, r: bufio.NewReader(conn), encoding: funcutil.EnvelopeGob}
+}
+
+// Dial connects to the server at the address, see net.Dial
+func Dial(network, address string) (*Client, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// SetEncoding sets the encoding of the envelopes, it must be the one of the server
+func (c *Client) SetEncoding(e funcutil.EnvelopeEncoding) {
+	c.Lock()
+	defer c.Unlock()
+	c.encoding = e
+}
+
+// SetCredential sets the credential sent with the calls, see funcutil.FuncUtil.Authenticate
+func (c *Client) SetCredential(credential string) {
+	c.Lock()
+	defer c.Unlock()
+	c.credential = credential
+}
+
+// Call invokes the remote method like funcutil.FuncUtil.Call, the errors returned by the
+// method are received as their message
+func (c *Client) Call(methodName string, params ...interface{}) ([]interface{}, error) {
+	c.Lock()
+	defer c.Unlock()
+	req := funcutil.CallRequest{Method: methodName, Params: params, Credential: c.credential}
+	if err := funcutil.EncodeRequest(c.conn, c.encoding, req); err != nil {
+		return nil, err
+	}
+	resp, err := funcutil.DecodeResponse(c.r, c.encoding)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Results, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,89 @@
+package tcprpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/kadekcipta/funcutil"
+)
+
+type calculator struct{}
+
+func (c *calculator) Add(a, b int) int {
+	return a + b
+}
+
+func (c *calculator) Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+func TestServe(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer l.Close()
+	go Serve(l, f)
+
+	c, err := Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 3; i++ {
+		if rets, err := c.Call("calculator.Add", i, 2); err != nil || rets[0] != i+2 {
+			t.Errorf("Should be %d got %v %v", i+2, rets, err)
+		}
+	}
+	if rets, err := c.Call("calculator.Div", 1, 0); err != nil || rets[1] != "division by zero" {
+		t.Errorf("Should be division by zero got %v %v", rets, err)
+	}
+	if _, err := c.Call("calculator.NotExists"); err == nil || err.Error() != funcutil.ErrMethodNotFound.Error() {
+		t.Errorf("method should not exists got %v", err)
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	server, client := net.Pipe()
+	s := NewServer(f)
+	s.SetEncoding(funcutil.EnvelopeJSON)
+	go s.ServeConn(server)
+
+	c := NewClient(client)
+	c.SetEncoding(funcutil.EnvelopeJSON)
+	defer c.Close()
+	if rets, err := c.Call("calculator.Add", 40, 2); err != nil || rets[0] != 42.0 {
+		t.Errorf("Should be 42 got %v %v", rets, err)
+	}
+}
+
+func TestCredential(t *testing.T) {
+	f := funcutil.New()
+	f.Register(&calculator{})
+	f.SetAuthenticator(funcutil.AuthenticatorFunc(func(ctx context.Context, credential string) (funcutil.Identity, error) {
+		if credential != "secret" {
+			return funcutil.Identity{}, funcutil.ErrUnauthenticated
+		}
+		return funcutil.Identity{Name: "alice"}, nil
+	}))
+	server, client := net.Pipe()
+	go NewServer(f).ServeConn(server)
+	c := NewClient(client)
+	defer c.Close()
+	if _, err := c.Call("calculator.Add", 1, 2); err == nil || err.Error() != funcutil.ErrUnauthenticated.Error() {
+		t.Errorf("should failed due to missing credential got %v", err)
+	}
+	c.SetCredential("secret")
+	if rets, err := c.Call("calculator.Add", 1, 2); err != nil || rets[0] != 3 {
+		t.Errorf("Should be 3 got %v %v", rets, err)
+	}
+}
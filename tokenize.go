@@ -0,0 +1,125 @@
+package funcutil
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnbalancedQuotes = errors.New("Unbalanced quotes")
+	ErrEmptyCommand     = errors.New("Empty command")
+)
+
+type token struct {
+	text string
+	// quoted tells whether any part of the word was quoted
+	quoted bool
+}
+
+// tokenize splits the line into words like a POSIX shell
+func tokenize(line string) ([]token, error) {
+	tokens := []token{}
+	var word strings.Builder
+	var quote rune
+	inWord, quoted, escaped := false, false, false
+	for _, c := range line {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			inWord, escaped = true, true
+		case quote == '"':
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			inWord, quoted, quote = true, true, c
+		case unicode.IsSpace(c):
+			if inWord {
+				tokens = append(tokens, token{word.String(), quoted})
+				word.Reset()
+			}
+			inWord, quoted = false, false
+		default:
+			inWord = true
+			word.WriteRune(c)
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrUnbalancedQuotes
+	}
+	if inWord {
+		tokens = append(tokens, token{word.String(), quoted})
+	}
+	return tokens, nil
+}
+
+// Tokenize splits the command line into words like a POSIX shell: the words are
+// separated by spaces, single quotes keep their content as is, double quotes keep
+// the spaces and a backslash escapes the next character outside single quotes
+//
+//	Tokenize(`service.SetHello "hello world" 42`) // ["service.SetHello", "hello world", "42"]
+func Tokenize(line string) ([]string, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.text
+	}
+	return words, nil
+}
+
+// ParseCommand returns the method name and the params of the command line, the first
+// word is the method name. The unquoted words holding a literal, see Eval, are
+// passed as their values and the other words as strings.
+//
+//	ParseCommand(`service.SetHello "hello world" 42 true`) // "service.SetHello", ["hello world", 42, true]
+func ParseCommand(line string) (string, []interface{}, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tokens) == 0 {
+		return "", nil, ErrEmptyCommand
+	}
+	params := make([]interface{}, 0, len(tokens)-1)
+	for _, t := range tokens[1:] {
+		params = append(params, wordValue(t))
+	}
+	return tokens[0].text, params, nil
+}
+
+// wordValue returns the value of the literal held by the unquoted word, the word otherwise
+func wordValue(t token) interface{} {
+	if t.quoted {
+		return t.text
+	}
+	p := &exprParser{s: t.text}
+	if v, err := p.value(); err == nil && p.pos == len(t.text) {
+		return v
+	}
+	return t.text
+}
+
+// Exec parses the command line and invokes the method like Call, see ParseCommand
+//
+//	f.Exec(`service.SetHello "hello world"`)
+func (f *FuncUtil) Exec(line string) ([]interface{}, error) {
+	name, params, err := ParseCommand(line)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(name, params...)
+}
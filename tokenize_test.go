@@ -0,0 +1,41 @@
+package funcutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	words, err := Tokenize(`  service.SetHello "hello world" 'it''s' a\ b "say \"hi\"" '\n' x"y z" `)
+	expected := []string{"service.SetHello", "hello world", "its", "a b", `say "hi"`, `\n`, "xy z"}
+	if err != nil || !reflect.DeepEqual(words, expected) {
+		t.Errorf("Unexpected %q %v", words, err)
+	}
+	for _, line := range []string{`a "b`, `a 'b`, `a\`} {
+		if _, err := Tokenize(line); err != ErrUnbalancedQuotes {
+			t.Errorf("%s should failed due to unbalanced quotes got %v", line, err)
+		}
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	name, params, err := ParseCommand(`service.SetHello "hello world" 42 true "42" -1.5 [1,2] null plain`)
+	expected := []interface{}{"hello world", 42, true, "42", -1.5, []interface{}{1, 2}, nil, "plain"}
+	if err != nil || name != "service.SetHello" || !reflect.DeepEqual(params, expected) {
+		t.Errorf("Unexpected %s %#v %v", name, params, err)
+	}
+	if _, _, err := ParseCommand("  "); err != ErrEmptyCommand {
+		t.Errorf("should failed due to empty command got %v", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	f := New()
+	f.Register(&calculator{}, &text{})
+	if rets, err := f.Exec(`calculator.Add 40 2`); err != nil || rets[0] != int64(42) {
+		t.Errorf("Should be 42 got %v %v", rets, err)
+	}
+	if rets, err := f.Exec(`text.Split "a b  c"`); err != nil || len(rets[0].([]string)) != 3 {
+		t.Errorf("Should be 3 words got %v %v", rets, err)
+	}
+}
@@ -0,0 +1,156 @@
+package funcutil
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validator validates the struct parameters before the method is invoked
+type Validator interface {
+	Validate(v interface{}) error
+}
+
+// FieldError describes a struct field failing a validation rule
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+func (e FieldError) String() string {
+	if e.Param == "" {
+		return fmt.Sprintf("%s %s", e.Field, e.Rule)
+	}
+	return fmt.Sprintf("%s %s=%s", e.Field, e.Rule, e.Param)
+}
+
+// ValidationError lists all the failing fields of a struct parameter
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := []string{}
+	for _, fe := range e.Fields {
+		fields = append(fields, fe.String())
+	}
+	return "validation failed: " + strings.Join(fields, ", ")
+}
+
+// TagValidator is the default Validator which honors the validate struct tags.
+// Supported rules are required, min=N and max=N, where min and max apply to
+// numbers by value and to strings, slices and maps by length.
+//
+//	type User struct {
+//		Name string `validate:"required,max=32"`
+//		Age  int    `validate:"min=1"`
+//	}
+type TagValidator struct {
+}
+
+func (tv TagValidator) Validate(v interface{}) error {
+	e := &ValidationError{}
+	tv.validate(reflect.ValueOf(v), "", e)
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+func (tv TagValidator) validate(v reflect.Value, prefix string, e *ValidationError) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := prefix + sf.Name
+		fv := v.Field(i)
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			for _, rule := range strings.Split(tag, ",") {
+				kv := strings.SplitN(rule, "=", 2)
+				param := ""
+				if len(kv) > 1 {
+					param = kv[1]
+				}
+				if !checkRule(fv, kv[0], param) {
+					e.Fields = append(e.Fields, FieldError{Field: name, Rule: kv[0], Param: param})
+				}
+			}
+		}
+		tv.validate(fv, name+".", e)
+	}
+}
+
+func checkRule(v reflect.Value, rule, param string) bool {
+	switch rule {
+	case "required":
+		return !v.IsZero()
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+		var n float64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = v.Float()
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			n = float64(v.Len())
+		default:
+			return true
+		}
+		if rule == "min" {
+			return n >= limit
+		}
+		return n <= limit
+	}
+	// unknown rules are left to custom validators
+	return true
+}
+
+// SetValidator replaces the validator of the struct parameters, nil disables the validation
+func (f *FuncUtil) SetValidator(v Validator) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.opts.validator = v
+	return nil
+}
+
+// validate runs the validator over the struct arguments
+func (o options) validate(args []reflect.Value) error {
+	if o.validator == nil {
+		return nil
+	}
+	for _, v := range args {
+		t := v.Type()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		if err := o.validator.Validate(v.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package funcutil
+
+import (
+	"errors"
+	"testing"
+)
+
+type Address struct {
+	City string `validate:"required"`
+}
+
+type User struct {
+	Name    string `validate:"required,max=8"`
+	Age     int    `validate:"min=1"`
+	Address Address
+}
+
+type registry struct {
+	users []User
+}
+
+func (r *registry) Add(u User) {
+	r.users = append(r.users, u)
+}
+
+func (r *registry) AddRef(u *User) {
+	r.users = append(r.users, *u)
+}
+
+func TestValidation(t *testing.T) {
+	f := New()
+	r := &registry{}
+	f.Register(r)
+	if _, err := f.Call("registry.Add", User{Name: "gopher", Age: 10, Address: Address{"Bali"}}); err != nil {
+		t.Error(err)
+	}
+	_, err := f.Call("registry.AddRef", &User{Name: "a long name"})
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Should be validation error got %v", err)
+	}
+	expect := "validation failed: Name max=8, Age min=1, Address.City required"
+	if ve.Error() != expect {
+		t.Errorf("Should be %s got %s", expect, ve.Error())
+	}
+	if len(r.users) != 1 {
+		t.Error("invalid user should not be added")
+	}
+	f.SetValidator(nil)
+	if _, err := f.Call("registry.Add", User{}); err != nil {
+		t.Error(err)
+	}
+}
+
+type rejectAll struct {
+}
+
+func (rejectAll) Validate(v interface{}) error {
+	return errors.New("rejected")
+}
+
+func TestCustomValidator(t *testing.T) {
+	f := New()
+	f.Register(&registry{})
+	f.SetValidator(rejectAll{})
+	if _, err := f.Call("registry.Add", User{Name: "gopher", Age: 10}); err == nil || err.Error() != "rejected" {
+		t.Errorf("Should be rejected got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package funcutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionPolicy picks the version called when the caller doesn't select one,
+// versions are the registered versions of the method
+type VersionPolicy func(methodName string, versions []string) string
+
+// LatestVersion picks the highest version, the numbers within the versions
+// are compared numerically so v10 is higher than v9
+func LatestVersion(methodName string, versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// RegisterVersion registers the methods of the structs as the given version,
+// e.g. service.Run@v1. The callers select the version with the same suffix,
+// or get the one picked by the version policy when they omit it.
+//
+//	f.RegisterVersion("v1", &serviceV1{})
+//	f.RegisterVersion("v2", &serviceV2{})
+//	f.Call("service.Run@v1")
+//	f.Call("service.Run") // calls v2, see SetVersionPolicy
+func (f *FuncUtil) RegisterVersion(version string, vars ...interface{}) error {
+	f.Lock()
+	defer f.Unlock()
+	for _, s := range vars {
+		if err := f.register(s, registration{ns: f.ns, version: version}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetVersionPolicy sets how the version is picked when the caller omits it,
+// LatestVersion by default. The version is picked again after every change of the
+// registry, not at every call.
+func (f *FuncUtil) SetVersionPolicy(policy VersionPolicy) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	f.versionPolicy = policy
+	return nil
+}
+
+// SetDefaultVersion pins the version of the method called when the caller omits it,
+// regardless the version policy. An empty version removes the pin.
+func (f *FuncUtil) SetDefaultVersion(methodName, version string) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.change(); err != nil {
+		return err
+	}
+	if version == "" {
+		delete(f.defaultVersions, methodName)
+		return nil
+	}
+	f.defaultVersions[methodName] = version
+	return nil
+}
+
+// splitVersion splits service.Run@v1 into service.Run and v1
+func splitVersion(name string) (string, string) {
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// resolveVersion returns the registered name of the version of the method to call.
+// The caller must hold the lock.
+func (f *FuncUtil) resolveVersion(methodName string) (string, bool) {
+	versions := []string{}
+	if _, pinned := f.defaultVersions[methodName]; !pinned {
+		for name := range f.calls {
+			if base, version := splitVersion(name); version != "" && base == methodName {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return f.pickVersion(methodName, versions)
+}
+
+// pickVersion returns the registered name of the version of the method to call among
+// its registered versions. The caller must hold the lock.
+func (f *FuncUtil) pickVersion(methodName string, versions []string) (string, bool) {
+	if version, pinned := f.defaultVersions[methodName]; pinned {
+		name := methodName + "@" + version
+		_, exists := f.calls[name]
+		return name, exists
+	}
+	if len(versions) == 0 {
+		return "", false
+	}
+	policy := f.versionPolicy
+	if policy == nil {
+		policy = LatestVersion
+	}
+	name := methodName + "@" + policy(methodName, versions)
+	_, exists := f.calls[name]
+	return name, exists
+}
+
+// compareVersions compares the versions chunk by chunk, numbers numerically
+func compareVersions(a, b string) int {
+	ca, cb := versionChunks(a), versionChunks(b)
+	for i := 0; i < len(ca) && i < len(cb); i++ {
+		na, errA := strconv.Atoi(ca[i])
+		nb, errB := strconv.Atoi(cb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case ca[i] != cb[i]:
+			if ca[i] < cb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(ca) - len(cb)
+}
+
+// versionChunks splits v2.10rc1 into v, 2, ., 10, rc, 1
+func versionChunks(v string) []string {
+	chunks := []string{}
+	start := 0
+	for i := 1; i <= len(v); i++ {
+		if i == len(v) || isDigit(v[i]) != isDigit(v[i-1]) {
+			chunks = append(chunks, v[start:i])
+			start = i
+		}
+	}
+	return chunks
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
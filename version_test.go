@@ -0,0 +1,81 @@
+package funcutil
+
+import "testing"
+
+type reporter struct {
+	format string
+}
+
+func (r *reporter) Format() string {
+	return r.format
+}
+
+func TestRegisterVersion(t *testing.T) {
+	f := New()
+	if err := f.RegisterVersion("v2", &reporter{"v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RegisterVersion("v10", &reporter{"v10"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RegisterVersion("v1", reporter{}); err != ErrNotStructPointer {
+		t.Error("should failed due to non pointer struct")
+	}
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"reporter.Format@v2", "v2"},
+		{"reporter.Format@v10", "v10"},
+		{"reporter.Format", "v10"},
+	}
+	for _, test := range tests {
+		rets, err := f.Call(test.name)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if rets[0] != test.expect {
+			t.Errorf("%s: Should be %s got %v", test.name, test.expect, rets[0])
+		}
+	}
+	if _, err := f.Call("reporter.Format@v3"); err != ErrMethodNotFound {
+		t.Error("should failed due to unknown version")
+	}
+
+	f.SetDefaultVersion("reporter.Format", "v2")
+	if rets, _ := f.Call("reporter.Format"); rets[0] != "v2" {
+		t.Errorf("Should be v2 got %v", rets[0])
+	}
+	f.SetDefaultVersion("reporter.Format", "")
+	f.SetVersionPolicy(func(name string, versions []string) string {
+		return "v2"
+	})
+	if rets, _ := f.Call("reporter.Format"); rets[0] != "v2" {
+		t.Errorf("Should be v2 got %v", rets[0])
+	}
+	if err := f.SetDoc("reporter.Format", "formats"); err != nil {
+		t.Error(err)
+	}
+	if mi, _ := f.Info("reporter.Format@v2"); mi.Version != "v2" || mi.Doc != "formats" {
+		t.Errorf("Unexpected info %+v", mi)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"v1", "v2", -1},
+		{"v10", "v9", 1},
+		{"1.2.0", "1.10", -1},
+		{"v2", "v2", 0},
+	}
+	for _, test := range tests {
+		c := compareVersions(test.a, test.b)
+		if (c < 0 && test.expect >= 0) || (c > 0 && test.expect <= 0) || (c == 0 && test.expect != 0) {
+			t.Errorf("%s %s: Should be %d got %d", test.a, test.b, test.expect, c)
+		}
+	}
+}